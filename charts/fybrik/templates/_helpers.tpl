@@ -163,3 +163,41 @@ passed as parameter to the function.
 {{- $dir := toString (first .) -}}
 {{- printf "%s/%s" (include "fybrik.getDataDir" .) $dir }}
 {{- end }}
+
+{{/*
+Environment variables that configure the export of OpenTelemetry trace spans
+*/}}
+{{- define "fybrik.tracingEnv" -}}
+- name: TRACING_EXPORTER
+  value: {{ .Values.global.tracing.exporter | default "none" | quote }}
+{{- if .Values.global.tracing.otlpEndpoint }}
+- name: OTEL_EXPORTER_OTLP_ENDPOINT
+  value: {{ .Values.global.tracing.otlpEndpoint | quote }}
+{{- end }}
+{{- if .Values.global.tracing.file }}
+- name: TRACING_FILE
+  value: {{ .Values.global.tracing.file | quote }}
+{{- end }}
+{{- end }}
+
+{{/*
+isKatalogWebhookEnabled checks if the katalog connector serves the validating webhook of the assets.
+The assets are validated against the taxonomy that is deployed with the manager.
+*/}}
+{{- define "fybrik.isKatalogWebhookEnabled" -}}
+{{- $withTaxonomy := include "fybrik.isEnabled" (tuple .Values.manager.enabled (or .Values.coordinator.enabled .Values.worker.enabled)) }}
+{{- if and $withTaxonomy .Values.clusterScoped .Values.katalogConnector.validation.webhook }}
+true
+{{- end -}}
+{{- end }}
+
+{{/*
+isKatalogAssetStatusEnabled checks if the katalog connector reports the status of the assets.
+The assets are validated against the taxonomy that is deployed with the manager.
+*/}}
+{{- define "fybrik.isKatalogAssetStatusEnabled" -}}
+{{- $withTaxonomy := include "fybrik.isEnabled" (tuple .Values.manager.enabled (or .Values.coordinator.enabled .Values.worker.enabled)) }}
+{{- if and $withTaxonomy .Values.katalogConnector.validation.status }}
+true
+{{- end -}}
+{{- end }}
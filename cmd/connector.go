@@ -0,0 +1,82 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"fybrik.io/fybrik/pkg/connectors/conformance"
+	"fybrik.io/fybrik/pkg/logging"
+	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+)
+
+const defaultConnectorTimeout = 30 * time.Second
+
+// connectorCmd groups the commands that work with connectors
+var connectorCmd = &cobra.Command{
+	Use:   "connector",
+	Short: "Commands for Fybrik connectors",
+}
+
+// connectorTestCmd runs the conformance test of a connector
+func connectorTestCmd() *cobra.Command {
+	config := &conformance.Config{}
+	var connectorType, caCertFile, certFile, keyFile string
+	timeout := defaultConnectorTimeout
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Test that a connector conforms to the connector API",
+		Long: `Calls every operation of the connector API in connectors/api/<type>.spec.yaml with valid and invalid
+payloads, checks the status codes and the error bodies, and validates the responses against the taxonomy schemas
+that the manager uses. The data catalog test creates, updates and deletes an asset in the given catalog.`,
+		Example:       "  fybrik connector test --type datacatalog --url http://localhost:8080 --catalog-id fybrik-notebook-sample",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			log := logging.LogInit(logging.SETUP, "connector-test")
+			tlsConfig, err := fybrikTLS.GetClientTLSConfigFromFiles(&log, caCertFile, certFile, keyFile)
+			if err != nil {
+				return errors.Wrap(err, "failed to get tls config")
+			}
+			config.HTTPClient = &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+			report, err := conformance.Run(context.Background(), connectorType, config)
+			if err != nil {
+				return err
+			}
+			if err = report.Print(cmd.OutOrStdout()); err != nil {
+				return err
+			}
+			if !report.Passed() {
+				return errors.New("the connector does not conform to the connector API")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&connectorType, "type", "", "Type of the connector: "+strings.Join(conformance.Types, ", "))
+	cmd.Flags().StringVar(&config.URL, "url", "", "URL of the connector")
+	cmd.Flags().StringVar(&config.TaxonomyDir, "taxonomy-dir", "charts/fybrik/files/taxonomy",
+		"Directory of the taxonomy schemas that the responses are validated against")
+	cmd.Flags().StringVar(&config.Credentials, "credentials", "", "Credentials that are sent in the credentials header")
+	cmd.Flags().StringVar(&config.CatalogID, "catalog-id", "default", "Catalog in which the data catalog test creates an asset")
+	cmd.Flags().StringVar(&config.AssetID, "asset-id", "fybrik-conformance/asset", "Asset of the policy manager requests")
+	cmd.Flags().StringVar(&caCertFile, "cacert", "", "CA certificate file to verify the connector certificate")
+	cmd.Flags().StringVar(&certFile, "cert", "", "Client certificate file")
+	cmd.Flags().StringVar(&keyFile, "key", "", "Client private key file")
+	cmd.Flags().DurationVar(&timeout, "timeout", timeout, "Timeout of a request to the connector")
+	_ = cmd.MarkFlagRequired("type")
+	_ = cmd.MarkFlagRequired("url")
+	return cmd
+}
+
+func init() {
+	connectorCmd.AddCommand(connectorTestCmd())
+	rootCmd.AddCommand(connectorCmd)
+}
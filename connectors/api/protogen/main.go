@@ -0,0 +1,682 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// protogen generates the protobuf definitions of the gRPC services of the connectors from the OpenAPI specifications
+// of their REST APIs and from the JSON schemas of the taxonomy, which are generated from the pkg/model types.
+//
+// A service has a method for each operation of the REST API, with the request and response objects of the operation
+// as messages. The properties of a taxonomy object that can be extended in the taxonomy layers (additionalProperties)
+// are kept in a google.protobuf.Struct field, and the JSON names of the fields are the property names of the schemas.
+// The numbers of the fields in existing definitions are kept, and the numbers of removed fields are reserved.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"emperror.dev/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	protoPackage   = "fybrik.connectors"
+	goPackage      = "fybrik.io/fybrik/pkg/connectors/protos"
+	structType     = "google.protobuf.Struct"
+	emptyType      = "google.protobuf.Empty"
+	errorDetails   = "ErrorDetails"
+	lineLength     = 120
+	definitionsRef = "#/definitions/"
+)
+
+// service describes the gRPC service of a connector type
+type service struct {
+	// name of the service
+	name string
+	// spec is the file name of the OpenAPI specification
+	spec string
+	// bulk are the operations that also get a method with a bidirectional stream of requests and results
+	bulk []string
+}
+
+var services = []service{
+	{name: "DataCatalog", spec: "datacatalog.spec.yaml", bulk: []string{"getAssetInfo"}},
+	{name: "PolicyManager", spec: "policymanager.spec.yaml"},
+	{name: "StorageManager", spec: "storagemanager.spec.yaml"},
+}
+
+// wellKnownImports are the imports of the well-known protobuf types
+var wellKnownImports = map[string]string{
+	structType: "google/protobuf/struct.proto",
+	emptyType:  "google/protobuf/empty.proto",
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*schema `json:"properties,omitempty"`
+	Items                *schema            `json:"items,omitempty"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties,omitempty"`
+}
+
+type schemaFile struct {
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+type content struct {
+	Content map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"content"`
+}
+
+type operation struct {
+	OperationID string `json:"operationId"`
+	Summary     string `json:"summary"`
+	Parameters  []struct {
+		In   string `json:"in"`
+		Name string `json:"name"`
+	} `json:"parameters"`
+	RequestBody *content            `json:"requestBody"`
+	Responses   map[string]*content `json:"responses"`
+}
+
+type openAPISpec struct {
+	Paths map[string]map[string]*operation `json:"paths"`
+}
+
+type field struct {
+	name        string
+	jsonName    string
+	typeName    string
+	repeated    bool
+	number      int
+	description string
+}
+
+type message struct {
+	name        string
+	description string
+	fields      []*field
+	reserved    []string
+}
+
+type method struct {
+	name        string
+	description string
+	input       string
+	output      string
+	streaming   bool
+}
+
+type protoFile struct {
+	name     string
+	sources  map[string]bool
+	imports  map[string]bool
+	messages map[string]*message
+	service  *service
+	methods  []*method
+}
+
+type generator struct {
+	outputDir string
+	files     map[string]*protoFile
+	schemas   map[string]*schemaFile
+	// messageFiles is the name of the proto file of each message
+	messageFiles map[string]string
+}
+
+func main() {
+	specDir := flag.String("spec-dir", ".", "directory of the OpenAPI specifications of the connectors")
+	outputDir := flag.String("o", ".", "output directory of the protobuf definitions")
+	flag.Parse()
+
+	g := &generator{
+		outputDir:    *outputDir,
+		files:        map[string]*protoFile{},
+		schemas:      map[string]*schemaFile{},
+		messageFiles: map[string]string{},
+	}
+	for i := range services {
+		if err := g.addService(&services[i], filepath.Join(*specDir, services[i].spec)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if err := g.write(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// protoFileName returns the name of the proto file of a specification or a schema file.
+// The definitions of taxonomy.json are in taxonomy.proto, although they are read from base_taxonomy.json.
+func protoFileName(path string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".spec.yaml"), ".json")
+	return strings.TrimPrefix(name, "base_") + ".proto"
+}
+
+func (g *generator) file(name string) *protoFile {
+	f, ok := g.files[name]
+	if !ok {
+		f = &protoFile{name: name, sources: map[string]bool{}, imports: map[string]bool{}, messages: map[string]*message{}}
+		g.files[name] = f
+	}
+	return f
+}
+
+// loadSchemas returns the definitions of a schema file. The taxonomy of the models is read from base_taxonomy.json,
+// because taxonomy.json also includes the taxonomy layers of the deployment, which are not part of the contract.
+func (g *generator) loadSchemas(path string) (*schemaFile, error) {
+	if filepath.Base(path) == "taxonomy.json" {
+		path = filepath.Join(filepath.Dir(path), "base_taxonomy.json")
+	}
+	if s, ok := g.schemas[path]; ok {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &schemaFile{}
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", path)
+	}
+	g.schemas[path] = s
+	return s, nil
+}
+
+// resolve returns the schema file and the definition name of a reference from a file
+func resolve(from, ref string) (string, string, error) {
+	i := strings.Index(ref, definitionsRef)
+	if i < 0 {
+		return "", "", errors.Errorf("unsupported reference %s in %s", ref, from)
+	}
+	path := from
+	if i > 0 {
+		path = filepath.Join(filepath.Dir(from), ref[:i])
+	}
+	return path, ref[i+len(definitionsRef):], nil
+}
+
+func (g *generator) addService(s *service, specPath string) error {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec := &openAPISpec{}
+	if err = yaml.Unmarshal(data, spec); err != nil {
+		return errors.Wrapf(err, "failed to parse %s", specPath)
+	}
+	f := g.file(protoFileName(specPath))
+	f.service = s
+	f.sources[filepath.Base(specPath)] = true
+	for _, item := range spec.Paths {
+		for _, op := range item {
+			m, err := g.addMethod(f, specPath, op)
+			if err != nil {
+				return err
+			}
+			f.methods = append(f.methods, m)
+			for _, bulk := range s.bulk {
+				if bulk == op.OperationID {
+					f.methods = append(f.methods, g.addBulkMethod(f, m))
+				}
+			}
+		}
+	}
+	sort.Slice(f.methods, func(i, j int) bool { return f.methods[i].name < f.methods[j].name })
+	return nil
+}
+
+// bodyType returns the message of a request or a response body, or google.protobuf.Empty if there is no body
+func (g *generator) bodyType(f *protoFile, specPath string, body *content) (string, error) {
+	if body != nil {
+		for _, c := range body.Content {
+			if c.Schema != nil && c.Schema.Ref != "" {
+				path, name, err := resolve(specPath, c.Schema.Ref)
+				if err != nil {
+					return "", err
+				}
+				return g.useMessage(f, path, name)
+			}
+		}
+	}
+	f.imports[wellKnownImports[emptyType]] = true
+	return emptyType, nil
+}
+
+func (g *generator) addMethod(f *protoFile, specPath string, op *operation) (*method, error) {
+	if op.OperationID == "" {
+		return nil, errors.Errorf("an operation in %s has no operationId", specPath)
+	}
+	m := &method{name: exported(op.OperationID), description: op.Summary}
+	for _, p := range op.Parameters {
+		if p.In == "header" {
+			m.description += ". The credentials are sent in the " + strings.ToLower(p.Name) + " metadata"
+		}
+	}
+	var err error
+	if m.input, err = g.bodyType(f, specPath, op.RequestBody); err != nil {
+		return nil, err
+	}
+	var response *content
+	for _, code := range []string{"200", "201"} {
+		if r, ok := op.Responses[code]; ok && len(r.Content) > 0 {
+			response = r
+			break
+		}
+	}
+	if m.output, err = g.bodyType(f, specPath, response); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// addBulkMethod adds the bulk method of an operation, which responds to each request in a stream with a message
+// that holds either the response or the error, in the order of the requests
+func (g *generator) addBulkMethod(f *protoFile, m *method) *method {
+	result := m.name + "Result"
+	f.messages[result] = &message{
+		name:        result,
+		description: "The result of a request in the stream of Bulk" + m.name + ", with either the response or the error",
+		fields: []*field{
+			{name: "response", jsonName: "response", typeName: m.output},
+			{name: "error", jsonName: "error", typeName: errorDetails},
+		},
+	}
+	g.messageFiles[result] = f.name
+	if _, ok := g.messageFiles[errorDetails]; !ok {
+		f.messages[errorDetails] = &message{
+			name:        errorDetails,
+			description: "The error of a request in a stream, with the status code of the REST API",
+			fields: []*field{
+				{name: "code", jsonName: "code", typeName: "int32"},
+				{name: "message", jsonName: "message", typeName: "string"},
+			},
+		}
+		g.messageFiles[errorDetails] = f.name
+	}
+	return &method{
+		name:        "Bulk" + m.name,
+		description: "Responds to each " + m.name + " request in the stream with its result, in the order of the requests",
+		input:       m.input,
+		output:      result,
+		streaming:   true,
+	}
+}
+
+// useMessage generates the message of a definition if it has not been generated yet, and returns its type name
+// for a field or a method in f
+func (g *generator) useMessage(f *protoFile, path, name string) (string, error) {
+	fileName := protoFileName(path)
+	if fileName != f.name {
+		f.imports[fileName] = true
+	}
+	if existing, ok := g.messageFiles[name]; ok {
+		if existing != fileName {
+			return "", errors.Errorf("%s is defined in both %s and %s", name, existing, fileName)
+		}
+		return name, nil
+	}
+	schemas, err := g.loadSchemas(path)
+	if err != nil {
+		return "", err
+	}
+	def, ok := schemas.Definitions[name]
+	if !ok {
+		return "", errors.Errorf("%s is not defined in %s", name, path)
+	}
+	if def.Type != "object" {
+		return "", errors.Errorf("%s in %s is not an object", name, path)
+	}
+	target := g.file(fileName)
+	target.sources[filepath.Base(path)] = true
+	g.messageFiles[name] = fileName
+	msg := &message{name: name, description: def.Description}
+	target.messages[name] = msg
+
+	properties := make([]string, 0, len(def.Properties))
+	for property := range def.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		fd, err := g.newField(target, path, property, def.Properties[property])
+		if err != nil {
+			return "", errors.Wrapf(err, "property %s of %s", property, name)
+		}
+		msg.fields = append(msg.fields, fd)
+	}
+	switch strings.TrimSpace(string(def.AdditionalProperties)) {
+	case "", "false":
+	case "true":
+		target.imports[wellKnownImports[structType]] = true
+		msg.fields = append(msg.fields, &field{
+			name:        "additional_properties",
+			jsonName:    "additionalProperties",
+			typeName:    structType,
+			description: "Properties that are defined in the taxonomy layers",
+		})
+	default:
+		return "", errors.Errorf("unsupported additionalProperties of %s in %s", name, path)
+	}
+	return name, nil
+}
+
+func (g *generator) newField(f *protoFile, path, property string, s *schema) (*field, error) {
+	fd := &field{name: snakeCase(property), jsonName: property, description: s.Description}
+	if s.Type == "array" {
+		if s.Items == nil {
+			return nil, errors.New("array without items")
+		}
+		fd.repeated = true
+		s = s.Items
+		if fd.description == "" {
+			fd.description = s.Description
+		}
+	}
+	enum := s.Enum
+	if s.Ref != "" {
+		refPath, name, err := resolve(path, s.Ref)
+		if err != nil {
+			return nil, err
+		}
+		schemas, err := g.loadSchemas(refPath)
+		if err != nil {
+			return nil, err
+		}
+		def, ok := schemas.Definitions[name]
+		if !ok {
+			return nil, errors.Errorf("%s is not defined in %s", name, refPath)
+		}
+		if fd.description == "" {
+			fd.description = def.Description
+		}
+		if len(enum) == 0 {
+			enum = def.Enum
+		}
+		if def.Type == "object" {
+			if fd.typeName, err = g.useMessage(f, refPath, name); err != nil {
+				return nil, err
+			}
+			return fd, nil
+		}
+		s = def
+	}
+	switch s.Type {
+	case "string":
+		fd.typeName = "string"
+	case "boolean":
+		fd.typeName = "bool"
+	case "integer":
+		fd.typeName = "int64"
+		if s.Format == "int32" {
+			fd.typeName = "int32"
+		}
+	case "number":
+		fd.typeName = "double"
+	default:
+		return nil, errors.Errorf("unsupported type %q", s.Type)
+	}
+	if len(enum) > 0 {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = fmt.Sprint(v)
+		}
+		fd.description = strings.TrimSuffix(fd.description, ".") + ". One of: " + strings.Join(values, ", ")
+		fd.description = strings.TrimPrefix(fd.description, ". ")
+	}
+	return fd, nil
+}
+
+// exported returns the name with an upper case first letter
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// snakeCase returns the snake case name of a property, e.g., asset_id for assetID
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// defaultJSONName returns the JSON name that protoc derives from a field name
+func defaultJSONName(name string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var (
+	messageLine  = regexp.MustCompile(`^message\s+(\w+)\s*{`)
+	fieldLine    = regexp.MustCompile(`^\s*(?:repeated\s+)?[\w.]+\s+(\w+)\s*=\s*(\d+)`)
+	reservedLine = regexp.MustCompile(`^\s*reserved\s+(.*);`)
+)
+
+// existingNumbers returns the numbers of the fields and the reserved numbers and names of the messages in an
+// existing proto file
+func existingNumbers(path string) (fields map[string]map[string]int, reserved map[string][]string, err error) {
+	fields = map[string]map[string]int{}
+	reserved = map[string][]string{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fields, reserved, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	current := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		if m := messageLine.FindStringSubmatch(line); m != nil {
+			current = m[1]
+			fields[current] = map[string]int{}
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		if line == "}" {
+			current = ""
+		} else if m := reservedLine.FindStringSubmatch(line); m != nil {
+			for _, r := range strings.Split(m[1], ",") {
+				reserved[current] = append(reserved[current], strings.TrimSpace(r))
+			}
+		} else if m := fieldLine.FindStringSubmatch(line); m != nil {
+			fields[current][m[1]], _ = strconv.Atoi(m[2])
+		}
+	}
+	return fields, reserved, nil
+}
+
+// number assigns the field numbers of the messages of a file, keeping the numbers of an existing file
+func (f *protoFile) number(path string) error {
+	fields, reserved, err := existingNumbers(path)
+	if err != nil {
+		return err
+	}
+	for _, msg := range f.messages {
+		used := map[int]bool{}
+		next := 1
+		use := func(n int) {
+			used[n] = true
+			if n >= next {
+				next = n + 1
+			}
+		}
+		msg.reserved = reserved[msg.name]
+		for _, r := range msg.reserved {
+			if n, err := strconv.Atoi(r); err == nil {
+				use(n)
+			}
+		}
+		current := map[string]bool{}
+		for _, fd := range msg.fields {
+			current[fd.name] = true
+			if n, ok := fields[msg.name][fd.name]; ok {
+				fd.number = n
+				use(n)
+			}
+		}
+		var removed []string
+		for name, n := range fields[msg.name] {
+			if !current[name] {
+				removed = append(removed, strconv.Itoa(n), strconv.Quote(name))
+				use(n)
+			}
+		}
+		sort.Strings(removed)
+		msg.reserved = append(msg.reserved, removed...)
+		for _, fd := range msg.fields {
+			if fd.number == 0 {
+				fd.number = next
+				use(next)
+			}
+		}
+		sort.SliceStable(msg.fields, func(i, j int) bool { return msg.fields[i].number < msg.fields[j].number })
+	}
+	return nil
+}
+
+// writeComment writes a description as comment lines with an indent
+func writeComment(b *strings.Builder, indent, description string) {
+	words := strings.Fields(description)
+	line := indent + "//"
+	for _, w := range words {
+		if len(line)+1+len(w) > lineLength && line != indent+"//" {
+			b.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + w
+	}
+	if line != indent+"//" {
+		b.WriteString(line + "\n")
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (f *protoFile) render() string {
+	b := &strings.Builder{}
+	b.WriteString("// Copyright 2023 IBM Corp.\n// SPDX-License-Identifier: Apache-2.0\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(b, "package %s;\n\n", protoPackage)
+	if len(f.imports) > 0 {
+		for _, i := range sortedKeys(f.imports) {
+			fmt.Fprintf(b, "import %q;\n", i)
+		}
+		b.WriteString("\n")
+	}
+	// the comments before the syntax and package statements are copied to the generated code, unlike this one
+	fmt.Fprintf(b, "// Code generated by protogen from %s. DO NOT EDIT.\n", strings.Join(sortedKeys(f.sources), ", "))
+	fmt.Fprintf(b, "option go_package = %q;\n", goPackage)
+
+	if f.service != nil {
+		b.WriteString("\n")
+		writeComment(b, "", f.service.name+" is the service of the "+strings.TrimSuffix(f.service.spec, ".spec.yaml")+
+			" connectors, with the operations of connectors/api/"+f.service.spec)
+		fmt.Fprintf(b, "service %s {\n", f.service.name)
+		for i, m := range f.methods {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			writeComment(b, "  ", m.description)
+			stream := ""
+			if m.streaming {
+				stream = "stream "
+			}
+			fmt.Fprintf(b, "  rpc %s(%s%s) returns (%s%s);\n", m.name, stream, m.input, stream, m.output)
+		}
+		b.WriteString("}\n")
+	}
+
+	names := make([]string, 0, len(f.messages))
+	for name := range f.messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		msg := f.messages[name]
+		b.WriteString("\n")
+		writeComment(b, "", msg.description)
+		fmt.Fprintf(b, "message %s {\n", msg.name)
+		// the reserved numbers and names are in separate statements
+		var numbers, reservedNames []string
+		for _, r := range msg.reserved {
+			if strings.HasPrefix(r, "\"") {
+				reservedNames = append(reservedNames, r)
+			} else {
+				numbers = append(numbers, r)
+			}
+		}
+		for _, reserved := range [][]string{numbers, reservedNames} {
+			if len(reserved) > 0 {
+				fmt.Fprintf(b, "  reserved %s;\n", strings.Join(reserved, ", "))
+			}
+		}
+		for _, fd := range msg.fields {
+			writeComment(b, "  ", fd.description)
+			label := ""
+			if fd.repeated {
+				label = "repeated "
+			}
+			option := ""
+			if defaultJSONName(fd.name) != fd.jsonName {
+				option = fmt.Sprintf(" [json_name = %q]", fd.jsonName)
+			}
+			fmt.Fprintf(b, "  %s%s %s = %d%s;\n", label, fd.typeName, fd.name, fd.number, option)
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+func (g *generator) write() error {
+	for name, f := range g.files {
+		path := filepath.Join(g.outputDir, name)
+		if err := f.number(path); err != nil {
+			return errors.Wrapf(err, "failed to read the field numbers of %s", path)
+		}
+		if err := os.WriteFile(path, []byte(f.render()), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
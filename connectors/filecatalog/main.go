@@ -0,0 +1,111 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"fybrik.io/fybrik/connectors/filecatalog/pkg/connector"
+	"fybrik.io/fybrik/pkg/environment"
+	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
+)
+
+const (
+	envServicePort = "SERVICE_PORT"
+	envCatalogDir  = "CATALOG_DIR"
+	envGitCommit   = "GIT_COMMIT"
+)
+
+var (
+	gitCommit string
+	gitTag    string
+)
+
+// RootCmd defines the root cli command
+func RootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file-catalog",
+		Short: "Data catalog for Fybrik that stores the assets as files in a directory",
+	}
+	cmd.AddCommand(RunCmd())
+	return cmd
+}
+
+// RunCmd defines the command for running the connector
+func RunCmd() *cobra.Command {
+	ip := ""
+	portStr, err := environment.MustGetEnv(envServicePort)
+	if err != nil {
+		log.Err(err).Msg(envServicePort + " env var is not defined")
+		return nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		log.Err(err).Msg(fmt.Sprintf("error in converting %s = [%s] to integer", envServicePort, portStr))
+		return nil
+	}
+	catalogDir := os.Getenv(envCatalogDir)
+	git, _ := strconv.ParseBool(os.Getenv(envGitCommit))
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the connector",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gin.SetMode(gin.ReleaseMode)
+			if catalogDir == "" {
+				return errors.New("the catalog directory must be set with --catalog-dir or " + envCatalogDir)
+			}
+
+			store, err := connector.NewStore(catalogDir, git)
+			if err != nil {
+				return errors.Wrap(err, "failed to open the catalog directory")
+			}
+			handler := connector.NewHandler(store)
+			handler.Log.Info().Msg("based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
+			handler.Log.Info().Bool("git", git).Msg("serving the assets in " + catalogDir)
+			shutdownTracing, err := tracing.Init(context.Background(), connector.ServiceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to initialize tracing")
+			}
+			defer func() { _ = shutdownTracing(context.Background()) }()
+			router := connector.NewRouter(handler)
+			router.Use(gin.Logger())
+			bindAddress := fmt.Sprintf("%s:%d", ip, port)
+
+			if environment.IsUsingTLS() {
+				tlsConfig, err := fybrikTLS.GetServerConfig(&handler.Log)
+				if err != nil {
+					return errors.Wrap(err, "failed to get tls config")
+				}
+				server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
+				return server.ListenAndServeTLS("", "")
+			}
+
+			handler.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
+			return router.Run(bindAddress)
+		},
+	}
+	cmd.Flags().StringVar(&ip, "ip", ip, "IP address")
+	cmd.Flags().IntVar(&port, "port", port, "Listening port")
+	cmd.Flags().StringVar(&catalogDir, "catalog-dir", catalogDir, "Directory of the asset files")
+	cmd.Flags().BoolVar(&git, "git", git, "Commit the changes of the assets to the git repository in the catalog directory")
+	return cmd
+}
+
+func main() {
+	// Run the cli
+	if err := RootCmd().Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
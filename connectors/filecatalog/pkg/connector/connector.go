@@ -0,0 +1,228 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/utils"
+	"fybrik.io/fybrik/pkg/vault"
+)
+
+const (
+	FybrikAssetPrefix = "fybrik-"
+	assetSuffixLength = 10
+)
+
+type Handler struct {
+	store *Store
+	Log   zerolog.Logger
+}
+
+func NewHandler(store *Store) *Handler {
+	handler := &Handler{
+		store: store,
+		Log:   logging.LogInit(logging.CONNECTOR, "file-catalog-connector"),
+	}
+	return handler
+}
+
+func (r *Handler) getAssetInfo(c *gin.Context) {
+	// Parse request
+	var request datacatalog.GetAssetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	catalog, name, err := SplitAssetID(string(request.AssetID))
+	if err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+	asset, err := r.store.Get(catalog, name)
+	if err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+
+	response := datacatalog.GetAssetResponse{
+		ResourceMetadata: asset.Metadata,
+		Details:          asset.Details,
+	}
+	// the credentials are referenced in the same way as in other catalogs, also if vault is not used
+	if asset.SecretRef != nil {
+		secretNamespace := asset.SecretRef.Namespace
+		if secretNamespace == "" {
+			secretNamespace = catalog
+		}
+		response.Credentials = vault.PathForReadingKubeSecret(secretNamespace, asset.SecretRef.Name)
+	}
+	c.JSON(http.StatusOK, &response)
+}
+
+// Enables writing of assets to the catalog directory. The asset is created in the directory of DestinationCatalogID:
+// (a) When DestinationAssetID is specified then an asset is created with name: <DestinationAssetID>-<Random String>
+// (b) When DestinationAssetID is not specified then an asset is created with name: fybrik-<Random String>
+// When IdempotencyKey is specified, the random string is replaced by a hash of the key, and the asset that has been
+// created with the same key is returned instead of creating a new asset.
+func (r *Handler) createAsset(c *gin.Context) {
+	// Parse request
+	var request datacatalog.CreateAssetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	logging.LogStructure("CreateAssetRequest object received:", request, &r.Log, zerolog.DebugLevel, false, false)
+
+	asset := &Asset{
+		IdempotencyKey: request.IdempotencyKey,
+		Metadata:       request.ResourceMetadata,
+		Details:        request.Details,
+	}
+	if request.Credentials != "" {
+		secretName, secretNamespace, err := vault.GetKubeSecretDetailsFromVaultPath(request.Credentials)
+		if err != nil {
+			r.reportError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		asset.SecretRef = &taxonomy.SecretRef{Name: secretName, Namespace: secretNamespace}
+	}
+
+	assetPrefix := FybrikAssetPrefix
+	if request.DestinationAssetID != "" {
+		assetPrefix = utils.K8sConformName(request.DestinationAssetID, &r.Log) + "-"
+	}
+	suffix := uuid.New().String()
+	if request.IdempotencyKey != "" {
+		suffix = request.IdempotencyKey
+	}
+	catalog, name := request.DestinationCatalogID, assetPrefix+utils.Hash(suffix, assetSuffixLength)
+	assetID := taxonomy.AssetID(catalog + "/" + name)
+
+	err := r.store.Create(catalog, name, asset)
+	if errors.Is(err, os.ErrExist) && request.IdempotencyKey != "" {
+		r.returnExistingAsset(c, catalog, name, request.IdempotencyKey)
+		return
+	}
+	if err != nil {
+		r.reportStoreError(c, assetID, err)
+		return
+	}
+	response := datacatalog.CreateAssetResponse{
+		AssetID: string(assetID),
+	}
+	r.Log.Info().Msg("Sending response from File Catalog Connector with created asset ID: " + response.AssetID)
+
+	c.JSON(http.StatusCreated, &response)
+}
+
+// returnExistingAsset responds with the asset that has been created for a previous request with the same idempotency key
+func (r *Handler) returnExistingAsset(c *gin.Context, catalog, name, idempotencyKey string) {
+	assetID := taxonomy.AssetID(catalog + "/" + name)
+	existing, err := r.store.Get(catalog, name)
+	if err != nil {
+		r.reportStoreError(c, assetID, err)
+		return
+	}
+	if existing.IdempotencyKey != idempotencyKey {
+		r.reportError(c, http.StatusConflict, fmt.Sprintf("asset %s exists with a different idempotency key", assetID))
+		return
+	}
+	response := datacatalog.CreateAssetResponse{
+		AssetID: string(assetID),
+	}
+	r.Log.Info().Msg("Sending response from File Catalog Connector with the asset ID created for the idempotency key: " +
+		response.AssetID)
+
+	c.JSON(http.StatusOK, &response)
+}
+
+// Enables deletion of assets from the catalog directory.
+func (r *Handler) deleteAsset(c *gin.Context) {
+	// Parse request
+	var request datacatalog.DeleteAssetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	logging.LogStructure("DeleteAssetRequest object received:", request, &r.Log, zerolog.DebugLevel, false, false)
+
+	catalog, name, err := SplitAssetID(string(request.AssetID))
+	if err == nil {
+		err = r.store.Delete(catalog, name)
+	}
+	if err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+	response := datacatalog.DeleteAssetResponse{
+		Status: "Deletion successful!",
+	}
+	r.Log.Info().Msg("Sending response from File Catalog Connector with deleted asset ID: " + string(request.AssetID))
+
+	c.JSON(http.StatusOK, &response)
+}
+
+// Enables updates of the metadata of assets in the catalog directory.
+func (r *Handler) updateAsset(c *gin.Context) {
+	// Parse request
+	var request datacatalog.UpdateAssetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	logging.LogStructure("UpdateAssetRequest received:", request, &r.Log, zerolog.DebugLevel, false, false)
+
+	catalog, name, err := SplitAssetID(string(request.AssetID))
+	if err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+	asset, err := r.store.Get(catalog, name)
+	if err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+	asset.Metadata.Name = request.Name
+	asset.Metadata.Owner = request.Owner
+	asset.Metadata.Tags = request.Tags
+	asset.Metadata.Columns = request.Columns
+	if err = r.store.Update(catalog, name, asset); err != nil {
+		r.reportStoreError(c, request.AssetID, err)
+		return
+	}
+	response := datacatalog.UpdateAssetResponse{
+		Status: "Updation successful!",
+	}
+	r.Log.Info().Msg("Sending response from File Catalog Connector with updated asset ID: " + string(request.AssetID))
+
+	c.JSON(http.StatusOK, &response)
+}
+
+// reportStoreError reports an error of the store with the status code that matches it
+func (r *Handler) reportStoreError(c *gin.Context, assetID taxonomy.AssetID, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidAssetID):
+		r.reportError(c, http.StatusBadRequest, fmt.Sprintf("request has an invalid asset ID %s (must be in catalog/name format)", assetID))
+	case errors.Is(err, os.ErrNotExist):
+		r.reportError(c, http.StatusNotFound, fmt.Sprintf("asset %s does not exist", assetID))
+	default:
+		r.reportError(c, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (r *Handler) reportError(c *gin.Context, httpCode int, errorMessage string) {
+	r.Log.Warn().CallerSkipFrame(1).Msg(errorMessage)
+	c.JSON(httpCode, gin.H{"error": errorMessage})
+}
@@ -0,0 +1,161 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+	"fybrik.io/fybrik/pkg/vault"
+)
+
+// request sends a request to the connector and returns the response code, and decodes the response body into response
+func request(g *WithT, router *gin.Engine, method, path string, body, response interface{}) int {
+	requestBody, err := json.Marshal(body)
+	g.Expect(err).ToNot(HaveOccurred())
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(requestBody))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	if response != nil && w.Code < http.StatusMultipleChoices {
+		g.Expect(json.Unmarshal(w.Body.Bytes(), response)).To(Succeed())
+	}
+	return w.Code
+}
+
+func TestAssetLifecycle(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	store, err := NewStore(t.TempDir(), false)
+	g.Expect(err).ToNot(HaveOccurred())
+	router := NewRouter(NewHandler(store))
+
+	metadata := datacatalog.ResourceMetadata{
+		Name:      "transactions",
+		Geography: "theshire",
+		Tags:      &taxonomy.Tags{Properties: serde.Properties{Items: map[string]interface{}{"finance": true}}},
+	}
+	details := datacatalog.ResourceDetails{DataFormat: "csv", Connection: taxonomy.Connection{Name: "s3"}}
+	createRequest := datacatalog.CreateAssetRequest{
+		DestinationCatalogID: "finance",
+		DestinationAssetID:   "transactions",
+		ResourceMetadata:     metadata,
+		Details:              details,
+		Credentials:          vault.PathForReadingKubeSecret("fybrik-system", "transactions-creds"),
+		IdempotencyKey:       "app-uuid/transactions",
+	}
+	created := datacatalog.CreateAssetResponse{}
+	g.Expect(request(g, router, http.MethodPost, "/createAsset", &createRequest, &created)).To(Equal(http.StatusCreated))
+	g.Expect(created.AssetID).To(HavePrefix("finance/transactions-"))
+
+	// the asset that has been created for the idempotency key is returned
+	repeated := datacatalog.CreateAssetResponse{}
+	g.Expect(request(g, router, http.MethodPost, "/createAsset", &createRequest, &repeated)).To(Equal(http.StatusOK))
+	g.Expect(repeated.AssetID).To(Equal(created.AssetID))
+
+	getRequest := datacatalog.GetAssetRequest{AssetID: taxonomy.AssetID(created.AssetID), OperationType: datacatalog.READ}
+	asset := datacatalog.GetAssetResponse{}
+	g.Expect(request(g, router, http.MethodPost, "/getAssetInfo", &getRequest, &asset)).To(Equal(http.StatusOK))
+	g.Expect(asset.ResourceMetadata).To(Equal(metadata))
+	g.Expect(asset.Details).To(Equal(details))
+	g.Expect(asset.Credentials).To(Equal(createRequest.Credentials))
+
+	updateRequest := datacatalog.UpdateAssetRequest{AssetID: taxonomy.AssetID(created.AssetID), Name: "transactions", Owner: "Alice"}
+	g.Expect(request(g, router, http.MethodPatch, "/updateAsset", &updateRequest, nil)).To(Equal(http.StatusOK))
+	g.Expect(request(g, router, http.MethodPost, "/getAssetInfo", &getRequest, &asset)).To(Equal(http.StatusOK))
+	g.Expect(asset.ResourceMetadata.Owner).To(Equal("Alice"))
+
+	deleteRequest := datacatalog.DeleteAssetRequest{AssetID: taxonomy.AssetID(created.AssetID)}
+	g.Expect(request(g, router, http.MethodDelete, "/deleteAsset", &deleteRequest, nil)).To(Equal(http.StatusOK))
+	g.Expect(request(g, router, http.MethodPost, "/getAssetInfo", &getRequest, nil)).To(Equal(http.StatusNotFound))
+	g.Expect(request(g, router, http.MethodDelete, "/deleteAsset", &deleteRequest, nil)).To(Equal(http.StatusNotFound))
+}
+
+func TestInvalidAssetID(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	store, err := NewStore(t.TempDir(), false)
+	g.Expect(err).ToNot(HaveOccurred())
+	router := NewRouter(NewHandler(store))
+
+	for _, assetID := range []string{"asset", "../etc/passwd", "finance/../../asset", "/asset"} {
+		getRequest := datacatalog.GetAssetRequest{AssetID: taxonomy.AssetID(assetID), OperationType: datacatalog.READ}
+		g.Expect(request(g, router, http.MethodPost, "/getAssetInfo", &getRequest, nil)).To(Equal(http.StatusBadRequest), assetID)
+	}
+	createRequest := datacatalog.CreateAssetRequest{DestinationCatalogID: "../finance"}
+	g.Expect(request(g, router, http.MethodPost, "/createAsset", &createRequest, nil)).To(Equal(http.StatusBadRequest))
+}
+
+func TestGitHistory(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	store, err := NewStore(dir, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	router := NewRouter(NewHandler(store))
+
+	createRequest := datacatalog.CreateAssetRequest{DestinationCatalogID: "finance", ResourceMetadata: datacatalog.ResourceMetadata{Name: "a"}}
+	created := datacatalog.CreateAssetResponse{}
+	g.Expect(request(g, router, http.MethodPost, "/createAsset", &createRequest, &created)).To(Equal(http.StatusCreated))
+	g.Expect(created.AssetID).To(HavePrefix("finance/" + FybrikAssetPrefix))
+	updateRequest := datacatalog.UpdateAssetRequest{AssetID: taxonomy.AssetID(created.AssetID), Name: "b"}
+	g.Expect(request(g, router, http.MethodPatch, "/updateAsset", &updateRequest, nil)).To(Equal(http.StatusOK))
+	// an update that does not change the asset is not committed
+	g.Expect(request(g, router, http.MethodPatch, "/updateAsset", &updateRequest, nil)).To(Equal(http.StatusOK))
+	deleteRequest := datacatalog.DeleteAssetRequest{AssetID: taxonomy.AssetID(created.AssetID)}
+	g.Expect(request(g, router, http.MethodDelete, "/deleteAsset", &deleteRequest, nil)).To(Equal(http.StatusOK))
+
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%s").Output()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(strings.Split(strings.TrimSpace(string(out)), "\n")).To(Equal([]string{
+		"Delete asset " + created.AssetID,
+		"Update asset " + created.AssetID,
+		"Create asset " + created.AssetID,
+	}))
+}
+
+func TestGitCommitFailure(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	dir := t.TempDir()
+	store, err := NewStore(dir, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	asset := &Asset{Metadata: datacatalog.ResourceMetadata{Name: "a"}}
+	g.Expect(store.Create("finance", "a", asset)).To(Succeed())
+	g.Expect(store.Create("finance", "a", asset)).To(MatchError(os.ErrExist))
+
+	// a failing hook rejects all the following commits
+	hook := filepath.Join(dir, ".git", "hooks", "pre-commit")
+	g.Expect(os.WriteFile(hook, []byte("#!/bin/sh\nexit 1\n"), 0o700)).To(Succeed()) //nolint:gosec // the hook must be executable
+
+	// the files of the assets are restored if their changes cannot be committed
+	g.Expect(store.Update("finance", "a", &Asset{Metadata: datacatalog.ResourceMetadata{Name: "b"}})).ToNot(Succeed())
+	g.Expect(store.Get("finance", "a")).To(Equal(asset))
+	g.Expect(store.Delete("finance", "a")).ToNot(Succeed())
+	g.Expect(store.Get("finance", "a")).To(Equal(asset))
+	g.Expect(store.Create("finance", "c", asset)).ToNot(Succeed())
+	_, err = store.Get("finance", "c")
+	g.Expect(err).To(MatchError(os.ErrNotExist))
+
+	// neither the changes nor temporary files are left in the directory
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--untracked-files=all").Output()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(out)).To(BeEmpty())
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// ServiceName is the name of the file catalog service in trace spans
+const ServiceName = "file-catalog-connector"
+
+// NewRouter returns a new router.
+func NewRouter(handler *Handler) *gin.Engine {
+	router := gin.Default()
+	router.Use(otelgin.Middleware(ServiceName))
+	router.POST("/getAssetInfo", handler.getAssetInfo)
+	router.POST("/createAsset", handler.createAsset)
+	router.DELETE("/deleteAsset", handler.deleteAsset)
+	router.PATCH("/updateAsset", handler.updateAsset)
+	return router
+}
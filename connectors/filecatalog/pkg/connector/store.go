@@ -0,0 +1,245 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const (
+	// AssetFileExtension is the extension of the asset files
+	AssetFileExtension = ".yaml"
+	gitAuthorName      = "Fybrik file catalog"
+	gitAuthorEmail     = "file-catalog@fybrik.io"
+)
+
+// ErrInvalidAssetID is returned for asset identifiers that are not in catalog/name format
+var ErrInvalidAssetID = errors.New("invalid asset ID (must be in catalog/name format)")
+
+// idSegmentRegex matches the catalog and the name of an asset, which are used as a directory name and a file name
+var idSegmentRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]*[a-zA-Z0-9])?$`)
+
+// Asset is the content of an asset file
+type Asset struct {
+	// IdempotencyKey of the request that created the asset
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	// SecretRef references the kubernetes secret that holds the credentials of the asset
+	SecretRef *taxonomy.SecretRef `json:"secretRef,omitempty"`
+	// Metadata of the asset
+	Metadata datacatalog.ResourceMetadata `json:"metadata"`
+	// Details of the asset
+	Details datacatalog.ResourceDetails `json:"details"`
+}
+
+// Store keeps the assets as yaml files in a directory, with a sub directory per catalog.
+// The asset with identifier catalog/name is stored in <dir>/catalog/name.yaml.
+// If git is enabled, the directory is a git repository and every change of an asset is committed.
+type Store struct {
+	Dir string
+	Git bool
+	mux sync.RWMutex
+}
+
+// NewStore returns a store of the assets in the given directory.
+// If git is enabled, the directory is initialized as a git repository if it is not one already.
+func NewStore(dir string, git bool) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrap(err, "failed to create the catalog directory")
+	}
+	store := &Store{Dir: dir, Git: git}
+	if git {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+			if err = store.git("init"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return store, nil
+}
+
+// assetFile returns the file of the asset relative to the store directory
+func assetFile(catalog, name string) (string, error) {
+	if !idSegmentRegex.MatchString(catalog) || !idSegmentRegex.MatchString(name) {
+		return "", ErrInvalidAssetID
+	}
+	return filepath.Join(catalog, name+AssetFileExtension), nil
+}
+
+// SplitAssetID returns the catalog and the name of the asset
+func SplitAssetID(assetID string) (string, string, error) {
+	catalog, name, found := strings.Cut(assetID, "/")
+	if !found {
+		return "", "", ErrInvalidAssetID
+	}
+	if _, err := assetFile(catalog, name); err != nil {
+		return "", "", err
+	}
+	return catalog, name, nil
+}
+
+// Get returns the asset. It returns an error that wraps os.ErrNotExist if the asset does not exist.
+func (s *Store) Get(catalog, name string) (*Asset, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	file, err := assetFile(catalog, name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filepath.Join(s.Dir, file))
+	if err != nil {
+		return nil, err
+	}
+	asset := &Asset{}
+	if err = yaml.Unmarshal(content, asset); err != nil {
+		return nil, errors.Wrap(err, "failed to parse asset file "+file)
+	}
+	return asset, nil
+}
+
+// Create stores a new asset. It returns an error that wraps os.ErrExist if the asset exists.
+func (s *Store) Create(catalog, name string, asset *Asset) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.write(catalog, name, asset, true, "Create asset "+catalog+"/"+name)
+}
+
+// Update stores the new content of an existing asset
+func (s *Store) Update(catalog, name string, asset *Asset) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.write(catalog, name, asset, false, "Update asset "+catalog+"/"+name)
+}
+
+// Delete removes the asset. It returns an error that wraps os.ErrNotExist if the asset does not exist.
+func (s *Store) Delete(catalog, name string) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	file, err := assetFile(catalog, name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, file)
+	previous, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil {
+		return err
+	}
+	return s.commitOrRestore(file, previous, "Delete asset "+catalog+"/"+name)
+}
+
+// write stores the content of a new asset if create is set, or of an existing asset otherwise
+func (s *Store) write(catalog, name string, asset *Asset, create bool, message string) error {
+	file, err := assetFile(catalog, name)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(asset)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.Dir, file)
+	// the previous content of an existing asset is restored if the change cannot be committed
+	previous, err := os.ReadFile(path)
+	switch {
+	case create && err == nil:
+		return &os.PathError{Op: "create", Path: path, Err: os.ErrExist}
+	case create && !errors.Is(err, os.ErrNotExist), !create && err != nil:
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	if err = writeFile(path, content); err != nil {
+		return err
+	}
+	return s.commitOrRestore(file, previous, message)
+}
+
+// writeFile writes the content to a temporary file in the directory of the file and renames it to the file,
+// so that the file is replaced atomically and is never left partially written
+func writeFile(path string, content []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// the temporary file does not exist anymore once it is renamed
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err = f.Write(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// commitOrRestore commits the change of the file, and restores the previous content of the file if the commit fails,
+// so that the assets in the directory are the committed ones. A nil previous content stands for a new file.
+func (s *Store) commitOrRestore(file string, previous []byte, message string) error {
+	err := s.commit(file, message)
+	if err == nil {
+		return nil
+	}
+	path := filepath.Join(s.Dir, file)
+	var restoreErr error
+	if previous == nil {
+		restoreErr = os.Remove(path)
+	} else {
+		restoreErr = writeFile(path, previous)
+	}
+	if restoreErr == nil {
+		// the change is not staged anymore
+		restoreErr = s.git("reset", "--quiet", "--", file)
+	}
+	if restoreErr != nil {
+		return errors.Combine(err, errors.Wrap(restoreErr, "failed to restore "+file))
+	}
+	return err
+}
+
+// commit commits the change of the file if git is enabled
+func (s *Store) commit(file, message string) error {
+	if !s.Git {
+		return nil
+	}
+	if err := s.git("add", "--all", "--", file); err != nil {
+		return err
+	}
+	// there is nothing to commit if the content of the file has not changed
+	if err := s.git("diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	return s.git("-c", "user.name="+gitAuthorName, "-c", "user.email="+gitAuthorEmail, "commit", "--quiet", "-m", message)
+}
+
+func (s *Store) git(args ...string) error {
+	//nolint:gosec // the arguments are built by the store
+	cmd := exec.Command("git", append([]string{"-C", s.Dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String())))
+	}
+	return nil
+}
@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,19 +13,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	ctrl "sigs.k8s.io/controller-runtime"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	kconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
 	"fybrik.io/fybrik/connectors/katalog/pkg/connector"
+	"fybrik.io/fybrik/connectors/katalog/pkg/controllers"
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/environment"
 	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 const (
-	envServicePort = "SERVICE_PORT"
+	envServicePort         = "SERVICE_PORT"
+	envEnableAuthorization = "ENABLE_AUTHORIZATION"
+	envEnableAssetStatus   = "ENABLE_ASSET_STATUS"
+	envLeaderElectionID    = "LEADER_ELECTION_ID"
+	webhookCertSubDir      = "/k8s-webhook-server"
+	webhookPort            = 9443
 )
 
 var (
@@ -55,6 +70,15 @@ func RunCmd() *cobra.Command {
 		log.Err(err).Msg(fmt.Sprintf("error in converting %s = [%s] to integer", envServicePort, portStr))
 		return nil
 	}
+	grpcPort, err := environment.GetGRPCPort()
+	if err != nil {
+		log.Err(err).Msg("invalid " + environment.GRPCPortKey)
+		return nil
+	}
+	authorization := os.Getenv(envEnableAuthorization) == "true"
+	assetStatus := os.Getenv(envEnableAssetStatus) == "true"
+	webhooks := os.Getenv(environment.EnableWebhooksKey) == "true"
+	leaderElectionID := os.Getenv(envLeaderElectionID)
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run the connector",
@@ -70,6 +94,18 @@ func RunCmd() *cobra.Command {
 			if err != nil {
 				return errors.Wrap(err, "unable to add corev1 to schema")
 			}
+			err = authenticationv1.AddToScheme(scheme)
+			if err != nil {
+				return errors.Wrap(err, "unable to add authenticationv1 to schema")
+			}
+			err = authorizationv1.AddToScheme(scheme)
+			if err != nil {
+				return errors.Wrap(err, "unable to add authorizationv1 to schema")
+			}
+			err = fappv1.AddToScheme(scheme)
+			if err != nil {
+				return errors.Wrap(err, "unable to add fybrik v1beta1 to schema")
+			}
 
 			client, err := kclient.New(kconfig.GetConfigOrDie(), kclient.Options{Scheme: scheme})
 			if err != nil {
@@ -77,29 +113,103 @@ func RunCmd() *cobra.Command {
 			}
 
 			handler := connector.NewHandler(client)
+			if authorization {
+				handler.Authorizer = connector.NewSubjectAccessReviewAuthorizer(client)
+				handler.Log.Info().Msg("requests are authorized with subject access reviews")
+			}
 			handler.Log.Info().Msg("based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
+			shutdownTracing, err := tracing.Init(context.Background(), connector.ServiceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to initialize tracing")
+			}
+			defer func() { _ = shutdownTracing(context.Background()) }()
 			router := connector.NewRouter(handler)
 			router.Use(gin.Logger())
 			bindAddress := fmt.Sprintf("%s:%d", ip, port)
 
-			if environment.IsUsingTLS() {
-				tlsConfig, err := fybrikTLS.GetServerConfig(&handler.Log)
-				if err != nil {
-					return errors.Wrap(err, "failed to get tls config")
+			serveHTTP := func() error {
+				if environment.IsUsingTLS() {
+					tlsConfig, err := fybrikTLS.GetServerConfig(&handler.Log)
+					if err != nil {
+						return errors.Wrap(err, "failed to get tls config")
+					}
+					server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
+					return server.ListenAndServeTLS("", "")
 				}
-				server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
-				return server.ListenAndServeTLS("", "")
+
+				handler.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
+				return router.Run(bindAddress)
+			}
+			errs := make(chan error, 3)
+			go func() { errs <- serveHTTP() }()
+
+			// the connector serves gRPC in addition to REST if a gRPC port is set
+			if grpcPort != 0 {
+				var grpcServer *grpc.Server
+				if grpcServer, err = rpc.NewServer(connector.ServiceName, &handler.Log); err != nil {
+					return err
+				}
+				defer grpcServer.Stop()
+				rpc.RegisterDataCatalogServer(grpcServer, handler)
+				go func() { errs <- rpc.Serve(grpcServer, fmt.Sprintf("%s:%d", ip, grpcPort)) }()
 			}
 
-			handler.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
-			return router.Run(bindAddress)
+			// the validating webhook and the status of the assets are served by a controller manager
+			if webhooks || assetStatus {
+				var mgr ctrl.Manager
+				if mgr, err = newAssetManager(scheme, webhooks, assetStatus, leaderElectionID); err != nil {
+					return err
+				}
+				go func() { errs <- mgr.Start(ctrl.SetupSignalHandler()) }()
+			}
+			return <-errs
 		},
 	}
 	cmd.Flags().StringVar(&ip, "ip", ip, "IP address")
 	cmd.Flags().IntVar(&port, "port", port, "Listening port")
+	cmd.Flags().BoolVar(&authorization, "authorization", authorization,
+		"Authorize the callers with the RBAC rules of the cluster on the assets")
+	cmd.Flags().IntVar(&grpcPort, "grpc-port", grpcPort, "Listening port for gRPC (disabled if 0)")
+	cmd.Flags().BoolVar(&webhooks, "webhooks", webhooks, "Serve the webhook that validates the assets against the taxonomy")
+	cmd.Flags().BoolVar(&assetStatus, "asset-status", assetStatus,
+		"Report the validation errors and the referencing applications in the status of the assets")
+	cmd.Flags().StringVar(&leaderElectionID, "leader-election-id", leaderElectionID,
+		"Name of the lease that elects the replica that reports the status of the assets (disabled if empty)")
 	return cmd
 }
 
+// newAssetManager returns a controller manager that serves the validating webhook of the assets if webhooks is set,
+// and runs the controller that updates the status of the assets if assetStatus is set.
+// The webhook is served by all the replicas of the connector, while the controller runs only in the replica that
+// holds the lease named leaderElectionID, unless it is empty.
+func newAssetManager(scheme *runtime.Scheme, webhooks, assetStatus bool, leaderElectionID string) (ctrl.Manager, error) {
+	mgr, err := ctrl.NewManager(kconfig.GetConfigOrDie(), ctrl.Options{
+		Scheme:    scheme,
+		Namespace: environment.GetApplicationNamespace(),
+		CertDir:   environment.GetDataDir() + webhookCertSubDir,
+		Port:      webhookPort,
+		// the metrics of the manager are not served, since their default port is used by the connector
+		MetricsBindAddress:         "0",
+		LeaderElection:             assetStatus && leaderElectionID != "",
+		LeaderElectionID:           leaderElectionID,
+		LeaderElectionResourceLock: resourcelock.LeasesResourceLock,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create a controller manager")
+	}
+	if webhooks {
+		if err = (&v1alpha1.Asset{}).SetupWebhookWithManager(mgr); err != nil {
+			return nil, errors.Wrap(err, "unable to create the Asset webhook")
+		}
+	}
+	if assetStatus {
+		if err = controllers.NewAssetReconciler(mgr).SetupWithManager(mgr); err != nil {
+			return nil, errors.Wrap(err, "unable to create the Asset controller")
+		}
+	}
+	return mgr, nil
+}
+
 func main() {
 	// Run the cli
 	if err := RootCmd().Execute(); err != nil {
@@ -0,0 +1,113 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/validate"
+)
+
+// TaxonomyFile is the data catalog taxonomy that defines the details and the metadata of the assets
+var TaxonomyFile = environment.GetDataDir() + "/taxonomy/datacatalog.json"
+
+func (r *Asset) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,admissionReviewVersions=v1;v1beta1,sideEffects=None,path=/validate-katalog-fybrik-io-v1alpha1-asset,mutating=false,failurePolicy=fail,groups=katalog.fybrik.io,resources=assets;assets/status,versions=v1alpha1,name=vasset.katalog.fybrik.io
+
+var _ webhook.Validator = &Asset{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *Asset) ValidateCreate() error {
+	return r.ValidateAsset(TaxonomyFile)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *Asset) ValidateUpdate(old runtime.Object) error {
+	oldAsset, ok := old.(*Asset)
+	if !ok {
+		return fmt.Errorf("expected an Asset but got a %T", old)
+	}
+	allErrs := validateRevisions(oldAsset.Status.Revisions, r.Status.Revisions)
+	// the spec is only validated if it changes, so that the status of assets that do not conform to the taxonomy
+	// can be updated
+	if !equality.Semantic.DeepEqual(&oldAsset.Spec, &r.Spec) {
+		errs, err := r.ValidateAssetFields(TaxonomyFile)
+		if err != nil {
+			return err
+		}
+		allErrs = append(allErrs, errs...)
+	}
+	return r.invalid(allErrs)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *Asset) ValidateDelete() error {
+	return nil
+}
+
+// ValidateAsset returns an Invalid error if the asset does not conform to the taxonomy
+func (r *Asset) ValidateAsset(taxonomyFile string) error {
+	allErrs, err := r.ValidateAssetFields(taxonomyFile)
+	if err != nil {
+		return err
+	}
+	return r.invalid(allErrs)
+}
+
+// invalid returns an Invalid error with the errors of the validation of the asset, or nil if there are none
+func (r *Asset) invalid(allErrs field.ErrorList) error {
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(GroupVersion.WithKind("Asset").GroupKind(), r.Name, allErrs)
+}
+
+// ValidateAssetFields validates the details and the metadata of the asset against the definitions of
+// ResourceDetails and ResourceMetadata in the data catalog taxonomy, which the responses of the connector are
+// validated against
+func (r *Asset) ValidateAssetFields(taxonomyFile string) (field.ErrorList, error) {
+	var allErrs field.ErrorList
+	fields := []struct {
+		path       *field.Path
+		value      interface{}
+		definition string
+	}{
+		{field.NewPath("spec", "details"), &r.Spec.Details, "ResourceDetails"},
+		{field.NewPath("spec", "metadata"), &r.Spec.Metadata, "ResourceMetadata"},
+	}
+	for _, f := range fields {
+		fieldJSON, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		errs, err := validate.TaxonomyCheck(fieldJSON, taxonomyFile+"#/definitions/"+f.definition)
+		if err != nil {
+			return nil, err
+		}
+		// the paths of the taxonomy check are relative to the validated field
+		for _, e := range errs {
+			if e.Field == "(root)" {
+				e.Field = f.path.String()
+			} else {
+				e.Field = f.path.String() + "." + e.Field
+			}
+			allErrs = append(allErrs, e)
+		}
+	}
+	return allErrs, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const taxonomyFile = "../../../../../../charts/fybrik/files/taxonomy/datacatalog.json"
+
+func assetWithConnection(t *testing.T, connection string) *Asset {
+	asset := &Asset{ObjectMeta: metav1.ObjectMeta{Name: "asset", Namespace: "demo"}}
+	spec := `
+details:
+  dataFormat: csv
+  connection:
+` + connection + `
+metadata:
+  name: asset
+  geography: theshire
+  tags:
+    finance: true
+secretRef:
+  name: asset-creds
+`
+	assert.Nil(t, yaml.Unmarshal([]byte(spec), &asset.Spec))
+	return asset
+}
+
+func TestValidAsset(t *testing.T) {
+	t.Parallel()
+	asset := assetWithConnection(t, `
+    name: s3
+    s3:
+      endpoint: "http://localstack.fybrik-notebook-sample.svc.cluster.local:4566"
+      bucket: "demo"
+      object_key: "PS_20174392719_1491204439457_log.csv"`)
+	assert.Nil(t, asset.ValidateAsset(taxonomyFile), "No error should be found")
+}
+
+func TestInvalidAsset(t *testing.T) {
+	t.Parallel()
+	asset := assetWithConnection(t, `
+    name: s3
+    s3:
+      endpoint: "http://localstack.fybrik-notebook-sample.svc.cluster.local:4566"
+      object_key: 5`)
+	errs, err := asset.ValidateAssetFields(taxonomyFile)
+	assert.Nil(t, err)
+	fields := []string{}
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	assert.Contains(t, fields, "spec.details.connection.s3")
+	assert.Contains(t, fields, "spec.details.connection.s3.object_key")
+
+	err = asset.ValidateAsset(taxonomyFile)
+	assert.True(t, apierrors.IsInvalid(err), "the asset should be invalid: %v", err)
+}
+
+func TestRevisions(t *testing.T) {
+	t.Parallel()
+	asset := assetWithConnection(t, `
+    name: s3
+    s3:
+      endpoint: "http://localstack.fybrik-notebook-sample.svc.cluster.local:4566"
+      bucket: "demo"
+      object_key: "PS_20174392719_1491204439457_log.csv"`)
+	for revision := int64(1); revision <= MaxAssetRevisions+1; revision++ {
+		assert.True(t, asset.Status.AddRevision(revision, &asset.Spec.Metadata, metav1.Now()))
+	}
+	assert.False(t, asset.Status.AddRevision(MaxAssetRevisions, &asset.Spec.Metadata, metav1.Now()),
+		"revisions older than the latest one should not be recorded")
+	assert.Len(t, asset.Status.Revisions, MaxAssetRevisions)
+	assert.Equal(t, int64(2), asset.Status.Revisions[0].Revision, "the oldest revision should be dropped")
+
+	// dropping the oldest revision and recording a new one is allowed
+	updated := asset.DeepCopy()
+	updated.Status.AddRevision(MaxAssetRevisions+2, &asset.Spec.Metadata, metav1.Now())
+	assert.Nil(t, updated.ValidateUpdate(asset))
+
+	// recorded revisions can not be modified or removed
+	modified := asset.DeepCopy()
+	modified.Status.Revisions[1].Metadata.Owner = "mallory"
+	assert.True(t, apierrors.IsInvalid(modified.ValidateUpdate(asset)))
+	removed := asset.DeepCopy()
+	removed.Status.Revisions = append(removed.Status.Revisions[:1], removed.Status.Revisions[2:]...)
+	assert.True(t, apierrors.IsInvalid(removed.ValidateUpdate(asset)))
+	removed.Status.Revisions = nil
+	assert.True(t, apierrors.IsInvalid(removed.ValidateUpdate(asset)))
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+// MaxAssetRevisions is the number of revisions of the metadata that are kept in the status of an asset
+const MaxAssetRevisions = 20
+
+// AddRevision records the metadata of the asset at a revision, unless the revision is not newer than the latest
+// recorded revision. The oldest revisions are dropped to keep at most MaxAssetRevisions.
+// It returns whether the revision has been recorded.
+func (s *AssetStatus) AddRevision(revision int64, metadata *datacatalog.ResourceMetadata, now metav1.Time) bool {
+	if len(s.Revisions) > 0 && s.Revisions[len(s.Revisions)-1].Revision >= revision {
+		return false
+	}
+	s.Revisions = append(s.Revisions, AssetRevision{Revision: revision, Time: now, Metadata: *metadata.DeepCopy()})
+	if len(s.Revisions) > MaxAssetRevisions {
+		s.Revisions = s.Revisions[len(s.Revisions)-MaxAssetRevisions:]
+	}
+	return true
+}
+
+// MetadataAt returns the metadata of the asset at a revision. The current metadata is returned for the generation
+// of the asset, and the recorded metadata for the previous revisions.
+func (r *Asset) MetadataAt(revision int64) (*datacatalog.ResourceMetadata, bool) {
+	if revision == r.Generation {
+		return &r.Spec.Metadata, true
+	}
+	for ind := range r.Status.Revisions {
+		if r.Status.Revisions[ind].Revision == revision {
+			return &r.Status.Revisions[ind].Metadata, true
+		}
+	}
+	return nil, false
+}
+
+// validateRevisions returns errors if an update of the status modifies recorded revisions,
+// or removes revisions other than the oldest ones
+func validateRevisions(oldRevisions, newRevisions []AssetRevision) field.ErrorList {
+	var allErrs field.ErrorList
+	path := field.NewPath("status", "revisions")
+	if len(oldRevisions) > 0 && len(newRevisions) == 0 {
+		return append(allErrs, field.Forbidden(path, "recorded revisions can not be removed"))
+	}
+	recorded := map[int64]int{}
+	for ind := range newRevisions {
+		recorded[newRevisions[ind].Revision] = ind
+	}
+	for ind := range oldRevisions {
+		old := &oldRevisions[ind]
+		newIndex, found := recorded[old.Revision]
+		switch {
+		case found && !equality.Semantic.DeepEqual(old, &newRevisions[newIndex]):
+			allErrs = append(allErrs, field.Forbidden(path.Index(newIndex), "recorded revisions are immutable"))
+		case !found && old.Revision > newRevisions[0].Revision:
+			allErrs = append(allErrs, field.Forbidden(path, fmt.Sprintf("revision %d can not be removed, "+
+				"only the oldest revisions are removed", old.Revision)))
+		}
+	}
+	return allErrs
+}
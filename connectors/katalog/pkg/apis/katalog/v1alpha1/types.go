@@ -11,6 +11,9 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Valid",type=string,JSONPath=`.status.conditions[?(@.type=="Valid")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // Asset defines an asset in the catalog
 type Asset struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -18,6 +21,9 @@ type Asset struct {
 
 	// +required
 	Spec AssetSpec `json:"spec"`
+
+	// +optional
+	Status AssetStatus `json:"status,omitempty"`
 }
 
 type AssetSpec struct {
@@ -37,6 +43,51 @@ type SecretRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// ValidCondition is the type of the condition that reports whether the asset is valid according to the taxonomy
+const ValidCondition = "Valid"
+
+// AssetStatus reports the validation of the asset and its usage
+type AssetStatus struct {
+	// ObservedGeneration is the generation of the spec that has been validated
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions include the Valid condition, whose status is False if the asset does not conform to the taxonomy
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ValidationErrors are the errors of the validation of the asset against the taxonomy
+	// +optional
+	ValidationErrors []string `json:"validationErrors,omitempty"`
+
+	// ReferencingApplications are the FybrikApplications that currently reference the asset
+	// +optional
+	ReferencingApplications []ApplicationReference `json:"referencingApplications,omitempty"`
+
+	// Revisions is the history of the asset metadata, ordered from the oldest to the latest revision.
+	// Recorded revisions are immutable, and only the oldest revisions are dropped to keep at most MaxAssetRevisions.
+	// +optional
+	Revisions []AssetRevision `json:"revisions,omitempty"`
+}
+
+// AssetRevision is the metadata of an asset at a generation of the asset
+type AssetRevision struct {
+	// Revision is the generation of the asset that had the metadata
+	Revision int64 `json:"revision"`
+	// Time at which the revision has been recorded
+	Time metav1.Time `json:"time"`
+	// Metadata of the asset at the revision
+	Metadata datacatalog.ResourceMetadata `json:"metadata"`
+}
+
+// ApplicationReference is a reference to a FybrikApplication
+type ApplicationReference struct {
+	// Name of the FybrikApplication
+	Name string `json:"name"`
+	// Namespace of the FybrikApplication
+	Namespace string `json:"namespace"`
+}
+
 // +kubebuilder:object:root=true
 // AssetList contains a list of Asset resources
 type AssetList struct {
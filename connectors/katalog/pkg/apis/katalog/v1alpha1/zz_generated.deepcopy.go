@@ -8,15 +8,32 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationReference) DeepCopyInto(out *ApplicationReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationReference.
+func (in *ApplicationReference) DeepCopy() *ApplicationReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Asset) DeepCopyInto(out *Asset) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Asset.
@@ -69,6 +86,23 @@ func (in *AssetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetRevision) DeepCopyInto(out *AssetRevision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetRevision.
+func (in *AssetRevision) DeepCopy() *AssetRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AssetSpec) DeepCopyInto(out *AssetSpec) {
 	*out = *in
@@ -87,6 +121,45 @@ func (in *AssetSpec) DeepCopy() *AssetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssetStatus) DeepCopyInto(out *AssetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ValidationErrors != nil {
+		in, out := &in.ValidationErrors, &out.ValidationErrors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReferencingApplications != nil {
+		in, out := &in.ReferencingApplications, &out.ReferencingApplications
+		*out = make([]ApplicationReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]AssetRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssetStatus.
+func (in *AssetStatus) DeepCopy() *AssetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AssetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretRef) DeepCopyInto(out *SecretRef) {
 	*out = *in
@@ -0,0 +1,169 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apiserver/pkg/authentication/user"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+)
+
+// Verbs of the operations of the connector on the Asset resources
+const (
+	getVerb    = "get"
+	createVerb = "create"
+	deleteVerb = "delete"
+	updateVerb = "update"
+)
+
+const (
+	assetResource     = "assets"
+	authorizationKey  = "authorization"
+	bearerTokenPrefix = "Bearer "
+	// certificateUserPrefix is the prefix of the users that are identified by client certificates
+	certificateUserPrefix = "katalog-certificate:"
+)
+
+// Authorizer decides whether the caller of an operation is allowed to perform the verb on the assets of a namespace.
+// It returns a gRPC status error with the status code of the REST API if the caller is not allowed.
+type Authorizer interface {
+	Authorize(ctx context.Context, verb, namespace, name string) error
+}
+
+// SubjectAccessReviewAuthorizer authorizes the callers with the RBAC rules of the cluster on the Asset resources,
+// so the callers that can get the Asset resources of a namespace, e.g., with the katalog-viewer role, can get
+// their information from the connector.
+// The caller is identified by a bearer token, which is authenticated with a TokenReview, or by the client
+// certificate of a mutual TLS connection, whose common name prefixed with certificateUserPrefix is the user.
+// The certificates are not issued by the cluster, so their subjects must not name the users and groups of the
+// cluster: the organizations are not used as groups, and the prefix sets the users apart from the users of the cluster.
+// Callers that present neither are anonymous.
+type SubjectAccessReviewAuthorizer struct {
+	client kclient.Client
+}
+
+func NewSubjectAccessReviewAuthorizer(client kclient.Client) *SubjectAccessReviewAuthorizer {
+	return &SubjectAccessReviewAuthorizer{client: client}
+}
+
+func (a *SubjectAccessReviewAuthorizer) Authorize(ctx context.Context, verb, namespace, name string) error {
+	userInfo, err := a.authenticate(ctx, callerFromContext(ctx))
+	if err != nil {
+		return err
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			Extra:  extra(userInfo.Extra),
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     v1alpha1.GroupVersion.Group,
+				Resource:  assetResource,
+				Name:      name,
+			},
+		},
+	}
+	if err = a.client.Create(ctx, review); err != nil {
+		return rpc.NewError(http.StatusInternalServerError, "failed to review the access of "+userInfo.Username+": "+err.Error())
+	}
+	if !review.Status.Allowed {
+		message := fmt.Sprintf("user %s is not allowed to %s assets in namespace %s", userInfo.Username, verb, namespace)
+		if review.Status.Reason != "" {
+			message += ": " + review.Status.Reason
+		}
+		return rpc.NewError(http.StatusForbidden, message)
+	}
+	return nil
+}
+
+// authenticate returns the user of the caller
+func (a *SubjectAccessReviewAuthorizer) authenticate(ctx context.Context, c caller) (*authenticationv1.UserInfo, error) {
+	switch {
+	case c.token != "":
+		review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: c.token}}
+		if err := a.client.Create(ctx, review); err != nil {
+			return nil, rpc.NewError(http.StatusInternalServerError, "failed to review the bearer token: "+err.Error())
+		}
+		if !review.Status.Authenticated {
+			return nil, rpc.NewError(http.StatusUnauthorized, "invalid bearer token: "+review.Status.Error)
+		}
+		return &review.Status.User, nil
+	case c.certificate != nil:
+		return &authenticationv1.UserInfo{
+			Username: certificateUserPrefix + c.certificate.Subject.CommonName,
+			Groups:   []string{user.AllAuthenticated},
+		}, nil
+	default:
+		return &authenticationv1.UserInfo{Username: user.Anonymous, Groups: []string{user.AllUnauthenticated}}, nil
+	}
+}
+
+func extra(values map[string]authenticationv1.ExtraValue) map[string]authorizationv1.ExtraValue {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]authorizationv1.ExtraValue, len(values))
+	for key, value := range values {
+		result[key] = authorizationv1.ExtraValue(value)
+	}
+	return result
+}
+
+// caller holds the credentials that identify the caller of an operation
+type caller struct {
+	token       string
+	certificate *x509.Certificate
+}
+
+type callerKey struct{}
+
+// identifyCaller adds the credentials of the caller of a REST request to the context of the request
+func identifyCaller(c *gin.Context) {
+	var identified caller
+	if header := c.GetHeader(authorizationKey); strings.HasPrefix(header, bearerTokenPrefix) {
+		identified.token = strings.TrimPrefix(header, bearerTokenPrefix)
+	}
+	// only certificates that have been verified by a mutual TLS handshake identify the caller
+	if c.Request.TLS != nil && len(c.Request.TLS.VerifiedChains) > 0 {
+		identified.certificate = c.Request.TLS.VerifiedChains[0][0]
+	}
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), callerKey{}, identified))
+	c.Next()
+}
+
+// callerFromContext returns the credentials of the caller of a REST request,
+// or the credentials in the metadata and the connection of a gRPC request
+func callerFromContext(ctx context.Context) caller {
+	if identified, ok := ctx.Value(callerKey{}).(caller); ok {
+		return identified
+	}
+	var identified caller
+	if values := metadata.ValueFromIncomingContext(ctx, authorizationKey); len(values) > 0 &&
+		strings.HasPrefix(values[0], bearerTokenPrefix) {
+		identified.token = strings.TrimPrefix(values[0], bearerTokenPrefix)
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, isTLS := p.AuthInfo.(credentials.TLSInfo); isTLS && len(tlsInfo.State.VerifiedChains) > 0 {
+			identified.certificate = tlsInfo.State.VerifiedChains[0][0]
+		}
+	}
+	return identified
+}
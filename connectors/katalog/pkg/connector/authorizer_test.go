@@ -0,0 +1,128 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/metadata"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// reviewingClient reviews the token of alice, and allows alice to get the assets of the demo namespace
+type reviewingClient struct {
+	kclient.Client
+}
+
+func (c *reviewingClient) Create(ctx context.Context, obj kclient.Object, opts ...kclient.CreateOption) error {
+	switch review := obj.(type) {
+	case *authenticationv1.TokenReview:
+		if review.Spec.Token == "alice-token" {
+			review.Status.Authenticated = true
+			review.Status.User = authenticationv1.UserInfo{Username: "alice"}
+		}
+		return nil
+	case *authorizationv1.SubjectAccessReview:
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attributes.Namespace == "demo" && attributes.Verb == getVerb &&
+			attributes.Group == v1alpha1.GroupVersion.Group && attributes.Resource == assetResource
+		return nil
+	default:
+		return c.Client.Create(ctx, obj, opts...)
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+
+	schema := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(schema)
+	assets := []kclient.Object{
+		&v1alpha1.Asset{ObjectMeta: v1.ObjectMeta{Namespace: "demo", Name: "asset"}},
+		&v1alpha1.Asset{ObjectMeta: v1.ObjectMeta{Namespace: "other", Name: "asset"}},
+	}
+	client := &reviewingClient{Client: fake.NewClientBuilder().WithScheme(schema).WithObjects(assets...).Build()}
+	handler := NewHandler(client)
+	handler.Authorizer = NewSubjectAccessReviewAuthorizer(client)
+	router := NewRouter(handler)
+
+	getAssetInfo := func(id, authorization string) *httptest.ResponseRecorder {
+		requestBytes, err := json.Marshal(&datacatalog.GetAssetRequest{AssetID: taxonomy.AssetID(id), OperationType: datacatalog.READ})
+		g.Expect(err).To(BeNil())
+		request := httptest.NewRequest(http.MethodPost, "/getAssetInfo", bytes.NewBuffer(requestBytes))
+		if authorization != "" {
+			request.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, request)
+		return w
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		g.Expect(getAssetInfo("demo/asset", "Bearer alice-token").Code).To(Equal(http.StatusOK))
+	})
+	t.Run("other namespace", func(t *testing.T) {
+		w := getAssetInfo("other/asset", "Bearer alice-token")
+		g.Expect(w.Code).To(Equal(http.StatusForbidden))
+		g.Expect(w.Body.String()).To(ContainSubstring("user alice is not allowed to get assets in namespace other"))
+	})
+	t.Run("anonymous", func(t *testing.T) {
+		g.Expect(getAssetInfo("demo/asset", "").Code).To(Equal(http.StatusForbidden))
+	})
+	t.Run("invalid token", func(t *testing.T) {
+		g.Expect(getAssetInfo("demo/asset", "Bearer mallory-token").Code).To(Equal(http.StatusUnauthorized))
+	})
+	t.Run("unknown asset is not revealed", func(t *testing.T) {
+		g.Expect(getAssetInfo("other/unknown", "Bearer alice-token").Code).To(Equal(http.StatusForbidden))
+	})
+	t.Run("create", func(t *testing.T) {
+		_, err := handler.CreateAsset(context.Background(), &datacatalog.CreateAssetRequest{DestinationCatalogID: "demo"}, "")
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).To(ContainSubstring("user system:anonymous is not allowed to create assets in namespace demo"))
+	})
+}
+
+func TestCallerFromContext(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// gRPC requests are identified by the token in the metadata
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationKey, "Bearer token"))
+	g.Expect(callerFromContext(ctx)).To(Equal(caller{token: "token"}))
+
+	// REST requests are identified by the verified client certificate
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "fybrik-manager", Organization: []string{"system:masters"}}}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/getAssetInfo", http.NoBody)
+	c.Request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+	identifyCaller(c)
+	g.Expect(callerFromContext(c.Request.Context())).To(Equal(caller{certificate: certificate}))
+
+	userInfo, err := (&SubjectAccessReviewAuthorizer{}).authenticate(context.Background(), caller{certificate: certificate})
+	g.Expect(err).ToNot(HaveOccurred())
+	// the certificate cannot name a user or a group of the cluster
+	g.Expect(userInfo.Username).To(Equal("katalog-certificate:fybrik-manager"))
+	g.Expect(userInfo.Groups).To(ConsistOf("system:authenticated"))
+}
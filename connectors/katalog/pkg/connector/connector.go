@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +15,12 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/utils"
@@ -26,11 +29,24 @@ import (
 
 const (
 	FybrikAssetPrefix = "fybrik-"
+	// IdempotencyKeyAnnotation holds the idempotency key of the request that created the asset
+	IdempotencyKeyAnnotation = "katalog.fybrik.io/idempotency-key"
+	idempotencyKeyHashLength = 10
 )
 
+// The operations of the handler implement the gRPC service of the data catalog,
+// and are called by the handlers of the REST API.
+// The creds argument of the operations, from the X-Request-Datacatalog-Cred header, is not used: it is the Vault path
+// of the credentials that the application provides for catalogs that authenticate their users, while the assets of
+// Katalog are Kubernetes resources read with the credentials of the connector. The path is chosen by the caller and
+// proves no identity, so callers are authorized by the Authorizer with their bearer token or client certificate.
+var _ rpc.DataCatalogServer = (*Handler)(nil)
+
 type Handler struct {
 	client kclient.Client
 	Log    zerolog.Logger
+	// Authorizer authorizes the callers of the operations. All the callers are allowed if it is not set.
+	Authorizer Authorizer
 }
 
 func NewHandler(client kclient.Client) *Handler {
@@ -48,23 +64,33 @@ func (r *Handler) getAssetInfo(c *gin.Context) {
 		r.reportError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	response, err := r.GetAssetInfo(c.Request.Context(), &request, c.GetHeader(rpc.DataCatalogCredsKey))
+	if err != nil {
+		r.reportStatusError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
 
+// GetAssetInfo returns the metadata and the connection details of an asset
+func (r *Handler) GetAssetInfo(ctx context.Context, request *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
 	splittedID := strings.SplitN(string(request.AssetID), "/", 2)
 	if len(splittedID) != 2 {
 		errorMessage := fmt.Sprintf("request has an invalid asset ID %s (must be in namespace/name format)", request.AssetID)
-		r.reportError(c, http.StatusBadRequest, errorMessage)
-		return
+		return nil, rpc.NewError(http.StatusBadRequest, errorMessage)
 	}
 	namespace, name := splittedID[0], splittedID[1]
+	if err := r.authorize(ctx, getVerb, namespace, name); err != nil {
+		return nil, err
+	}
 
 	asset := &v1alpha1.Asset{}
-	if err := r.client.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
 		if errors.IsNotFound(err) {
-			r.reportError(c, http.StatusNotFound, err.Error())
-			return
+			return nil, rpc.NewError(http.StatusNotFound, err.Error())
 		}
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
 	}
 
 	secretNamespace := namespace
@@ -72,13 +98,50 @@ func (r *Handler) getAssetInfo(c *gin.Context) {
 		secretNamespace = asset.Spec.SecretRef.Namespace
 	}
 
-	response := datacatalog.GetAssetResponse{
-		ResourceMetadata: asset.Spec.Metadata,
+	// the revisions of the metadata are the generations of the asset
+	revision := asset.Generation
+	if request.Revision != "" {
+		var err error
+		if revision, err = strconv.ParseInt(request.Revision, 10, 64); err != nil {
+			return nil, rpc.NewError(http.StatusBadRequest, "request has an invalid revision "+request.Revision)
+		}
+	}
+	metadata, found := asset.MetadataAt(revision)
+	if !found {
+		errorMessage := fmt.Sprintf("revision %d of asset %s is not found", revision, request.AssetID)
+		return nil, rpc.NewError(http.StatusNotFound, errorMessage)
+	}
+
+	response := &datacatalog.GetAssetResponse{
+		ResourceMetadata: *metadata,
 		Details:          asset.Spec.Details,
 		Credentials:      vault.PathForReadingKubeSecret(secretNamespace, asset.Spec.SecretRef.Name),
+		Revision:         strconv.FormatInt(revision, 10),
 	}
+	return response, nil
+}
+
+// recordRevision records the current metadata of the asset in the history of its revisions
+func (r *Handler) recordRevision(ctx context.Context, asset *v1alpha1.Asset) error {
+	revision, metadata := asset.Generation, asset.Spec.Metadata.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &v1alpha1.Asset{}
+		if err := r.client.Get(ctx, kclient.ObjectKeyFromObject(asset), latest); err != nil {
+			return err
+		}
+		if !latest.Status.AddRevision(revision, metadata, v1.Now()) {
+			return nil
+		}
+		return r.client.Status().Update(ctx, latest)
+	})
+}
 
-	c.JSON(http.StatusOK, &response)
+// authorize returns an error if the caller is not allowed to perform the verb on the assets of the namespace
+func (r *Handler) authorize(ctx context.Context, verb, namespace, name string) error {
+	if r.Authorizer == nil {
+		return nil
+	}
+	return r.Authorizer.Authorize(ctx, verb, namespace, name)
 }
 
 func (r *Handler) reportError(c *gin.Context, httpCode int, errorMessage string) {
@@ -86,10 +149,13 @@ func (r *Handler) reportError(c *gin.Context, httpCode int, errorMessage string)
 	c.JSON(httpCode, gin.H{"error": errorMessage})
 }
 
-// Enables writing of assets to katalog. The different flows supported are:
-// (a) When DestinationAssetID is specified then an asset id is created with name: <DestinationAssetID>
-// (b) When DestinationAssetID is specified then an asset is created with name: <DestinationAssetID>-<Kubernetes Generated Random String>
-// (c) When DestinationAssetID is not specified then an asset is created with name: fybrik-<Kubernetes Generated Random String>
+// reportStatusError reports an error returned by an operation with its status code
+func (r *Handler) reportStatusError(c *gin.Context, err error) {
+	httpCode, errorMessage := rpc.HTTPStatus(err)
+	r.Log.Warn().CallerSkipFrame(1).Msg(errorMessage)
+	c.JSON(httpCode, gin.H{"error": errorMessage})
+}
+
 func (r *Handler) createAsset(c *gin.Context) {
 	// Parse request
 	var request datacatalog.CreateAssetRequest
@@ -98,21 +164,56 @@ func (r *Handler) createAsset(c *gin.Context) {
 		r.reportError(c, http.StatusBadRequest, "Error during ShouldBindJSON in createAsset.")
 		return
 	}
+	response, err := r.createAssetResult(c.Request.Context(), &request)
+	if err != nil {
+		r.reportStatusError(c, err)
+		return
+	}
+	// an asset that has been created for a previous request with the same idempotency key is returned with 200
+	if response.existing {
+		c.JSON(http.StatusOK, &response.CreateAssetResponse)
+		return
+	}
+	c.JSON(http.StatusCreated, &response.CreateAssetResponse)
+}
+
+// createAssetResult is the response to a request to create an asset, and whether the asset already existed
+type createAssetResult struct {
+	datacatalog.CreateAssetResponse
+	existing bool
+}
+
+// CreateAsset enables writing of assets to katalog. The different flows supported are:
+// (a) When DestinationAssetID is specified then an asset id is created with name: <DestinationAssetID>
+// (b) When DestinationAssetID is specified then an asset is created with name: <DestinationAssetID>-<Kubernetes Generated Random String>
+// (c) When DestinationAssetID is not specified then an asset is created with name: fybrik-<Kubernetes Generated Random String>
+// When IdempotencyKey is specified, the random string is replaced by a hash of the key, and the asset that has been
+// created with the same key is returned instead of creating a new asset.
+func (r *Handler) CreateAsset(ctx context.Context, request *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
+	response, err := r.createAssetResult(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &response.CreateAssetResponse, nil
+}
 
+func (r *Handler) createAssetResult(ctx context.Context, request *datacatalog.CreateAssetRequest) (*createAssetResult, error) {
 	logging.LogStructure("CreateAssetRequest object received:", request, &r.Log, zerolog.DebugLevel, false, false)
 
 	if request.DestinationCatalogID == "" {
 		errString := "Invalid DestinationCatalogID in request."
 		r.Log.Info().Msg(errString)
-		r.reportError(c, http.StatusBadRequest, errString)
-		return
+		return nil, rpc.NewError(http.StatusBadRequest, errString)
+	}
+	if err := r.authorize(ctx, createVerb, request.DestinationCatalogID, ""); err != nil {
+		return nil, err
 	}
 
 	secretName, secretNamespace, err := vault.GetKubeSecretDetailsFromVaultPath(request.Credentials)
 	if err != nil {
 		r.Log.Info().Msg(err.Error())
-		r.reportError(c, http.StatusInternalServerError, "Error getting kube secret from vaultpath")
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error getting kube secret from vaultpath")
 	}
 
 	assetPrefix := FybrikAssetPrefix
@@ -128,27 +229,49 @@ func (r *Handler) createAsset(c *gin.Context) {
 			Details:   request.Details,
 		},
 	}
+	if request.IdempotencyKey != "" {
+		asset.ObjectMeta.GenerateName = ""
+		asset.ObjectMeta.Name = assetPrefix + utils.Hash(request.IdempotencyKey, idempotencyKeyHashLength)
+		asset.ObjectMeta.Annotations = map[string]string{IdempotencyKeyAnnotation: request.IdempotencyKey}
+	}
 
 	logging.LogStructure("Fybrik Asset to be created in Katalog:", asset, &r.Log, zerolog.DebugLevel, false, false)
 
-	err = r.client.Create(context.Background(), asset)
+	err = r.client.Create(ctx, asset)
+	if errors.IsAlreadyExists(err) && request.IdempotencyKey != "" {
+		return r.existingAsset(ctx, asset.Namespace, asset.Name, request.IdempotencyKey)
+	}
 	if err != nil {
 		r.Log.Info().Msg(err.Error())
-		r.reportError(c, http.StatusInternalServerError, "Error during create asset.")
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error during create asset.")
 	}
 	logging.LogStructure("Created Asset: ", asset, &r.Log, zerolog.DebugLevel, false, false)
-
-	response := datacatalog.CreateAssetResponse{
-		AssetID: asset.ObjectMeta.Name,
+	if err = r.recordRevision(ctx, asset); err != nil {
+		r.Log.Info().Msg(err.Error())
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error while recording the revision of the created asset")
 	}
+
+	response := &createAssetResult{CreateAssetResponse: datacatalog.CreateAssetResponse{AssetID: asset.ObjectMeta.Name}}
 	r.Log.Info().Msg(
 		"Sending response from Katalog Connector with created asset ID: " + response.AssetID)
+	return response, nil
+}
 
-	c.JSON(http.StatusCreated, &response)
+// existingAsset returns the asset that has been created for a previous request with the same idempotency key
+func (r *Handler) existingAsset(ctx context.Context, namespace, name, idempotencyKey string) (*createAssetResult, error) {
+	existing := &v1alpha1.Asset{}
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, existing); err != nil {
+		r.Log.Info().Msg(err.Error())
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error during create asset.")
+	}
+	if existing.Annotations[IdempotencyKeyAnnotation] != idempotencyKey {
+		return nil, rpc.NewError(http.StatusConflict, fmt.Sprintf("asset %s/%s exists with a different idempotency key", namespace, name))
+	}
+	response := &createAssetResult{CreateAssetResponse: datacatalog.CreateAssetResponse{AssetID: existing.Name}, existing: true}
+	r.Log.Info().Msg("Sending response from Katalog Connector with the asset ID created for the idempotency key: " + response.AssetID)
+	return response, nil
 }
 
-// Enables deletion of assets to katalog.
 func (r *Handler) deleteAsset(c *gin.Context) {
 	// Parse request
 	var request datacatalog.DeleteAssetRequest
@@ -157,38 +280,50 @@ func (r *Handler) deleteAsset(c *gin.Context) {
 		r.reportError(c, http.StatusBadRequest, "Error during ShouldBindJSON in deleteAsset ")
 		return
 	}
+	response, err := r.DeleteAsset(c.Request.Context(), &request, c.GetHeader(rpc.DataCatalogCredsKey))
+	if err != nil {
+		r.reportStatusError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteAsset enables deletion of assets to katalog.
+func (r *Handler) DeleteAsset(ctx context.Context, request *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
 	logging.LogStructure("DeleteAssetRequest object received:", request, &r.Log, zerolog.DebugLevel, false, false)
 
 	splittedID := strings.SplitN(string(request.AssetID), "/", 2)
 	if len(splittedID) != 2 {
 		errorMessage := fmt.Sprintf("DeleteAssetRequest has an invalid asset ID %s (must be in namespace/name format)", request.AssetID)
-		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
-		return
+		return nil, rpc.NewError(http.StatusBadRequest, errorMessage)
 	}
 	namespace, name := splittedID[0], splittedID[1]
+	if err := r.authorize(ctx, deleteVerb, namespace, name); err != nil {
+		return nil, err
+	}
 
 	asset := &v1alpha1.Asset{}
-	if err := r.client.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
 		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error while getting asset information"})
-		return
+		if errors.IsNotFound(err) {
+			return nil, rpc.NewError(http.StatusNotFound, "Error: Asset Not Found during deleteAsset")
+		}
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error while getting asset information")
 	}
 
-	if err := r.client.Delete(context.Background(), asset); err != nil {
+	if err := r.client.Delete(ctx, asset); err != nil {
 		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"Error": "Error during deleting asset"})
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error during deleting asset")
 	}
-	response := datacatalog.DeleteAssetResponse{
+	response := &datacatalog.DeleteAssetResponse{
 		Status: "Deletion successful!",
 	}
 	r.Log.Info().Msg(
 		"Sending response from Katalog Connector with deleted asset ID: " + string(request.AssetID))
-
-	c.JSON(http.StatusOK, &response)
+	return response, nil
 }
 
-// Enables deletion of assets to katalog.
 func (r *Handler) updateAsset(c *gin.Context) {
 	// Parse request
 	var request datacatalog.UpdateAssetRequest
@@ -197,29 +332,45 @@ func (r *Handler) updateAsset(c *gin.Context) {
 		r.reportError(c, http.StatusBadRequest, "Error during ShouldBindJSON in updateAsset")
 		return
 	}
+	response, err := r.UpdateAsset(c.Request.Context(), &request, c.GetHeader(rpc.DataCatalogUpdateCredsKey))
+	if err != nil {
+		r.reportStatusError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateAsset enables updates of the metadata of assets in katalog.
+func (r *Handler) UpdateAsset(ctx context.Context, request *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
 	logging.LogStructure("UpdateAssetRequest received:", request, &r.Log, zerolog.DebugLevel, false, false)
 
 	splittedID := strings.SplitN(string(request.AssetID), "/", 2)
 	if len(splittedID) != 2 {
 		errorMessage := fmt.Sprintf("UpdateAssetRequest has an invalid asset ID %s (must be in namespace/name format)", request.AssetID)
-		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage})
-		return
+		return nil, rpc.NewError(http.StatusBadRequest, errorMessage)
 	}
 	namespace, name := splittedID[0], splittedID[1]
+	if err := r.authorize(ctx, updateVerb, namespace, name); err != nil {
+		return nil, err
+	}
 
 	r.Log.Info().Msg("Looking up asset: Namespace: " + namespace + ", name: " + name)
 
 	asset := &v1alpha1.Asset{}
-	if err := r.client.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, asset); err != nil {
+		r.Log.Info().Msg(err.Error())
 		if errors.IsNotFound(err) {
-			r.Log.Info().Msg(err.Error())
-			c.JSON(http.StatusNotFound, gin.H{"error": "Error: Asset Not Found during updateAsset"})
-			return
+			return nil, rpc.NewError(http.StatusNotFound, "Error: Asset Not Found during updateAsset")
 		}
-		errString := "Error reading asset information"
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error reading asset information")
+	}
+
+	// The current metadata is recorded before it is updated, so that the revisions that have been returned remain
+	// available after the update.
+	if err := r.recordRevision(ctx, asset); err != nil {
 		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": errString})
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error while recording the revision of the asset")
 	}
 
 	// A merge patch will preserve other fields modified at runtime.
@@ -229,16 +380,18 @@ func (r *Handler) updateAsset(c *gin.Context) {
 	asset.Spec.Metadata.Tags = request.Tags
 	asset.Spec.Metadata.Columns = request.Columns
 
-	if err := r.client.Patch(context.Background(), asset, patch); err != nil {
+	if err := r.client.Patch(ctx, asset, patch); err != nil {
 		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{" Error ": "Error while updating asset"})
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error while updating asset")
 	}
-	response := datacatalog.UpdateAssetResponse{
+	if err := r.recordRevision(ctx, asset); err != nil {
+		r.Log.Info().Msg(err.Error())
+		return nil, rpc.NewError(http.StatusInternalServerError, "Error while recording the revision of the updated asset")
+	}
+	response := &datacatalog.UpdateAssetResponse{
 		Status: "Updation successful!",
 	}
 	r.Log.Info().Msg(
 		"Sending response from Katalog Connector with updated asset ID: " + string(request.AssetID))
-
-	c.JSON(http.StatusOK, &response)
+	return response, nil
 }
@@ -21,11 +21,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
+	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/serde"
@@ -502,3 +504,135 @@ func TestCreateAndUpdateAsset(t *testing.T) {
 		// just for logging - end
 	})
 }
+
+func TestCreateAssetWithIdempotencyKey(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	createAssetReq := &datacatalog.CreateAssetRequest{
+		DestinationCatalogID: "fybrik-system",
+		DestinationAssetID:   "new-paysim-csv",
+		ResourceMetadata:     datacatalog.ResourceMetadata{Name: "new-paysim-csv"},
+		Details:              datacatalog.ResourceDetails{Connection: taxonomy.Connection{Name: "s3"}, DataFormat: "csv"},
+		Credentials:          "/v1/kubernetes-secrets/dummy-creds?namespace=dummy-namespace",
+		IdempotencyKey:       "1234/fybrik-notebook-sample/paysim-csv",
+	}
+	schema := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(schema)
+	client := fake.NewClientBuilder().WithScheme(schema).Build()
+	handler := NewHandler(client)
+	gin.SetMode(gin.TestMode)
+
+	createAsset := func(request *datacatalog.CreateAssetRequest) (int, string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		requestBytes, err := json.Marshal(request)
+		g.Expect(err).To(BeNil())
+		c.Request = httptest.NewRequest(http.MethodPost, "http://localhost/", bytes.NewBuffer(requestBytes))
+		handler.createAsset(c)
+		response := &datacatalog.CreateAssetResponse{}
+		_ = json.Unmarshal(w.Body.Bytes(), response)
+		return w.Code, response.AssetID
+	}
+
+	code, assetID := createAsset(createAssetReq)
+	g.Expect(code).To(Equal(http.StatusCreated))
+	g.Expect(assetID).To(HavePrefix("new-paysim-csv-"))
+
+	// a repeated request returns the existing asset
+	code, repeatedID := createAsset(createAssetReq)
+	g.Expect(code).To(Equal(http.StatusOK))
+	g.Expect(repeatedID).To(Equal(assetID))
+	assets := &v1alpha1.AssetList{}
+	g.Expect(client.List(context.Background(), assets)).To(Succeed())
+	g.Expect(assets.Items).To(HaveLen(1))
+	g.Expect(assets.Items[0].Annotations).To(HaveKeyWithValue(IdempotencyKeyAnnotation, createAssetReq.IdempotencyKey))
+
+	// a request with another key creates a new asset
+	otherReq := *createAssetReq
+	otherReq.IdempotencyKey = "5678/fybrik-notebook-sample/paysim-csv"
+	code, otherID := createAsset(&otherReq)
+	g.Expect(code).To(Equal(http.StatusCreated))
+	g.Expect(otherID).NotTo(Equal(assetID))
+
+	// an asset with the same name that has not been created with the key is not returned
+	existing := &v1alpha1.Asset{}
+	g.Expect(client.Get(context.Background(), types.NamespacedName{Namespace: "fybrik-system", Name: otherID}, existing)).To(Succeed())
+	existing.Annotations = nil
+	g.Expect(client.Update(context.Background(), existing)).To(Succeed())
+	code, _ = createAsset(&otherReq)
+	g.Expect(code).To(Equal(http.StatusConflict))
+}
+
+// generationClient sets the generation of the assets on create and patch, as the API server does
+type generationClient struct {
+	kclient.Client
+}
+
+func (c *generationClient) Create(ctx context.Context, obj kclient.Object, opts ...kclient.CreateOption) error {
+	obj.SetGeneration(1)
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *generationClient) Patch(ctx context.Context, obj kclient.Object, patch kclient.Patch, opts ...kclient.PatchOption) error {
+	obj.SetGeneration(obj.GetGeneration() + 1)
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestAssetRevisions(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schema := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(schema)
+	handler := NewHandler(&generationClient{Client: fake.NewClientBuilder().WithScheme(schema).Build()})
+	ctx := context.Background()
+
+	financeTags := func(finance bool) *taxonomy.Tags {
+		return &taxonomy.Tags{Properties: serde.Properties{Items: map[string]interface{}{"finance": finance}}}
+	}
+	created, err := handler.CreateAsset(ctx, &datacatalog.CreateAssetRequest{
+		DestinationCatalogID: "demo",
+		DestinationAssetID:   "asset",
+		ResourceMetadata:     datacatalog.ResourceMetadata{Name: "asset", Tags: financeTags(true)},
+		Credentials:          "/v1/kubernetes-secrets/creds?namespace=demo",
+	}, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	assetID := taxonomy.AssetID("demo/" + created.AssetID)
+
+	_, err = handler.UpdateAsset(ctx, &datacatalog.UpdateAssetRequest{AssetID: assetID, Name: "asset", Tags: financeTags(false)}, "")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	asset := &v1alpha1.Asset{}
+	g.Expect(handler.client.Get(ctx, types.NamespacedName{Namespace: "demo", Name: created.AssetID}, asset)).To(Succeed())
+	g.Expect(asset.Status.Revisions).To(HaveLen(2))
+	g.Expect(asset.Status.Revisions[0].Revision).To(Equal(int64(1)))
+	g.Expect(asset.Status.Revisions[1].Revision).To(Equal(int64(2)))
+
+	getAssetInfo := func(revision string) (*datacatalog.GetAssetResponse, error) {
+		return handler.GetAssetInfo(ctx, &datacatalog.GetAssetRequest{AssetID: assetID, OperationType: datacatalog.READ, Revision: revision}, "")
+	}
+
+	t.Run("latest revision", func(t *testing.T) {
+		response, err := getAssetInfo("")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(response.Revision).To(Equal("2"))
+		g.Expect(response.ResourceMetadata.Tags).To(Equal(financeTags(false)))
+	})
+	t.Run("previous revision", func(t *testing.T) {
+		response, err := getAssetInfo("1")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(response.Revision).To(Equal("1"))
+		g.Expect(response.ResourceMetadata.Tags).To(Equal(financeTags(true)))
+	})
+	t.Run("unknown revision", func(t *testing.T) {
+		_, err := getAssetInfo("5")
+		code, _ := rpc.HTTPStatus(err)
+		g.Expect(code).To(Equal(http.StatusNotFound))
+	})
+	t.Run("invalid revision", func(t *testing.T) {
+		_, err := getAssetInfo("latest")
+		code, _ := rpc.HTTPStatus(err)
+		g.Expect(code).To(Equal(http.StatusBadRequest))
+	})
+}
@@ -3,11 +3,19 @@
 
 package connector
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// ServiceName is the name of the katalog service in trace spans
+const ServiceName = "katalog-connector"
 
 // NewRouter returns a new router.
 func NewRouter(handler *Handler) *gin.Engine {
 	router := gin.Default()
+	router.Use(otelgin.Middleware(ServiceName))
+	router.Use(identifyCaller)
 	router.POST("/getAssetInfo", handler.getAssetInfo)
 	router.POST("/createAsset", handler.createAsset)
 	router.DELETE("/deleteAsset", handler.deleteAsset)
@@ -0,0 +1,182 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	// assetIDsField indexes the FybrikApplications by the identifiers of the assets that they reference
+	assetIDsField = "katalog.assetIDs"
+
+	validReason   = "Valid"
+	invalidReason = "Invalid"
+)
+
+// AssetReconciler reports in the status of the Asset resources whether they conform to the taxonomy,
+// and which FybrikApplications reference them
+type AssetReconciler struct {
+	client.Client
+	Log          zerolog.Logger
+	TaxonomyFile string
+}
+
+// NewAssetReconciler creates a new reconciler for Assets
+func NewAssetReconciler(mgr ctrl.Manager) *AssetReconciler {
+	return &AssetReconciler{
+		Client:       mgr.GetClient(),
+		Log:          logging.LogInit(logging.CONTROLLER, "katalog-asset"),
+		TaxonomyFile: v1alpha1.TaxonomyFile,
+	}
+}
+
+// Reconcile validates an Asset and finds the FybrikApplications that reference it
+func (r *AssetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.With().Str(logging.DATASETID, req.NamespacedName.String()).Logger()
+
+	asset := &v1alpha1.Asset{}
+	if err := r.Get(ctx, req.NamespacedName, asset); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !asset.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	observedStatus := asset.Status.DeepCopy()
+
+	// the asset is validated in every reconcile, since assets that have been admitted may not conform to a
+	// taxonomy that has been deployed later
+	if err := r.validate(asset); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// the metadata of assets that are edited directly, rather than with the connector, is also kept in the history
+	asset.Status.AddRevision(asset.Generation, &asset.Spec.Metadata, metav1.Now())
+
+	applications, err := r.referencingApplications(ctx, asset)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	asset.Status.ReferencingApplications = applications
+
+	if equality.Semantic.DeepEqual(observedStatus, &asset.Status) {
+		return ctrl.Result{}, nil
+	}
+	log.Debug().Msg("updating the status of the asset")
+	return ctrl.Result{}, r.Status().Update(ctx, asset)
+}
+
+// validate sets the validation errors and the Valid condition of the asset
+func (r *AssetReconciler) validate(asset *v1alpha1.Asset) error {
+	errs, err := asset.ValidateAssetFields(r.TaxonomyFile)
+	if err != nil {
+		return err
+	}
+	asset.Status.ObservedGeneration = asset.Generation
+	asset.Status.ValidationErrors = nil
+	for _, e := range errs {
+		asset.Status.ValidationErrors = append(asset.Status.ValidationErrors, e.Error())
+	}
+	condition := metav1.Condition{
+		Type:               v1alpha1.ValidCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             validReason,
+		ObservedGeneration: asset.Generation,
+	}
+	if len(errs) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = invalidReason
+		condition.Message = "the asset does not conform to the taxonomy: " + strings.Join(asset.Status.ValidationErrors, "; ")
+	}
+	meta.SetStatusCondition(&asset.Status.Conditions, condition)
+	return nil
+}
+
+// referencingApplications returns the FybrikApplications that reference the asset, sorted by namespace and name
+func (r *AssetReconciler) referencingApplications(ctx context.Context, asset *v1alpha1.Asset) ([]v1alpha1.ApplicationReference, error) {
+	var applicationList fapp.FybrikApplicationList
+	assetID := asset.Namespace + "/" + asset.Name
+	if err := r.List(ctx, &applicationList, client.MatchingFields{assetIDsField: assetID}); err != nil {
+		return nil, err
+	}
+	var applications []v1alpha1.ApplicationReference
+	for ind := range applicationList.Items {
+		application := &applicationList.Items[ind]
+		if application.DeletionTimestamp.IsZero() {
+			applications = append(applications, v1alpha1.ApplicationReference{Name: application.Name, Namespace: application.Namespace})
+		}
+	}
+	sort.Slice(applications, func(i, j int) bool {
+		if applications[i].Namespace != applications[j].Namespace {
+			return applications[i].Namespace < applications[j].Namespace
+		}
+		return applications[i].Name < applications[j].Name
+	})
+	return applications, nil
+}
+
+// AssetIDs returns the identifiers of the assets that a FybrikApplication references: the datasets that it reads
+// and the assets that have been cataloged for the datasets that it writes
+func AssetIDs(application *fapp.FybrikApplication) []string {
+	ids := []string{}
+	for _, data := range application.Spec.Data {
+		if !data.Requirements.FlowParams.IsNewDataSet {
+			ids = append(ids, data.DataSetID)
+		}
+	}
+	for _, state := range application.Status.AssetStates {
+		if state.CatalogedAsset != "" {
+			ids = append(ids, state.CatalogedAsset)
+		}
+	}
+	return ids
+}
+
+// referencedAssets returns reconcile requests for the assets that a FybrikApplication references,
+// so that their status is updated when the application changes or is deleted
+func (r *AssetReconciler) referencedAssets(obj client.Object) []reconcile.Request {
+	application, ok := obj.(*fapp.FybrikApplication)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, id := range AssetIDs(application) {
+		// the identifiers of the assets in katalog are in namespace/name format
+		if namespace, name, found := strings.Cut(id, "/"); found && !strings.Contains(name, "/") {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+		}
+	}
+	return requests
+}
+
+// SetupWithManager registers the Asset controller
+func (r *AssetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &fapp.FybrikApplication{}, assetIDsField,
+		func(obj client.Object) []string {
+			return AssetIDs(obj.(*fapp.FybrikApplication))
+		}); err != nil {
+		return err
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Asset{}).
+		Watches(&source.Kind{Type: &fapp.FybrikApplication{}}, handler.EnqueueRequestsFromMapFunc(r.referencedAssets)).
+		Complete(r)
+}
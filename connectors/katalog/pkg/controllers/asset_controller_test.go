@@ -0,0 +1,120 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/connectors/katalog/pkg/apis/katalog/v1alpha1"
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+)
+
+const taxonomyFile = "../../../../charts/fybrik/files/taxonomy/datacatalog.json"
+
+// indexingClient lists the FybrikApplications that match the asset identifiers field,
+// since the fake client does not support field indexes
+type indexingClient struct {
+	client.Client
+}
+
+func (c *indexingClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	listOptions := &client.ListOptions{}
+	listOptions.ApplyOptions(opts)
+	if err := c.Client.List(ctx, list, &client.ListOptions{Namespace: listOptions.Namespace}); err != nil {
+		return err
+	}
+	applications, ok := list.(*fapp.FybrikApplicationList)
+	if !ok || listOptions.FieldSelector == nil {
+		return nil
+	}
+	assetID, _ := listOptions.FieldSelector.RequiresExactMatch(assetIDsField)
+	matching := []fapp.FybrikApplication{}
+	for ind := range applications.Items {
+		for _, id := range AssetIDs(&applications.Items[ind]) {
+			if id == assetID {
+				matching = append(matching, applications.Items[ind])
+				break
+			}
+		}
+	}
+	applications.Items = matching
+	return nil
+}
+
+func application(namespace, name string, data ...fapp.DataContext) *fapp.FybrikApplication {
+	return &fapp.FybrikApplication{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       fapp.FybrikApplicationSpec{Data: data},
+	}
+}
+
+func TestAssetStatus(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schema := runtime.NewScheme()
+	g.Expect(v1alpha1.AddToScheme(schema)).To(Succeed())
+	g.Expect(fapp.AddToScheme(schema)).To(Succeed())
+
+	// the s3 connection of the asset misses the bucket
+	asset := &v1alpha1.Asset{ObjectMeta: metav1.ObjectMeta{Namespace: "demo", Name: "asset", Generation: 1}}
+	g.Expect(yaml.Unmarshal([]byte(`
+details:
+  dataFormat: csv
+  connection:
+    name: s3
+    s3:
+      endpoint: "http://localstack.fybrik-notebook-sample.svc.cluster.local:4566"
+      object_key: "PS_20174392719_1491204439457_log.csv"
+metadata:
+  name: asset
+secretRef:
+  name: asset-creds
+`), &asset.Spec)).To(Succeed())
+
+	newDataSet := fapp.DataContext{DataSetID: "demo/asset"}
+	newDataSet.Requirements.FlowParams.IsNewDataSet = true
+	objects := []client.Object{
+		asset,
+		application("apps", "reader", fapp.DataContext{DataSetID: "demo/asset"}),
+		application("demo", "notebook", fapp.DataContext{DataSetID: "demo/asset"}, fapp.DataContext{DataSetID: "demo/other"}),
+		application("demo", "writer", newDataSet),
+		application("demo", "other", fapp.DataContext{DataSetID: "demo/other"}),
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(schema).WithObjects(objects...).Build()
+	r := &AssetReconciler{Client: &indexingClient{Client: fakeClient}, Log: zerolog.Nop(), TaxonomyFile: taxonomyFile}
+
+	key := types.NamespacedName{Namespace: "demo", Name: "asset"}
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	reconciled := &v1alpha1.Asset{}
+	g.Expect(fakeClient.Get(context.Background(), key, reconciled)).To(Succeed())
+	g.Expect(reconciled.Status.ObservedGeneration).To(Equal(int64(1)))
+	g.Expect(reconciled.Status.ValidationErrors).To(ContainElement(ContainSubstring("spec.details.connection.s3")))
+	g.Expect(meta.IsStatusConditionFalse(reconciled.Status.Conditions, v1alpha1.ValidCondition)).To(BeTrue())
+	g.Expect(reconciled.Status.ReferencingApplications).To(Equal([]v1alpha1.ApplicationReference{
+		{Namespace: "apps", Name: "reader"},
+		{Namespace: "demo", Name: "notebook"},
+	}))
+
+	// the applications that write the asset reference it once it has been cataloged
+	writer := &fapp.FybrikApplication{}
+	g.Expect(fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "demo", Name: "writer"}, writer)).To(Succeed())
+	writer.Status.AssetStates = map[string]fapp.AssetState{"demo/asset": {CatalogedAsset: "demo/asset"}}
+	g.Expect(fakeClient.Status().Update(context.Background(), writer)).To(Succeed())
+	g.Expect(r.referencedAssets(writer)).To(ConsistOf(ctrl.Request{NamespacedName: key}))
+}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,9 +16,11 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 const (
@@ -46,6 +49,9 @@ func NewConnectorController(opaServerURL string) (*ConnectorController, error) {
 		}
 	}
 
+	// propagate the trace context to the OPA server
+	retryClient.HTTPClient = tracing.WrapClient(retryClient.HTTPClient)
+
 	return &ConnectorController{
 		OpaServerURL: opaServerURL,
 		OpaClient:    retryClient,
@@ -60,48 +66,72 @@ func (r *ConnectorController) GetPoliciesDecisions(c *gin.Context) {
 		r.reportError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	response, err := r.getPoliciesDecisions(c.Request.Context(), &request)
+	if err != nil {
+		httpCode, errorMessage := rpc.HTTPStatus(err)
+		r.reportError(c, httpCode, errorMessage)
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// getPoliciesDecisions evaluates the request with OPA. The errors are gRPC status errors with the status code of
+// the REST API, so that both transports report them.
+func (r *ConnectorController) getPoliciesDecisions(ctx context.Context,
+	request *policymanager.GetPolicyDecisionsRequest) (*policymanager.GetPolicyDecisionsResponse, error) {
 	logging.LogStructure("GetPoliciesDecisions object received:", request, &r.Log, zerolog.DebugLevel, false, false)
 	// Add "input" hierarchy
-	inputStruct := map[string]interface{}{"input": &request}
+	inputStruct := map[string]interface{}{"input": request}
 	// Marshal request as JSON
 	requestBody, err := json.Marshal(&inputStruct)
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
 	}
 	// Send request to OPA
 	endpoint := fmt.Sprintf("%s/%s", strings.TrimRight(r.OpaServerURL, "/"), strings.TrimLeft(policyEndpoint, "/"))
-	responseFromOPA, err := r.OpaClient.Post(endpoint, "application/json", bytes.NewBuffer(requestBody))
+	opaRequest, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(requestBody))
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
+	}
+	opaRequest.Header.Set("Content-Type", "application/json")
+	responseFromOPA, err := r.OpaClient.Do(opaRequest)
+	if err != nil {
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
 	}
 
 	// Read response from OPA
 	defer responseFromOPA.Body.Close()
 	responseFromOPABody, err := io.ReadAll(responseFromOPA.Body)
 	if err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
 	}
 
 	// Handle errors from OPA
 	if responseFromOPA.StatusCode != http.StatusOK {
 		// TODO: better error handling for OPA errors
-		r.reportError(c, responseFromOPA.StatusCode, string(responseFromOPABody))
-		return
+		return nil, rpc.NewError(responseFromOPA.StatusCode, string(responseFromOPABody))
 	}
 
 	// Unmarshal as GetPolicyDecisionsResponse for the sake of validation
 	var response policymanager.GetPolicyDecisionsResponse
 	if err := json.Unmarshal(responseFromOPABody, &response); err != nil {
-		r.reportError(c, http.StatusInternalServerError, err.Error())
-		return
+		return nil, rpc.NewError(http.StatusInternalServerError, err.Error())
 	}
 	r.Log.Info().Msg(
 		"Sending response from opa connector with created asset ID: " + string(request.Resource.ID))
+	return &response, nil
+}
 
-	c.JSON(http.StatusOK, response)
+// PolicyManagerServer serves the decisions of the controller over gRPC
+type PolicyManagerServer struct {
+	*ConnectorController
+}
+
+var _ rpc.PolicyManagerServer = PolicyManagerServer{}
+
+func (s PolicyManagerServer) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	return s.getPoliciesDecisions(ctx, in)
 }
 
 func (r *ConnectorController) reportError(c *gin.Context, httpCode int, errorMessage string) {
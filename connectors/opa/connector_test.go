@@ -103,6 +103,31 @@ func TestGetPoliciesDecisions(t *testing.T) {
 	})
 }
 
+// the status code of an OPA error is returned as is by the REST API, also if it has no matching gRPC code
+func TestGetPoliciesDecisionsOPAError(t *testing.T) {
+	opaMock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte("invalid input"))
+	}))
+	defer opaMock.Close()
+	controller, err := NewConnectorController(opaMock.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	requestBytes := mustAsJSON(t, &policymanager.GetPolicyDecisionsRequest{
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow},
+		Resource: policymanager.Resource{ID: taxonomy.AssetID("assetID")},
+	})
+	c.Request = httptest.NewRequest(http.MethodPost, "http://localhost/", bytes.NewBuffer(requestBytes))
+	controller.GetPoliciesDecisions(c)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), "invalid input")
+}
+
 func createMockServer(t *testing.T, name string, expectedRequest, mockedResponse interface{}) *httptest.Server {
 	expectedRequestBytes := mustAsJSON(t, expectedRequest)
 	responseBytes := mustAsJSON(t, mockedResponse)
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,14 +15,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/environment"
 	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 const (
 	envOPAServerURL = "OPA_SERVER_URL"
 	envServicePort  = "SERVICE_PORT"
+	serviceName     = "opa-connector"
 )
 
 var (
@@ -32,6 +38,7 @@ var (
 // NewRouter returns a new router.
 func NewRouter(controller *ConnectorController) *gin.Engine {
 	router := gin.Default()
+	router.Use(otelgin.Middleware(serviceName))
 	router.POST("/getPoliciesDecisions", controller.GetPoliciesDecisions)
 	return router
 }
@@ -59,6 +66,11 @@ func RunCmd() *cobra.Command {
 		log.Err(err).Msg(fmt.Sprintf("error in converting %s = [%s] to integer", envServicePort, portStr))
 		return nil
 	}
+	grpcPort, err := environment.GetGRPCPort()
+	if err != nil {
+		log.Err(err).Msg("invalid " + environment.GRPCPortKey)
+		return nil
+	}
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "Run opa connector",
@@ -80,24 +92,48 @@ func RunCmd() *cobra.Command {
 				return errors.Wrap(err, "failed to set connection to opa server")
 			}
 			controller.Log.Info().Msg("based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
+			shutdownTracing, err := tracing.Init(context.Background(), serviceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to initialize tracing")
+			}
+			defer func() { _ = shutdownTracing(context.Background()) }()
 			router := NewRouter(controller)
 			router.Use(gin.Logger())
 
 			bindAddress := fmt.Sprintf("%s:%d", ip, port)
-			if environment.IsUsingTLS() {
-				tlsConfig, err := fybrikTLS.GetServerConfig(&controller.Log)
-				if err != nil {
-					return errors.Wrap(err, "failed to get tls config")
+
+			serveHTTP := func() error {
+				if environment.IsUsingTLS() {
+					tlsConfig, err := fybrikTLS.GetServerConfig(&controller.Log)
+					if err != nil {
+						return errors.Wrap(err, "failed to get tls config")
+					}
+					server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
+					return server.ListenAndServeTLS("", "")
 				}
-				server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
-				return server.ListenAndServeTLS("", "")
+				controller.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
+				return router.Run(bindAddress)
+			}
+			if grpcPort == 0 {
+				return serveHTTP()
+			}
+
+			// the connector serves gRPC in addition to REST if a gRPC port is set
+			var grpcServer *grpc.Server
+			if grpcServer, err = rpc.NewServer(serviceName, &controller.Log); err != nil {
+				return err
 			}
-			controller.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
-			return router.Run(bindAddress)
+			defer grpcServer.Stop()
+			rpc.RegisterPolicyManagerServer(grpcServer, PolicyManagerServer{controller})
+			errs := make(chan error, 2)
+			go func() { errs <- rpc.Serve(grpcServer, fmt.Sprintf("%s:%d", ip, grpcPort)) }()
+			go func() { errs <- serveHTTP() }()
+			return <-errs
 		},
 	}
 	cmd.Flags().StringVar(&ip, "ip", ip, "IP address")
 	cmd.Flags().IntVar(&port, "port", port, "Listening port")
+	cmd.Flags().IntVar(&grpcPort, "grpc-port", grpcPort, "Listening port for gRPC (disabled if 0)")
 	return cmd
 }
 
@@ -0,0 +1,107 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/monitor"
+)
+
+// ConnectorController evaluates the rules in the rules directory.
+// The rules are reloaded when the rules files change.
+type ConnectorController struct {
+	RulesDir string
+	Log      zerolog.Logger
+
+	mux   sync.RWMutex
+	rules []Rule
+}
+
+var _ monitor.Subscriber = (*ConnectorController)(nil)
+
+func NewConnectorController(rulesDir string) (*ConnectorController, error) {
+	controller := &ConnectorController{
+		RulesDir: rulesDir,
+		Log:      logging.LogInit(logging.CONNECTOR, "rules-connector"),
+	}
+	rules, err := ReadRules(rulesDir)
+	if err != nil {
+		return nil, err
+	}
+	controller.rules = rules
+	controller.Log.Info().Msgf("Loaded %d rules from %s", len(rules), rulesDir)
+	return controller, nil
+}
+
+// GetOptions returns the directory and the extension of the rules files for the file monitor
+func (r *ConnectorController) GetOptions() monitor.FileMonitorOptions {
+	return monitor.FileMonitorOptions{Path: r.RulesDir, Extension: RulesFileExtension}
+}
+
+// OnNotify reloads the rules when the rules files change. The current rules are kept if the files are invalid.
+func (r *ConnectorController) OnNotify() {
+	rules, err := ReadRules(r.RulesDir)
+	if err != nil {
+		r.OnError(err)
+		return
+	}
+	r.mux.Lock()
+	r.rules = rules
+	r.mux.Unlock()
+	r.Log.Info().Msgf("Reloaded %d rules from %s", len(rules), r.RulesDir)
+}
+
+func (r *ConnectorController) OnError(err error) {
+	r.Log.Error().Err(err).Msg("Error loading the rules, the previous rules are used")
+}
+
+func (r *ConnectorController) GetPoliciesDecisions(c *gin.Context) {
+	var request policymanager.GetPolicyDecisionsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		r.reportError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	logging.LogStructure("GetPoliciesDecisions object received:", request, &r.Log, zerolog.DebugLevel, false, false)
+	response := r.evaluate(&request)
+	r.Log.Info().Str("decision_id", response.DecisionID).Str(logging.DATASETID, string(request.Resource.ID)).
+		Msgf("Decided on %d actions for %s flow", len(response.Result), request.Action.ActionType)
+	c.JSON(http.StatusOK, response)
+}
+
+// evaluate returns the actions of all the rules for the request
+func (r *ConnectorController) evaluate(request *policymanager.GetPolicyDecisionsRequest) *policymanager.GetPolicyDecisionsResponse {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	response := &policymanager.GetPolicyDecisionsResponse{
+		DecisionID: uuid.New().String(),
+		Result:     []policymanager.ResultItem{},
+	}
+	var messages []string
+	for i := range r.rules {
+		item := r.rules[i].Evaluate(request)
+		if item == nil {
+			continue
+		}
+		response.Result = append(response.Result, *item)
+		if r.rules[i].Description != "" {
+			messages = append(messages, r.rules[i].Description)
+		}
+	}
+	response.Message = strings.Join(messages, "; ")
+	return response
+}
+
+func (r *ConnectorController) reportError(c *gin.Context, httpCode int, errorMessage string) {
+	r.Log.Warn().CallerSkipFrame(1).Msg(errorMessage)
+	c.JSON(httpCode, gin.H{"error": errorMessage})
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/monitor"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+const financeRules = `
+rules:
+  - name: redact-finance-pii
+    description: PII columns of finance data are redacted outside theshire
+    when:
+      flows: [read]
+      assetTags: [finance]
+      columnTags: [PII]
+      outsideLocations: [theshire]
+    then:
+      name: RedactAction
+    otherwise:
+      name: Deny
+`
+
+const writeRules = `
+rules:
+  - name: deny-write
+    description: writing is not allowed
+    when:
+      flows: [write]
+    then:
+      name: Deny
+`
+
+func tags(names ...string) *taxonomy.Tags {
+	items := map[string]interface{}{}
+	for _, name := range names {
+		items[name] = true
+	}
+	return &taxonomy.Tags{Properties: serde.Properties{Items: items}}
+}
+
+func getPoliciesDecisions(t *testing.T, controller *ConnectorController, flow taxonomy.DataFlow,
+	location taxonomy.ProcessingLocation) *policymanager.GetPolicyDecisionsResponse {
+	request := policymanager.GetPolicyDecisionsRequest{
+		Action: policymanager.RequestAction{ActionType: flow, ProcessingLocation: location},
+		Resource: policymanager.Resource{ID: "finance/transactions", Metadata: &datacatalog.ResourceMetadata{
+			Tags: tags("finance"),
+			Columns: []datacatalog.ResourceColumn{
+				{Name: "account", Tags: tags("PII")},
+				{Name: "amount"},
+				{Name: "owner", Tags: tags("PII", "SPI")},
+			},
+		}},
+	}
+	body, err := json.Marshal(&request)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/getPoliciesDecisions", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	controller.GetPoliciesDecisions(c)
+	assert.Equal(t, http.StatusOK, w.Code)
+	response := &policymanager.GetPolicyDecisionsResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), response))
+	assert.NotEmpty(t, response.DecisionID)
+	return response
+}
+
+func TestGetPoliciesDecisionsFromRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rulesDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(rulesDir, "finance"+RulesFileExtension), []byte(financeRules), 0600))
+	controller, err := NewConnectorController(rulesDir)
+	assert.NoError(t, err)
+
+	// the tagged columns are redacted outside theshire
+	response := getPoliciesDecisions(t, controller, taxonomy.ReadFlow, "neverland")
+	assert.Len(t, response.Result, 1)
+	assert.Equal(t, "redact-finance-pii", response.Result[0].Policy)
+	assert.Equal(t, taxonomy.ActionName("RedactAction"), response.Result[0].Action.Name)
+	assert.Equal(t, []interface{}{"account", "owner"}, response.Result[0].Action.AdditionalProperties.Items[columnsProperty])
+	assert.Equal(t, "PII columns of finance data are redacted outside theshire", response.Message)
+
+	// the access is denied otherwise
+	response = getPoliciesDecisions(t, controller, taxonomy.ReadFlow, "theshire")
+	assert.Len(t, response.Result, 1)
+	assert.Equal(t, taxonomy.ActionName("Deny"), response.Result[0].Action.Name)
+
+	// every decision has its own identifier
+	assert.NotEqual(t, response.DecisionID, getPoliciesDecisions(t, controller, taxonomy.ReadFlow, "theshire").DecisionID)
+}
+
+func TestReloadRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rulesDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(rulesDir, "finance"+RulesFileExtension), []byte(financeRules), 0600))
+	controller, err := NewConnectorController(rulesDir)
+	assert.NoError(t, err)
+	fileMonitor := &monitor.FileMonitor{Subsciptions: []monitor.Subscription{}, Log: controller.Log}
+	assert.NoError(t, fileMonitor.Subscribe(controller))
+	response := getPoliciesDecisions(t, controller, taxonomy.WriteFlow, "theshire")
+	assert.Equal(t, taxonomy.ActionName("Deny"), response.Result[0].Action.Name)
+	assert.Equal(t, "redact-finance-pii", response.Result[0].Policy)
+
+	// the new rules are used when a rules file is added
+	assert.NoError(t, os.WriteFile(filepath.Join(rulesDir, "write"+RulesFileExtension), []byte(writeRules), 0600))
+	fileMonitor.Monitor()
+	response = getPoliciesDecisions(t, controller, taxonomy.WriteFlow, "theshire")
+	assert.Len(t, response.Result, 2)
+	assert.Equal(t, "deny-write", response.Result[1].Policy)
+	assert.Equal(t, "PII columns of finance data are redacted outside theshire; writing is not allowed", response.Message)
+
+	// the previous rules are kept when a rules file is invalid
+	invalidFile := filepath.Join(rulesDir, "invalid"+RulesFileExtension)
+	assert.NoError(t, os.WriteFile(invalidFile, []byte("rules:\n  - name: missing-action\n"), 0600))
+	now := time.Now().Add(time.Second)
+	assert.NoError(t, os.Chtimes(invalidFile, now, now))
+	fileMonitor.Monitor()
+	response = getPoliciesDecisions(t, controller, taxonomy.WriteFlow, "theshire")
+	assert.Len(t, response.Result, 2)
+}
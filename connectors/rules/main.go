@@ -0,0 +1,131 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/monitor"
+	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
+)
+
+const (
+	envRulesDir    = "RULES_DIR"
+	envServicePort = "SERVICE_PORT"
+	serviceName    = "rules-connector"
+)
+
+var (
+	gitCommit string
+	gitTag    string
+)
+
+// NewRouter returns a new router.
+func NewRouter(controller *ConnectorController) *gin.Engine {
+	router := gin.Default()
+	router.Use(otelgin.Middleware(serviceName))
+	router.POST("/getPoliciesDecisions", controller.GetPoliciesDecisions)
+	return router
+}
+
+// RootCmd defines the root cli command
+func RootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules-connector",
+		Short: "Policy manager connector for Fybrik that evaluates rules defined in yaml files",
+	}
+	cmd.AddCommand(RunCmd())
+	return cmd
+}
+
+// RunCmd defines the command for running the connector
+func RunCmd() *cobra.Command {
+	ip := ""
+	portStr, err := environment.MustGetEnv(envServicePort)
+	if err != nil {
+		log.Err(err).Msg(envServicePort + " env var is not defined")
+		return nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		log.Err(err).Msg(fmt.Sprintf("error in converting %s = [%s] to integer", envServicePort, portStr))
+		return nil
+	}
+	rulesDir := os.Getenv(envRulesDir)
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run rules connector",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			gin.SetMode(gin.ReleaseMode)
+			if rulesDir == "" {
+				return errors.New("the rules directory must be set with --rules-dir or " + envRulesDir)
+			}
+
+			// Create the connector and reload the rules when they change
+			controller, err := NewConnectorController(rulesDir)
+			if err != nil {
+				return errors.Wrap(err, "failed to load the rules")
+			}
+			controller.Log.Info().Msg("based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
+			fileMonitor := &monitor.FileMonitor{Subsciptions: []monitor.Subscription{}, Log: controller.Log}
+			if err = fileMonitor.Subscribe(controller); err != nil {
+				return errors.Wrap(err, "failed to monitor the rules")
+			}
+			watcher, err := fsnotify.NewWatcher()
+			if err != nil {
+				return errors.Wrap(err, "failed to create a file system watcher")
+			}
+			defer watcher.Close()
+			if err = watcher.Add(rulesDir); err != nil {
+				return errors.Wrap(err, "failed to watch the rules directory")
+			}
+			fileMonitor.Run(watcher)
+
+			shutdownTracing, err := tracing.Init(context.Background(), serviceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to initialize tracing")
+			}
+			defer func() { _ = shutdownTracing(context.Background()) }()
+			router := NewRouter(controller)
+			router.Use(gin.Logger())
+
+			bindAddress := fmt.Sprintf("%s:%d", ip, port)
+			if environment.IsUsingTLS() {
+				tlsConfig, err := fybrikTLS.GetServerConfig(&controller.Log)
+				if err != nil {
+					return errors.Wrap(err, "failed to get tls config")
+				}
+				server := http.Server{Addr: bindAddress, Handler: router, TLSConfig: tlsConfig}
+				return server.ListenAndServeTLS("", "")
+			}
+			controller.Log.Info().Msg(fybrikTLS.TLSDisabledMsg)
+			return router.Run(bindAddress)
+		},
+	}
+	cmd.Flags().StringVar(&ip, "ip", ip, "IP address")
+	cmd.Flags().IntVar(&port, "port", port, "Listening port")
+	cmd.Flags().StringVar(&rulesDir, "rules-dir", rulesDir, "Directory of the rules files")
+	return cmd
+}
+
+func main() {
+	// Run the cli
+	if err := RootCmd().Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
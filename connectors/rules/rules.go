@@ -0,0 +1,171 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"emperror.dev/errors"
+	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+const (
+	// RulesFileExtension is the extension of the files in the rules directory that define rules
+	RulesFileExtension = ".rules.yaml"
+	// columnsProperty is the action property that holds the columns the action applies to
+	columnsProperty = "columns"
+)
+
+// RulesFile is a file that defines policy rules
+type RulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule returns an action for the requests that match its conditions, and optionally another action for the requests
+// that do not match them
+type Rule struct {
+	// Name of the rule, returned as the policy on which the decision was based
+	Name string `json:"name"`
+	// Description of the rule, returned in the message of the decision
+	Description string `json:"description,omitempty"`
+	// When defines the conditions of the rule. All conditions must hold for the request to match the rule.
+	When Conditions `json:"when,omitempty"`
+	// Then is the action that is returned if the request matches the rule
+	Then taxonomy.Action `json:"then"`
+	// Otherwise is the action that is returned if the request does not match the rule
+	Otherwise *taxonomy.Action `json:"otherwise,omitempty"`
+}
+
+// Conditions of a rule on the request. Conditions that are not set always hold.
+type Conditions struct {
+	// Flows holds if the data flow of the request is one of the given flows
+	Flows []taxonomy.DataFlow `json:"flows,omitempty"`
+	// AssetTags holds if the asset has all the given tags
+	AssetTags []string `json:"assetTags,omitempty"`
+	// ColumnTags holds if at least one column of the asset has one of the given tags.
+	// The action of the rule applies to these columns.
+	ColumnTags []string `json:"columnTags,omitempty"`
+	// Locations holds if the data is processed in one of the given locations
+	Locations []taxonomy.ProcessingLocation `json:"locations,omitempty"`
+	// OutsideLocations holds if the data is not processed in any of the given locations
+	OutsideLocations []taxonomy.ProcessingLocation `json:"outsideLocations,omitempty"`
+}
+
+// ReadRules reads the rules from the rules files in the given directory, ordered by the file names
+func ReadRules(dir string) ([]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the rules directory")
+	}
+	rules := []Rule{}
+	names := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), RulesFileExtension) {
+			continue
+		}
+		var content []byte
+		if content, err = os.ReadFile(filepath.Clean(filepath.Join(dir, entry.Name()))); err != nil {
+			return nil, errors.Wrap(err, "failed to read rules file "+entry.Name())
+		}
+		file := RulesFile{}
+		if err = yaml.UnmarshalStrict(content, &file); err != nil {
+			return nil, errors.Wrap(err, "failed to parse rules file "+entry.Name())
+		}
+		for i := range file.Rules {
+			rule := &file.Rules[i]
+			if rule.Name == "" || rule.Then.Name == "" {
+				return nil, errors.Errorf("rule %d in %s must have a name and an action", i, entry.Name())
+			}
+			if other, found := names[rule.Name]; found {
+				return nil, errors.Errorf("rule %s is defined in both %s and %s", rule.Name, other, entry.Name())
+			}
+			names[rule.Name] = entry.Name()
+			rules = append(rules, *rule)
+		}
+	}
+	return rules, nil
+}
+
+// Evaluate returns the action of the rule for the request, or nil if the rule has no action for the request
+func (r *Rule) Evaluate(request *policymanager.GetPolicyDecisionsRequest) *policymanager.ResultItem {
+	columns, matched := r.When.match(request)
+	var action taxonomy.Action
+	switch {
+	case matched:
+		action = *r.Then.DeepCopy()
+		if len(r.When.ColumnTags) > 0 {
+			if action.AdditionalProperties.Items == nil {
+				action.AdditionalProperties.Items = map[string]interface{}{}
+			}
+			if _, found := action.AdditionalProperties.Items[columnsProperty]; !found {
+				action.AdditionalProperties.Items[columnsProperty] = columns
+			}
+		}
+	case r.Otherwise != nil:
+		action = *r.Otherwise.DeepCopy()
+	default:
+		return nil
+	}
+	return &policymanager.ResultItem{Policy: r.Name, Action: action}
+}
+
+// match returns whether the request matches the conditions, and the columns that have the column tags
+func (c *Conditions) match(request *policymanager.GetPolicyDecisionsRequest) ([]interface{}, bool) {
+	if len(c.Flows) > 0 && !hasItem(c.Flows, request.Action.ActionType) {
+		return nil, false
+	}
+	location := request.Action.ProcessingLocation
+	if len(c.Locations) > 0 && !hasItem(c.Locations, location) {
+		return nil, false
+	}
+	if hasItem(c.OutsideLocations, location) {
+		return nil, false
+	}
+	metadata := request.Resource.Metadata
+	if metadata == nil {
+		metadata = &datacatalog.ResourceMetadata{}
+	}
+	for _, tag := range c.AssetTags {
+		if !hasTag(metadata.Tags, tag) {
+			return nil, false
+		}
+	}
+	if len(c.ColumnTags) == 0 {
+		return nil, true
+	}
+	columns := []interface{}{}
+	for _, column := range metadata.Columns {
+		for _, tag := range c.ColumnTags {
+			if hasTag(column.Tags, tag) {
+				columns = append(columns, column.Name)
+				break
+			}
+		}
+	}
+	return columns, len(columns) > 0
+}
+
+// hasTag returns true if the tag is set and not false
+func hasTag(tags *taxonomy.Tags, tag string) bool {
+	if tags == nil {
+		return false
+	}
+	value, found := tags.Items[tag]
+	return found && value != nil && value != false
+}
+
+func hasItem[T ~string](items []T, item T) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
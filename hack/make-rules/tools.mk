@@ -94,6 +94,22 @@ $(TOOLBIN)/oapi-codegen:
 	GOBIN=$(ABSTOOLBIN) go install github.com/deepmap/oapi-codegen/cmd/oapi-codegen@v$(OAPI_CODEGEN_VERSION)
 	$(call post-install-check)
 
+INSTALL_TOOLS += $(TOOLBIN)/protoc
+.PHONY: $(TOOLBIN)/protoc
+$(TOOLBIN)/protoc:
+	cd $(TOOLS_DIR); ./install_protoc.sh
+	$(call post-install-check)
+
+INSTALL_TOOLS += $(TOOLBIN)/protoc-gen-go
+$(TOOLBIN)/protoc-gen-go:
+	GOBIN=$(ABSTOOLBIN) go install google.golang.org/protobuf/cmd/protoc-gen-go@v$(PROTOC_GEN_GO_VERSION)
+	$(call post-install-check)
+
+INSTALL_TOOLS += $(TOOLBIN)/protoc-gen-go-grpc
+$(TOOLBIN)/protoc-gen-go-grpc:
+	GOBIN=$(ABSTOOLBIN) go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v$(PROTOC_GEN_GO_GRPC_VERSION)
+	$(call post-install-check)
+
 INSTALL_TOOLS += $(TOOLBIN)/crdoc
 $(TOOLBIN)/crdoc:
 	GOBIN=$(ABSTOOLBIN) go install fybrik.io/crdoc@v$(CRDOC_VERSION)
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+# Copyright 2023 IBM Corp.
+# SPDX-License-Identifier: Apache-2.0
+
+
+cd "${0%/*}"
+source ./common.sh
+
+
+header_text "Checking for bin/protoc ${PROTOC_VERSION}"
+[[ -f bin/protoc && `bin/protoc --version | cut -f2 -d" "` == ${PROTOC_VERSION} ]] && exit 0
+
+protoc_os=linux
+if [[ "$os" == "darwin" ]]; then
+  protoc_os=osx
+fi
+
+header_text "Installing bin/protoc ${PROTOC_VERSION}"
+mkdir -p ./bin
+tmp=$(mktemp -d)
+curl -L -o ${tmp}/protoc.zip https://github.com/protocolbuffers/protobuf/releases/download/v${PROTOC_VERSION}/protoc-${PROTOC_VERSION}-${protoc_os}-x86_64.zip
+unzip -o -q ${tmp}/protoc.zip -d ${tmp}
+mv ${tmp}/bin/protoc ./bin/protoc
+# the well-known types, e.g., google/protobuf/struct.proto
+rm -rf ./bin/include
+mv ${tmp}/include ./bin/include
+rm -rf ${tmp}
+chmod +x ./bin/protoc
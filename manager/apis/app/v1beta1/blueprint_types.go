@@ -48,6 +48,11 @@ type ApplicationDetails struct {
 	// +optional
 	IPBlocks []*netv1.IPBlock `json:"ipBlocks,omitempty"`
 
+	// ServiceAccounts of the user application, in the form namespace/name.
+	// It is obtained from FybrikApplication spec.
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
+
 	// Application context such as intent, role, etc.
 	// +optional
 	Context taxonomy.AppInfo `json:"context,omitempty"`
@@ -68,6 +73,10 @@ type ModuleDeployment struct {
 	Release string `json:"release"`
 	// Service URLs, usually represented by hostname + port
 	URLs []string `json:"urls"`
+	// IPBlocks of the gateways of the cluster the module runs in, set for modules in other clusters.
+	// For ingress these are the egress gateways of the module cluster, for egress these are its ingress gateways
+	// +optional
+	IPBlocks []string `json:"ipBlocks,omitempty"`
 }
 
 // ModuleNetwork specifies the module communication with a workload or other modules
@@ -110,6 +119,15 @@ type BlueprintModule struct {
 	// Network specifies the module communication with a workload or other modules
 	// +optional
 	Network ModuleNetwork `json:"network,omitempty"`
+
+	// DependsOn are the instance names of the modules that must be ready before this module is deployed
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// CredentialKeys are the keys of the data credentials that are copied to the module secrets
+	// by the credential broker. All keys are copied if empty.
+	// +optional
+	CredentialKeys []string `json:"credentialKeys,omitempty"`
 }
 
 // BlueprintSpec defines the desired state of Blueprint, which defines the components of the workload's data path
@@ -154,10 +172,55 @@ type BlueprintStatus struct {
 	// +optional
 	ModulesState map[string]ObservedState `json:"modules,omitempty"`
 
+	// ModulesHistory is a map which holds the recent state transitions of each module
+	// its key is the moduleInstanceName. At most MaxStateTransitions transitions are kept per module.
+	// +optional
+	ModulesHistory map[string][]StateTransition `json:"modulesHistory,omitempty"`
+
 	// Releases map each release to the observed generation of the blueprint containing this release.
 	// At the end of reconcile, each release should be mapped to the latest blueprint version or be uninstalled.
 	// +optional
 	Releases map[string]int64 `json:"releases,omitempty"`
+
+	// Revision is the generation of the blueprint whose module releases serve the workload, if these releases have been
+	// rolled out next to the releases of a previous generation. The release names of a revision are suffixed with the revision.
+	// Zero stands for the releases named after the module instances.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+
+	// Rollout describes the ongoing or the last failed rollout of a new blueprint generation
+	// +optional
+	Rollout *BlueprintRollout `json:"rollout,omitempty"`
+}
+
+// RolloutPhase is the phase of a blueprint rollout
+// +kubebuilder:validation:Enum=Progressing;RolledBack
+type RolloutPhase string
+
+const (
+	// RolloutProgressing means that the releases of the new generation are deployed next to the serving releases
+	RolloutProgressing RolloutPhase = "Progressing"
+	// RolloutRolledBack means that the releases of the new generation have failed and have been removed
+	RolloutRolledBack RolloutPhase = "RolledBack"
+)
+
+// BlueprintRollout describes the rollout of a blueprint generation next to the releases that serve the workload
+type BlueprintRollout struct {
+	// Generation of the blueprint that is rolled out
+	// +required
+	Generation int64 `json:"generation"`
+
+	// Phase of the rollout
+	// +required
+	Phase RolloutPhase `json:"phase"`
+
+	// StartTime is the time the rollout has started
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+
+	// Message explains why the rollout has been rolled back
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -212,13 +275,16 @@ func CreateMetaBlueprint(blueprint *Blueprint) MetaBlueprint {
 	return metaBlueprint
 }
 
-// CreateMetaBlueprintWithoutState creates the MetaBlueprint structure with an empty state
+// CreateMetaBlueprintWithoutState creates the MetaBlueprint structure with an empty state.
+// The history of module state transitions is preserved.
 func CreateMetaBlueprintWithoutState(blueprint *Blueprint) MetaBlueprint {
 	metaBlueprint := MetaBlueprint{
 		Name:      blueprint.GetName(),
 		Namespace: blueprint.GetNamespace(),
 		Status: BlueprintStatus{
-			ModulesState: map[string]ObservedState{},
+			ModulesState:   map[string]ObservedState{},
+			ModulesHistory: blueprint.Status.ModulesHistory,
+			Revision:       blueprint.Status.Revision,
 		},
 	}
 	return metaBlueprint
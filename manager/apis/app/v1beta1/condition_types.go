@@ -16,6 +16,10 @@ const (
 	DenyCondition  ConditionType = "Deny"
 	ReadyCondition ConditionType = "Ready"
 	ValidCondition ConditionType = "Valid"
+	// DependenciesResolvedCondition indicates whether the dependencies of a module are available and acyclic
+	DependenciesResolvedCondition ConditionType = "DependenciesResolved"
+	// DeprecatedInUseCondition indicates whether a deprecated module version is still used by applications
+	DeprecatedInUseCondition ConditionType = "DeprecatedInUse"
 )
 
 // Condition describes the state of a FybrikApplication at a certain point.
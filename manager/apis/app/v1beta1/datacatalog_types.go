@@ -18,4 +18,8 @@ type DataStore struct {
 	// Format represents data format (e.g. parquet) as received from catalog connectors
 	// +optional
 	Format taxonomy.DataFormat `json:"format,omitempty"`
+	// SecretRef is the Kubernetes secret that holds the credentials when they are delivered to the modules
+	// by the credential broker of the manager instead of Vault.
+	// +optional
+	SecretRef *taxonomy.SecretRef `json:"secretRef,omitempty"`
 }
@@ -15,7 +15,8 @@ import (
 // FlowRequirements include the requirements specific to the flow
 // Note: Implicit copies done for data plane optimization by Fybrik do not use these parameters
 type FlowRequirements struct {
-	// Catalog indicates that the data asset must be cataloged, and in which catalog to register it
+	// Catalog indicates that the data asset must be cataloged, and in which catalog to register it.
+	// The catalog also selects the data catalog connector that registers the asset if a catalog registry is configured.
 	// +optional
 	Catalog string `json:"catalog,omitempty"`
 
@@ -90,6 +91,13 @@ type FybrikApplicationSpec struct {
 	// and the protocol used to access it and the format expected.
 	// +required
 	Data []DataContext `json:"data"`
+
+	// ModuleVersions constrains the versions of the modules that may be deployed for the application.
+	// The key is a module name and the value is a semantic version constraint, e.g. "1.2.3", "~1.2" or ">= 1.0, < 2.0".
+	// Module versions used by the application are kept when it is planned again, as long as they satisfy the constraints.
+	// Changing a constraint is the way to roll the application forward to newer versions.
+	// +optional
+	ModuleVersions map[string]string `json:"moduleVersions,omitempty"`
 }
 
 // ResourceReference contains resource identifier(name, namespace, kind)
@@ -127,6 +135,29 @@ type DatasetDetails struct {
 	Persistent bool `json:"persistent,omitempty"`
 }
 
+// CredentialLease is a lease on the dynamic credentials of an asset that the manager obtains from Vault for the modules
+type CredentialLease struct {
+	// LeaseID is the identifier of the lease in Vault
+	// +required
+	LeaseID string `json:"leaseID"`
+
+	// SourcePath is the path of the dynamic secret that the credentials have been issued for, e.g. /v1/database/creds/readonly
+	// +required
+	SourcePath string `json:"sourcePath"`
+
+	// SecretPath is the path of the secret in Vault holding the leased credentials that is passed to the modules
+	// +required
+	SecretPath string `json:"secretPath"`
+
+	// Renewable is true if the lease can be renewed
+	// +optional
+	Renewable bool `json:"renewable,omitempty"`
+
+	// RenewTime is the time at which the lease is renewed, or replaced by a new lease if it can not be renewed
+	// +required
+	RenewTime metav1.Time `json:"renewTime"`
+}
+
 // AssetState defines the observed state of an asset
 type AssetState struct {
 	// Conditions indicate the asset state (Ready, Deny, Error)
@@ -137,6 +168,15 @@ type AssetState struct {
 	// +optional
 	CatalogedAsset string `json:"catalogedAsset,omitempty"`
 
+	// Revision is the revision of the asset metadata that has been returned by the data catalog and used to
+	// construct the data path of the asset. Empty if the catalog does not keep the history of the asset metadata
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Deleted is true if the asset has been deleted by a delete flow and removed from the catalog
+	// +optional
+	Deleted bool `json:"deleted,omitempty"`
+
 	// Endpoint provides the endpoint spec from which the asset will be served to the application
 	// +optional
 	Endpoint taxonomy.Connection `json:"endpoint,omitempty"`
@@ -179,6 +219,15 @@ type FybrikApplicationStatus struct {
 	// ProvisionedStorage has the information required to register the dataset once the owned plotter resource is ready
 	// +optional
 	ProvisionedStorage map[string]DatasetDetails `json:"provisionedStorage,omitempty"`
+
+	// Modules maps the FybrikModules used by the generated resource to their versions
+	// +optional
+	Modules map[string]string `json:"modules,omitempty"`
+
+	// CredentialLeases maps an asset (identified by AssetID) to the lease on its dynamic credentials.
+	// The leases are revoked when the application is deleted or when its data paths are computed again.
+	// +optional
+	CredentialLeases map[string]CredentialLease `json:"credentialLeases,omitempty"`
 }
 
 // FybrikApplication provides information about the application whose data is being operated on,
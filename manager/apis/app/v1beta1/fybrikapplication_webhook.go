@@ -6,6 +6,8 @@ package v1beta1
 import (
 	"encoding/json"
 
+	"github.com/Masterminds/semver/v3"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -59,6 +61,11 @@ func (r *FybrikApplication) ValidateFybrikApplication(taxonomyFile string) error
 	if err != nil {
 		return err
 	}
+	for moduleName, constraint := range r.Spec.ModuleVersions {
+		if _, err = semver.NewConstraint(constraint); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "moduleVersions").Key(moduleName), constraint, err.Error()))
+		}
+	}
 
 	// Return any error
 	if len(allErrs) == 0 {
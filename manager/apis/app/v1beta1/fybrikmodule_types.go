@@ -134,6 +134,47 @@ type ResourceStatusIndicator struct {
 	ErrorMessage string `json:"errorMessage,omitempty"`
 }
 
+// ModuleHealthCheck specifies how the health of a module is checked.
+// The module is ready if its health endpoint responds successfully and all of its workloads are ready.
+type ModuleHealthCheck struct {
+	// URL of an HTTP endpoint that responds with a 2xx status code while the module is healthy
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Workloads that run the module, e.g. the deployment of a shared service
+	// +optional
+	Workloads []WorkloadReference `json:"workloads,omitempty"`
+
+	// PeriodSeconds is the interval between two health checks. Defaults to 30 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// TimeoutSeconds is the timeout of a request to the health endpoint. Defaults to 5 seconds.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+}
+
+// WorkloadReference refers to a Kubernetes resource whose readiness is computed with kstatus
+type WorkloadReference struct {
+	// APIVersion of the resource, e.g. apps/v1
+	// +required
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the resource, e.g. Deployment
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the resource
+	// +required
+	Name string `json:"name"`
+
+	// Namespace of the resource. Defaults to the modules namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // FybrikModuleSpec contains the info common to all modules,
 // which are one of the components that process, load, write, audit, monitor the data used by
 // the data scientist's application.
@@ -143,6 +184,22 @@ type FybrikModuleSpec struct {
 	// +optional
 	Description string `json:"description,omitempty"`
 
+	// Name of the module that this FybrikModule is a version of.
+	// FybrikModules with the same module name and different versions are installed side by side.
+	// Defaults to the name of the FybrikModule.
+	// +optional
+	ModuleName string `json:"moduleName,omitempty"`
+
+	// Semantic version of the module, e.g. 1.2.0
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Deprecated marks a module version that should no longer be used.
+	// Deprecated versions are not selected for new data paths, unless an application pins them,
+	// while applications that already use them keep them until they are rolled forward.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+
 	// May be one of service, config or plugin
 	// Service: Means that the control plane deploys the component that performs the capability
 	// Config: Another pre-installed service performs the capability and the module deployed configures
@@ -174,19 +231,49 @@ type FybrikModuleSpec struct {
 	// +optional
 	StatusIndicators []ResourceStatusIndicator `json:"statusIndicators,omitempty"`
 
+	// HealthCheck allows checking the health of a module that runs independently of the workloads,
+	// e.g. a module with a cluster scope or a plugin of a shared service. Unhealthy modules are not used in data paths.
+	// +optional
+	HealthCheck *ModuleHealthCheck `json:"healthCheck,omitempty"`
+
 	// External services that are required for functionality of the module, format of the strings might be: be a URL
 	// (with or without schema) or a host name with or without port, or a CIDR (Classless Inter-Domain Routing) with optional port number
 	// separated by a colon
 	// +optional
 	ExternalServices []string `json:"externalServices,omitempty"`
+
+	// CredentialKeys are the keys of the data credentials that the module uses.
+	// When the credentials are delivered by the credential broker of the manager instead of Vault,
+	// only these keys are copied to the secrets of the module. All keys are copied if empty.
+	// +optional
+	CredentialKeys []string `json:"credentialKeys,omitempty"`
 }
 
+// DeploymentType is the kind of package that deploys a module
+// +kubebuilder:validation:Enum=helm;manifests;kustomize
+type DeploymentType string
+
+const (
+	// HelmDeployment is a helm chart
+	HelmDeployment DeploymentType = "helm"
+	// ManifestsDeployment is a set of Kubernetes manifests templated with the module values
+	ManifestsDeployment DeploymentType = "manifests"
+	// KustomizeDeployment is a kustomization
+	KustomizeDeployment DeploymentType = "kustomize"
+)
+
 // ChartSpec specifies chart name and values
 type ChartSpec struct {
-	// Name of helm chart
+	// Name of helm chart.
+	// For manifests and kustomize deployments the name is a reference to an OCI artifact holding the files,
+	// or to a ConfigMap holding them in the form configmap://<namespace>/<name>
 	// +required
 	Name string `json:"name"`
 
+	// Type of the deployment package: helm (default), manifests or kustomize
+	// +optional
+	Type DeploymentType `json:"type,omitempty"`
+
 	// Name of secret containing helm registry credentials
 	// +optional
 	ChartPullSecret string `json:"chartPullSecret,omitempty"`
@@ -198,7 +285,8 @@ type ChartSpec struct {
 
 // FybrikModuleStatus defines the observed state of FybrikModule.
 type FybrikModuleStatus struct {
-	// Conditions indicate the module states with respect to validation
+	// Conditions indicate the module states with respect to validation, to the resolution of its dependencies,
+	// to the usage of a deprecated version and to its readiness according to its health check
 	Conditions []Condition `json:"conditions,omitempty"`
 }
 
@@ -220,6 +308,14 @@ type FybrikModule struct {
 	Status FybrikModuleStatus `json:"status,omitempty"`
 }
 
+// GetModuleName returns the name of the module that this FybrikModule is a version of
+func (m *FybrikModule) GetModuleName() string {
+	if m.Spec.ModuleName != "" {
+		return m.Spec.ModuleName
+	}
+	return m.Name
+}
+
 // +kubebuilder:object:root=true
 
 // FybrikModuleList contains a list of FybrikModule
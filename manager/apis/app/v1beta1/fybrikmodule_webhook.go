@@ -6,6 +6,7 @@ package v1beta1
 import (
 	"encoding/json"
 
+	"github.com/Masterminds/semver/v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -57,6 +58,11 @@ func (r *FybrikModule) ValidateFybrikModule(taxonomyFile string) error {
 	if err != nil {
 		return err
 	}
+	if r.Spec.Version != "" {
+		if _, err = semver.NewVersion(r.Spec.Version); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "version"), r.Spec.Version, err.Error()))
+		}
+	}
 
 	// Return any error
 	if len(allErrs) == 0 {
@@ -3,6 +3,10 @@
 
 package v1beta1
 
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // ObservedState represents a part of the generated Blueprint/Plotter resource status that allows update of FybrikApplication status
 type ObservedState struct {
 	// Ready represents that the modules have been orchestrated successfully and the data is ready for usage
@@ -10,3 +14,64 @@ type ObservedState struct {
 	// Error indicates that there has been an error to orchestrate the modules and provides the error message
 	Error string `json:"error,omitempty"`
 }
+
+// State names used in the recorded state transitions
+const (
+	PendingState string = "Pending"
+	ReadyState   string = "Ready"
+	FailedState  string = "Failed"
+)
+
+// MaxStateTransitions is the maximal number of state transitions kept in the status history
+const MaxStateTransitions = 10
+
+// StateTransition records a change of the observed state
+type StateTransition struct {
+	// Time of the transition
+	// +required
+	Time metav1.Time `json:"time"`
+	// From is the previous state
+	// +optional
+	From string `json:"from,omitempty"`
+	// To is the new state
+	// +required
+	To string `json:"to"`
+	// Message explains the transition, e.g. an error message
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// StateName returns the name of the observed state: Ready, Failed or Pending
+func (s *ObservedState) StateName() string {
+	if s.Ready {
+		return ReadyState
+	}
+	if s.Error != "" {
+		return FailedState
+	}
+	return PendingState
+}
+
+// LastState returns the state the history ends with, or an empty string if there is no history
+func LastState(history []StateTransition) string {
+	if len(history) == 0 {
+		return ""
+	}
+	return history[len(history)-1].To
+}
+
+// RecordStateTransition appends a transition to the given state unless the history already ends with it.
+// Only the last MaxStateTransitions entries are kept.
+// The second returned value indicates whether a transition has been recorded.
+func RecordStateTransition(history []StateTransition, state *ObservedState, now metav1.Time) ([]StateTransition, bool) {
+	from := LastState(history)
+	to := state.StateName()
+	if from == to {
+		return history, false
+	}
+	history = append(history, StateTransition{Time: now, From: from, To: to, Message: state.Error})
+	if len(history) > MaxStateTransitions {
+		history = history[len(history)-MaxStateTransitions:]
+	}
+	return history, true
+}
@@ -159,6 +159,15 @@ type ModuleInfo struct {
 	// External services that are required for functionality of the module.
 	// +optional
 	ExternalServices []string `json:"externalServices,omitempty"`
+
+	// Dependencies are the names of the modules in the same template that this module depends on.
+	// Dependency modules follow the primary module in the modules list, in topological order.
+	// +optional
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// CredentialKeys are the keys of the data credentials that the module uses
+	// +optional
+	CredentialKeys []string `json:"credentialKeys,omitempty"`
 }
 
 // Template contains basic information about the required modules to serve the fybrikapplication
@@ -187,6 +196,11 @@ type FlowStatus struct {
 
 	// +required
 	SubFlows map[string]ObservedState `json:"subFlows"`
+
+	// History holds the recent state transitions of the flow.
+	// At most MaxStateTransitions transitions are kept.
+	// +optional
+	History []StateTransition `json:"history,omitempty"`
 }
 
 // PlotterSpec defines the desired state of Plotter, which is applied in a multi-clustered environment.
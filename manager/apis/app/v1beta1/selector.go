@@ -28,4 +28,10 @@ type Selector struct {
 	// https://kubernetes.io/docs/reference/generated/kubernetes-api/v1.26/#ipblock-v1-networking-k8s-io
 	// +optional
 	IPBlocks []*netv1.IPBlock `json:"ipBlocks,omitempty"`
+
+	// ServiceAccounts of the user application, in the form namespace/name.
+	// A name without a namespace refers to the namespace of the FybrikApplication.
+	// Used by the service mesh isolation of the modules to identify the user workload.
+	// +optional
+	ServiceAccounts []string `json:"serviceAccounts,omitempty"`
 }
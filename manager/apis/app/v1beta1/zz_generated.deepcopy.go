@@ -34,6 +34,11 @@ func (in *ApplicationDetails) DeepCopyInto(out *ApplicationDetails) {
 			}
 		}
 	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.Context.DeepCopyInto(&out.Context)
 }
 
@@ -187,6 +192,16 @@ func (in *BlueprintModule) DeepCopyInto(out *BlueprintModule) {
 		copy(*out, *in)
 	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialKeys != nil {
+		in, out := &in.CredentialKeys, &out.CredentialKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintModule.
@@ -199,6 +214,22 @@ func (in *BlueprintModule) DeepCopy() *BlueprintModule {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlueprintRollout) DeepCopyInto(out *BlueprintRollout) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintRollout.
+func (in *BlueprintRollout) DeepCopy() *BlueprintRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(BlueprintRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlueprintSpec) DeepCopyInto(out *BlueprintSpec) {
 	*out = *in
@@ -237,6 +268,23 @@ func (in *BlueprintStatus) DeepCopyInto(out *BlueprintStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.ModulesHistory != nil {
+		in, out := &in.ModulesHistory, &out.ModulesHistory
+		*out = make(map[string][]StateTransition, len(*in))
+		for key, val := range *in {
+			var outVal []StateTransition
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]StateTransition, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.Releases != nil {
 		in, out := &in.Releases, &out.Releases
 		*out = make(map[string]int64, len(*in))
@@ -244,6 +292,11 @@ func (in *BlueprintStatus) DeepCopyInto(out *BlueprintStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(BlueprintRollout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlueprintStatus.
@@ -293,6 +346,22 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialLease) DeepCopyInto(out *CredentialLease) {
+	*out = *in
+	in.RenewTime.DeepCopyInto(&out.RenewTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialLease.
+func (in *CredentialLease) DeepCopy() *CredentialLease {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialLease)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataContext) DeepCopyInto(out *DataContext) {
 	*out = *in
@@ -361,6 +430,11 @@ func (in *DataStore) DeepCopyInto(out *DataStore) {
 		}
 	}
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(taxonomy.SecretRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataStore.
@@ -467,6 +541,13 @@ func (in *FlowStatus) DeepCopyInto(out *FlowStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]StateTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FlowStatus.
@@ -550,6 +631,13 @@ func (in *FybrikApplicationSpec) DeepCopyInto(out *FybrikApplicationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ModuleVersions != nil {
+		in, out := &in.ModuleVersions, &out.ModuleVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikApplicationSpec.
@@ -584,6 +672,20 @@ func (in *FybrikApplicationStatus) DeepCopyInto(out *FybrikApplicationStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CredentialLeases != nil {
+		in, out := &in.CredentialLeases, &out.CredentialLeases
+		*out = make(map[string]CredentialLease, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikApplicationStatus.
@@ -676,11 +778,21 @@ func (in *FybrikModuleSpec) DeepCopyInto(out *FybrikModuleSpec) {
 		*out = make([]ResourceStatusIndicator, len(*in))
 		copy(*out, *in)
 	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(ModuleHealthCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ExternalServices != nil {
 		in, out := &in.ExternalServices, &out.ExternalServices
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CredentialKeys != nil {
+		in, out := &in.CredentialKeys, &out.CredentialKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FybrikModuleSpec.
@@ -885,6 +997,11 @@ func (in *ModuleDeployment) DeepCopyInto(out *ModuleDeployment) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IPBlocks != nil {
+		in, out := &in.IPBlocks, &out.IPBlocks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleDeployment.
@@ -897,6 +1014,26 @@ func (in *ModuleDeployment) DeepCopy() *ModuleDeployment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModuleHealthCheck) DeepCopyInto(out *ModuleHealthCheck) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleHealthCheck.
+func (in *ModuleHealthCheck) DeepCopy() *ModuleHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ModuleHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModuleInOut) DeepCopyInto(out *ModuleInOut) {
 	*out = *in
@@ -931,6 +1068,16 @@ func (in *ModuleInfo) DeepCopyInto(out *ModuleInfo) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialKeys != nil {
+		in, out := &in.CredentialKeys, &out.CredentialKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModuleInfo.
@@ -1215,6 +1362,11 @@ func (in *Selector) DeepCopyInto(out *Selector) {
 			}
 		}
 	}
+	if in.ServiceAccounts != nil {
+		in, out := &in.ServiceAccounts, &out.ServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Selector.
@@ -1227,6 +1379,22 @@ func (in *Selector) DeepCopy() *Selector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StateTransition) DeepCopyInto(out *StateTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateTransition.
+func (in *StateTransition) DeepCopy() *StateTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(StateTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepArgument) DeepCopyInto(out *StepArgument) {
 	*out = *in
@@ -1354,3 +1522,18 @@ func (in *Vault) DeepCopy() *Vault {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadReference) DeepCopyInto(out *WorkloadReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadReference.
+func (in *WorkloadReference) DeepCopy() *WorkloadReference {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -6,7 +6,6 @@ package app
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 
 	"emperror.dev/errors"
@@ -16,9 +15,9 @@ import (
 	credentialprovidersecrets "github.com/vdemeester/k8s-pkg-credentialprovider/secrets"
 	"gopkg.in/yaml.v2"
 	"helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/release"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,24 +36,30 @@ import (
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/helm"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/utils"
 )
 
 const (
+	BlueprintKind          string = "Blueprint"
 	BlueprintFinalizerName string = "Blueprint.finalizer"
 )
 
 // BlueprintReconciler reconciles a Blueprint object
 type BlueprintReconciler struct {
 	client.Client
-	Name   string
-	Log    zerolog.Logger
-	Scheme *runtime.Scheme
-	Helmer helm.Interface
+	// APIReader reads objects that are not cached by the manager, such as the secrets of the data credentials
+	APIReader client.Reader
+	Name      string
+	Log       zerolog.Logger
+	Scheme    *runtime.Scheme
+	Helmer    helm.Interface
 }
 
 // Reconcile receives a Blueprint CRD
-func (r *BlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *BlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() { metrics.ObserveReconcile(BlueprintKind, result, err) }()
 	blueprint := fapp.Blueprint{}
 	if err := r.Get(ctx, req.NamespacedName, &blueprint); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -77,7 +82,7 @@ func (r *BlueprintReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	observedStatus := blueprint.Status.DeepCopy()
 	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("Installing/Updating blueprint " + blueprint.GetName())
 
-	result, err := r.reconcile(ctx, cfg, &log, &blueprint)
+	result, err = r.reconcile(ctx, cfg, &log, &blueprint)
 	if err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to reconcile blueprint")
 	}
@@ -99,7 +104,7 @@ func (r *BlueprintReconciler) removeFinalizers(ctx context.Context, cfg *action.
 	// finalizer
 	if ctrlutil.ContainsFinalizer(blueprint, BlueprintFinalizerName) {
 		original := blueprint.DeepCopy()
-		if err := r.deleteExternalResources(cfg, blueprint); err != nil {
+		if err := r.deleteExternalResources(ctx, cfg, blueprint); err != nil {
 			r.Log.Error().Err(err).Msg("Error while deleting owned resources")
 		}
 		// remove the finalizer from the list and update it, because it needs to be deleted together with the object
@@ -109,15 +114,15 @@ func (r *BlueprintReconciler) removeFinalizers(ctx context.Context, cfg *action.
 		}
 	}
 	if environment.IsNPEnabled() {
-		return r.cleanupNetworkPolicies(ctx, blueprint)
+		return r.cleanupIsolationPolicies(ctx, blueprint)
 	}
 	return nil
 }
 
-func (r *BlueprintReconciler) deleteExternalResources(cfg *action.Configuration, blueprint *fapp.Blueprint) error {
+func (r *BlueprintReconciler) deleteExternalResources(ctx context.Context, cfg *action.Configuration, blueprint *fapp.Blueprint) error {
 	errs := make([]string, 0)
 	for release := range blueprint.Status.Releases {
-		if _, err := r.Helmer.Uninstall(cfg, release); err != nil {
+		if err := r.uninstallRelease(ctx, cfg, blueprint.Spec.ModulesNamespace, release); err != nil {
 			errs = append(errs, err.Error())
 		}
 	}
@@ -181,9 +186,9 @@ func (r *BlueprintReconciler) obtainSecrets(ctx context.Context, log *zerolog.Lo
 	return registrySuccessfulLogin, nil
 }
 
-func (r *BlueprintReconciler) applyChartResource(ctx context.Context, cfg *action.Configuration, chartSpec fapp.ChartSpec,
+func (r *BlueprintReconciler) applyChartResource(ctx context.Context, deployer ModuleDeployer, chartSpec *fapp.ChartSpec,
 	network *fapp.ModuleNetwork, args map[string]interface{}, blueprint *fapp.Blueprint, releaseName string,
-	log *zerolog.Logger) (*release.Release, error) {
+	log *zerolog.Logger) error {
 	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Chart Ref ---\n\n" + chartSpec.Name + "\n\n")
 
 	args = CopyMap(args)
@@ -195,59 +200,27 @@ func (r *BlueprintReconciler) applyChartResource(ctx context.Context, cfg *actio
 
 	var registrySuccessfulLogin string
 	var err error
-	registrySuccessfulLogin, err = r.obtainSecrets(ctx, log, chartSpec)
+	registrySuccessfulLogin, err = r.obtainSecrets(ctx, log, *chartSpec)
 	if err != nil {
-		return nil, err
-	}
-
-	tmpDir, err := os.MkdirTemp(environment.GetDataDir(), "fybrik-helm-")
-	if err != nil {
-		return nil, errors.WithMessage(err, chartSpec.Name+": failed to create temporary directory for chart pull")
-	}
-	defer func(log *zerolog.Logger) {
-		if err = os.RemoveAll(tmpDir); err != nil {
-			log.Error().Msgf("Error while calling RemoveAll on directory %s created for pulling helm chart", tmpDir)
-		}
-	}(log)
-
-	err = r.Helmer.Pull(cfg, chartSpec.Name, tmpDir)
-	if err != nil {
-		return nil, errors.WithMessage(err, chartSpec.Name+": failed chart pull")
+		return err
 	}
 	// if we logged into a registry, let us try to log out
-	if registrySuccessfulLogin != "" {
-		logoutErr := r.Helmer.RegistryLogout(registrySuccessfulLogin)
-		if logoutErr != nil {
-			return nil, errors.WithMessage(err, "failed to logout from helm registry: "+registrySuccessfulLogin)
+	defer func() {
+		if registrySuccessfulLogin == "" {
+			return
 		}
-	}
-	chart, err := r.Helmer.Load(chartSpec.Name, tmpDir)
-	if err != nil {
-		return nil, errors.WithMessage(err, chartSpec.Name+": failed chart load")
-	}
-	if environment.IsNPEnabled() {
-		err = r.createNetworkPolicies(ctx, releaseName, network, blueprint, log)
-		if err != nil {
-			return nil, err
+		if logoutErr := r.Helmer.RegistryLogout(registrySuccessfulLogin); logoutErr != nil {
+			log.Error().Err(logoutErr).Msg("failed to logout from helm registry: " + registrySuccessfulLogin)
 		}
-	}
-	releaseNamespace := blueprint.Spec.ModulesNamespace
-	inst, err := r.Helmer.IsInstalled(cfg, releaseName)
-	// TODO should we return err if it is not nil?
-	var rel *release.Release
-	if inst && err == nil {
-		rel, err = r.Helmer.Upgrade(ctx, cfg, chart, releaseNamespace, releaseName, args)
-		if err != nil {
-			return nil, errors.WithMessage(err, chartSpec.Name+": failed upgrade")
-		}
-	} else {
-		rel, err = r.Helmer.Install(ctx, cfg, chart, releaseNamespace, releaseName, args)
+	}()
+
+	if environment.IsNPEnabled() {
+		err = r.createIsolationPolicies(ctx, releaseName, network, blueprint, log)
 		if err != nil {
-			return nil, errors.WithMessage(err, chartSpec.Name+": failed install")
+			return err
 		}
 	}
-	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Release Status ---\n\n" + string(rel.Info.Status) + "\n\n")
-	return rel, nil
+	return deployer.Deploy(ctx, chartSpec, blueprint.Spec.ModulesNamespace, releaseName, args, log)
 }
 
 // CopyMap copies a map
@@ -285,13 +258,50 @@ func SetMapField(obj map[string]interface{}, k string, v interface{}) bool {
 	return true
 }
 
-// updateModuleState updates the module state
+// updateModuleState updates the module state and records the state transition in the module history
 func (r *BlueprintReconciler) updateModuleState(blueprint *fapp.Blueprint, instanceName string, isReady bool, err string) {
 	state := fapp.ObservedState{
 		Ready: isReady,
 		Error: err,
 	}
 	blueprint.Status.ModulesState[instanceName] = state
+	history, recorded := fapp.RecordStateTransition(blueprint.Status.ModulesHistory[instanceName], &state, metav1.Now())
+	if !recorded {
+		return
+	}
+	blueprint.Status.ModulesHistory[instanceName] = history
+	if start := notReadySince(history); isReady && start != nil {
+		metrics.ModuleTimeToReady.WithLabelValues(blueprint.Spec.Modules[instanceName].Chart.Name).
+			Observe(history[len(history)-1].Time.Sub(start.Time).Seconds())
+	}
+}
+
+// pendingDependencies returns the instance names of the module dependencies that are not ready yet
+func pendingDependencies(blueprint *fapp.Blueprint, module *fapp.BlueprintModule) []string {
+	pending := []string{}
+	for _, dependency := range module.DependsOn {
+		if _, found := blueprint.Spec.Modules[dependency]; !found {
+			continue
+		}
+		if !blueprint.Status.ModulesState[dependency].Ready {
+			pending = append(pending, dependency)
+		}
+	}
+	return pending
+}
+
+// notReadySince returns the time the module has left the ready state before the last transition,
+// or the time of the first recorded transition if the module has not been ready before.
+func notReadySince(history []fapp.StateTransition) *metav1.Time {
+	if len(history) < 2 {
+		return nil
+	}
+	for i := len(history) - 2; i > 0; i-- {
+		if history[i-1].To == fapp.ReadyState {
+			return &history[i].Time
+		}
+	}
+	return &history[0].Time
 }
 
 //nolint:gocyclo
@@ -309,9 +319,25 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	if blueprint.Status.Releases == nil {
 		blueprint.Status.Releases = map[string]int64{}
 	}
+	// the releases of a rolled back generation are not deployed again until the blueprint changes
+	if rollout := blueprint.Status.Rollout; !updateRequired && rollout != nil && rollout.Phase == fapp.RolloutRolledBack {
+		blueprint.Status.ObservedState.Error = rolledBackError(rollout)
+		return ctrl.Result{}, nil
+	}
+	revision := r.rolloutRevision(ctx, cfg, blueprint, updateRequired, log)
+	rollingOut := blueprint.Status.Rollout != nil
 	if blueprint.Status.ModulesState == nil {
 		blueprint.Status.ModulesState = make(map[string]fapp.ObservedState)
 	}
+	if blueprint.Status.ModulesHistory == nil {
+		blueprint.Status.ModulesHistory = make(map[string][]fapp.StateTransition)
+	}
+	// forget the history of modules that are not a part of the blueprint anymore
+	for instanceName := range blueprint.Status.ModulesHistory {
+		if _, exists := blueprint.Spec.Modules[instanceName]; !exists {
+			delete(blueprint.Status.ModulesHistory, instanceName)
+		}
+	}
 	// count the overall number of Helm releases and how many of them are ready
 	numReleases, numReady := 0, 0
 	// Add debug information to module labels
@@ -320,9 +346,18 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 	}
 	blueprint.Labels[managerUtils.BlueprintNameLabel] = blueprint.Name
 	blueprint.Labels[managerUtils.BlueprintNamespaceLabel] = blueprint.Namespace
-
+	appName := managerUtils.GetApplicationNameFromLabels(blueprint.Labels)
+	// the releases of a revision reference each other by their revision names
+	instanceNames := make([]string, 0, len(blueprint.Spec.Modules))
 	for instanceName := range blueprint.Spec.Modules {
+		instanceNames = append(instanceNames, instanceName)
+	}
+	renames := revisionRenames(appName, uuid, instanceNames, revision)
+
+	// modules are deployed after the modules they depend on
+	for _, instanceName := range sortModuleInstances(blueprint.Spec.Modules) {
 		module := blueprint.Spec.Modules[instanceName]
+		releaseName := revisionReleaseName(appName, uuid, instanceName, revision)
 		// Get arguments by type
 		helmValues := HelmValues{
 			ModuleArguments: module.Arguments,
@@ -330,31 +365,73 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 			Labels:          blueprint.Labels,
 			UUID:            uuid,
 		}
+		// the credential broker delivers the credentials in secrets that only the service account of the release can read
+		var credentials map[string]taxonomy.SecretRef
+		if environment.IsCredentialBrokerEnabled() {
+			arguments := module.Arguments.DeepCopy()
+			credentials = brokerSecretRefs(arguments, blueprint.Spec.ModulesNamespace, releaseName)
+			helmValues.ModuleArguments = *arguments
+			if len(credentials) > 0 {
+				helmValues.ServiceAccount = &ServiceAccountValues{Create: false, Name: releaseName}
+			}
+		}
 		args, err := utils.StructToMap(&helmValues)
 		if err != nil {
 			return ctrl.Result{}, errors.WithMessage(err, "Blueprint step arguments are invalid")
 		}
+		network := &module.Network
+		if len(renames) > 0 {
+			args = renameValues(args, renames).(map[string]interface{})
+			network = renameNetwork(network, blueprint.Spec.Cluster, renames)
+		}
 
-		releaseName := managerUtils.GetReleaseName(managerUtils.GetApplicationNameFromLabels(blueprint.Labels),
-			uuid,
-			instanceName)
 		log.Trace().Msg("Release name: " + releaseName)
 		numReleases++
 
+		deployer, err := r.moduleDeployer(cfg, module.Chart.Type)
+		if err != nil {
+			blueprint.Status.ObservedState.Error += errors.Wrap(err, "ChartDeploymentFailure: ").Error() + "\n"
+			r.updateModuleState(blueprint, instanceName, false, err.Error())
+			blueprint.Status.Releases[releaseName] = blueprint.Status.ObservedGeneration
+			continue
+		}
 		// check the release status
-		rel, err := r.Helmer.Status(cfg, releaseName)
-		// nonexistent release or a failed release - re-apply the chart
-		if updateRequired || err != nil || rel == nil || rel.Info.Status == release.StatusFailed {
+		rel, err := deployer.Status(ctx, blueprint.Spec.ModulesNamespace, releaseName)
+		// a release is installed once the modules it depends on are ready
+		if err == nil && rel == nil {
+			if pending := pendingDependencies(blueprint, &module); len(pending) > 0 {
+				log.Trace().Msgf("Release %s is waiting for its dependencies %v", releaseName, pending)
+				r.updateModuleState(blueprint, instanceName, false, "")
+				continue
+			}
+		}
+		// a failed release of a rollout is not re-applied, the rollout is rolled back
+		if rollingOut && err == nil && rel != nil && rel.State == FailedRelease {
+			blueprint.Status.ObservedState.Error += "ChartDeploymentFailure: release " + releaseName + " has failed\n"
+			r.updateModuleState(blueprint, instanceName, false, "release "+releaseName+" has failed")
+			blueprint.Status.Releases[releaseName] = blueprint.Status.ObservedGeneration
+			continue
+		}
+		// the credential copies are reconciled on every reconcile rather than only when the chart is applied,
+		// so that changes of the source secrets reach deployed releases as well
+		if credentialsErr := r.deliverCredentials(ctx, blueprint, releaseName, module.CredentialKeys, credentials, log); credentialsErr != nil {
+			blueprint.Status.ObservedState.Error += errors.Wrap(credentialsErr, "ChartDeploymentFailure: ").Error() + "\n"
+			r.updateModuleState(blueprint, instanceName, false, credentialsErr.Error())
+		} else if updateRequired || err != nil || rel == nil || rel.State == FailedRelease {
+			// nonexistent release or a failed release - re-apply the chart
 			// Process templates with arguments
 			chart := module.Chart
-			if _, err = r.applyChartResource(ctx, cfg, chart, &module.Network, args, blueprint, releaseName, log); err != nil {
+			if err = r.applyChartResource(ctx, deployer, &chart, network, args, blueprint, releaseName, log); err != nil {
 				blueprint.Status.ObservedState.Error += errors.Wrap(err, "ChartDeploymentFailure: ").Error() + "\n"
 				r.updateModuleState(blueprint, instanceName, false, err.Error())
 			} else {
 				r.updateModuleState(blueprint, instanceName, false, "")
 			}
-		} else if rel != nil && rel.Info.Status == release.StatusDeployed {
-			status, errMsg := r.checkReleaseStatus(rel, uuid)
+		} else if rel.State == DeployedRelease {
+			status, errMsg := r.checkReleaseStatus(rel.Resources, uuid)
+			if policiesErr := r.refreshIsolationPolicies(ctx, releaseName, network, blueprint, log); policiesErr != nil {
+				status, errMsg = corev1.ConditionFalse, policiesErr.Error()
+			}
 			if status == corev1.ConditionFalse {
 				blueprint.Status.ObservedState.Error += "ResourceAllocationFailure: " + errMsg + "\n"
 				r.updateModuleState(blueprint, instanceName, false, errMsg)
@@ -365,10 +442,19 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 		}
 		blueprint.Status.Releases[releaseName] = blueprint.Status.ObservedGeneration
 	}
+	// the releases of the previous revision serve the workload until the rollout completes
+	if rollingOut && !r.progressRollout(ctx, cfg, blueprint, numReady == numReleases, log) {
+		if blueprint.Status.ObservedState.Error != "" {
+			return ctrl.Result{}, nil
+		}
+		log.Trace().Msg("blueprint rollout is in progress, will try again")
+		interval, _ := environment.GetResourcesPollingInterval()
+		return ctrl.Result{RequeueAfter: interval}, nil
+	}
 	// clean-up
 	for release, version := range blueprint.Status.Releases {
 		if version != blueprint.Status.ObservedGeneration {
-			_, err := r.Helmer.Uninstall(cfg, release)
+			err := r.uninstallRelease(ctx, cfg, blueprint.Spec.ModulesNamespace, release)
 			if err != nil {
 				log.Error().Err(err).Str(logging.ACTION, logging.DELETE).Msg("Error uninstalling release " + release)
 			} else {
@@ -397,11 +483,12 @@ func (r *BlueprintReconciler) reconcile(ctx context.Context, cfg *action.Configu
 // NewBlueprintReconciler creates a new reconciler for Blueprint resources
 func NewBlueprintReconciler(mgr ctrl.Manager, name string, helmer helm.Interface) *BlueprintReconciler {
 	return &BlueprintReconciler{
-		Client: mgr.GetClient(),
-		Name:   name,
-		Log:    logging.LogInit(logging.CONTROLLER, name),
-		Scheme: mgr.GetScheme(),
-		Helmer: helmer,
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Name:      name,
+		Log:       logging.LogInit(logging.CONTROLLER, name),
+		Scheme:    mgr.GetScheme(),
+		Helmer:    helmer,
 	}
 }
 
@@ -515,21 +602,9 @@ func (r *BlueprintReconciler) matchesCondition(res *unstructured.Unstructured, c
 	return true
 }
 
-func (r *BlueprintReconciler) checkReleaseStatus(rel *release.Release, uuid string) (corev1.ConditionStatus, string) {
+func (r *BlueprintReconciler) checkReleaseStatus(resources []*unstructured.Unstructured, uuid string) (corev1.ConditionStatus, string) {
 	log := r.Log.With().Str(managerUtils.FybrikAppUUID, uuid).Logger()
 
-	// get all resources for the given helm release in their current state
-	var resources []*unstructured.Unstructured
-	for versionKind := range rel.Info.Resources {
-		for _, obj := range rel.Info.Resources[versionKind] {
-			if unstr, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj); err == nil {
-				resources = append(resources, &unstructured.Unstructured{Object: unstr})
-			} else {
-				log.Err(err).Msg("error getting resources")
-				return corev1.ConditionUnknown, ""
-			}
-		}
-	}
 	// return True if all resources are ready, False - if any resource failed, Unknown - otherwise
 	numReady := 0
 	for _, res := range resources {
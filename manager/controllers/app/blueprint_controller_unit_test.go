@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -24,6 +26,7 @@ import (
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/helm"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 )
 
 func readBlueprint(f string) (*fapp.Blueprint, error) {
@@ -167,3 +170,46 @@ func TestLongReleaseName(t *testing.T) {
 	g.Expect(relName2).To(gomega.HavePrefix(appName + uuid))
 	g.Expect(relName2).To(gomega.HaveLen(53))
 }
+
+// This test checks that the module state transitions are recorded in a bounded history
+func TestModuleStateHistory(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	instanceName := "module-instance"
+	chartName := "history-test-chart"
+	blueprint := &fapp.Blueprint{
+		Spec: fapp.BlueprintSpec{
+			Modules: map[string]fapp.BlueprintModule{instanceName: {
+				Name:  "module",
+				Chart: fapp.ChartSpec{Name: chartName}}},
+		},
+		Status: fapp.BlueprintStatus{
+			ModulesState:   map[string]fapp.ObservedState{},
+			ModulesHistory: map[string][]fapp.StateTransition{},
+		},
+	}
+	r := &BlueprintReconciler{Log: logging.LogInit(logging.CONTROLLER, "test-blueprint-controller")}
+
+	// the same state is recorded only once
+	r.updateModuleState(blueprint, instanceName, false, "")
+	r.updateModuleState(blueprint, instanceName, false, "")
+	g.Expect(blueprint.Status.ModulesHistory[instanceName]).To(gomega.HaveLen(1))
+	r.updateModuleState(blueprint, instanceName, true, "")
+	history := blueprint.Status.ModulesHistory[instanceName]
+	g.Expect(history).To(gomega.HaveLen(2))
+	g.Expect(history[1].From).To(gomega.Equal(fapp.PendingState))
+	g.Expect(history[1].To).To(gomega.Equal(fapp.ReadyState))
+	g.Expect(testutil.CollectAndCount(metrics.ModuleTimeToReady.WithLabelValues(chartName).(prometheus.Histogram))).
+		To(gomega.Equal(1))
+
+	// the history is bounded
+	for i := 0; i < fapp.MaxStateTransitions; i++ {
+		r.updateModuleState(blueprint, instanceName, false, "failure")
+		r.updateModuleState(blueprint, instanceName, true, "")
+	}
+	history = blueprint.Status.ModulesHistory[instanceName]
+	g.Expect(history).To(gomega.HaveLen(fapp.MaxStateTransitions))
+	g.Expect(history[len(history)-2].To).To(gomega.Equal(fapp.FailedState))
+	g.Expect(history[len(history)-2].Message).To(gomega.Equal("failure"))
+	g.Expect(fapp.LastState(history)).To(gomega.Equal(fapp.ReadyState))
+}
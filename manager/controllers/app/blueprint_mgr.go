@@ -46,6 +46,11 @@ func (r *PlotterReconciler) RefineInstances(instances []ModuleInstanceSpec) []Mo
 			instance.Module.Arguments.Assets = append(instance.Module.Arguments.Assets, instances[ind].Module.Arguments.Assets...)
 			// AssetID is used for step name generation
 			instance.Module.AssetIDs = append(instance.Module.AssetIDs, instances[ind].Module.AssetIDs...)
+			for _, dependency := range instances[ind].Module.DependsOn {
+				if !utils.HasString(dependency, instance.Module.DependsOn) {
+					instance.Module.DependsOn = append(instance.Module.DependsOn, dependency)
+				}
+			}
 			instanceMap[key] = instance
 		}
 	}
@@ -83,6 +88,8 @@ func (r *PlotterReconciler) GenerateBlueprints(instances []ModuleInstanceSpec,
 // The credentials themselves are not included in the blueprint.
 func (r *PlotterReconciler) GenerateBlueprint(instances []ModuleInstanceSpec,
 	clusterName string, plotter *fapp.Plotter, services Services, ingressMap map[string][]string) fapp.BlueprintSpec {
+	serviceAccounts := qualifyServiceAccounts(plotter.Spec.Selector.ServiceAccounts,
+		mngrUtils.GetApplicationNamespaceFromLabels(plotter.Labels))
 	spec := fapp.BlueprintSpec{
 		Cluster:          clusterName,
 		ModulesNamespace: plotter.Spec.ModulesNamespace,
@@ -91,6 +98,7 @@ func (r *PlotterReconciler) GenerateBlueprint(instances []ModuleInstanceSpec,
 			WorkloadSelector: plotter.Spec.Selector.WorkloadSelector,
 			Namespaces:       plotter.Spec.Selector.Namespaces,
 			IPBlocks:         plotter.Spec.Selector.IPBlocks,
+			ServiceAccounts:  serviceAccounts,
 			Context:          plotter.Spec.AppInfo,
 		},
 	}
@@ -114,10 +122,15 @@ func (r *PlotterReconciler) GenerateBlueprint(instances []ModuleInstanceSpec,
 				if arg != nil {
 					if argKey, found := getServiceUniqueKey(arg.Connection, services); found {
 						argService := services[argKey]
-						egress = append(egress, fapp.ModuleDeployment{
+						deployment := fapp.ModuleDeployment{
 							Cluster: argService.Cluster,
 							Release: argService.Release,
-							URLs:    getURLsFromConnection(arg.Connection)})
+							URLs:    getURLsFromConnection(arg.Connection)}
+						if argService.Cluster != clusterName {
+							// a module in another cluster is reached through the ingress gateways of its cluster
+							deployment.IPBlocks = argService.IngressIPBlocks
+						}
+						egress = append(egress, deployment)
 					} else {
 						urls = append(urls, getURLsFromConnection(arg.Connection)...)
 					}
@@ -137,10 +150,15 @@ func (r *PlotterReconciler) GenerateBlueprint(instances []ModuleInstanceSpec,
 		keys := ingressMap[moduleKey]
 		for _, key := range keys {
 			argService := services[key]
-			ingress = append(ingress, fapp.ModuleDeployment{
+			deployment := fapp.ModuleDeployment{
 				Cluster: argService.Cluster,
 				Release: argService.Release,
-				URLs:    getURLsFromConnection(argService.API.Connection)})
+				URLs:    getURLsFromConnection(argService.API.Connection)}
+			if argService.Cluster != clusterName {
+				// a module in another cluster connects through the egress gateways of its cluster
+				deployment.IPBlocks = argService.EgressIPBlocks
+			}
+			ingress = append(ingress, deployment)
 		}
 		instances[ind].Module.Network = fapp.ModuleNetwork{
 			Endpoint: isEndpoint,
@@ -235,3 +253,14 @@ func createIngressMap(plotter *fapp.Plotter, moduleInstances []ModuleInstanceSpe
 	}
 	return ingressMap
 }
+
+// qualifyServiceAccounts returns the service accounts in the form namespace/name,
+// service accounts without a namespace belong to the application namespace
+func qualifyServiceAccounts(serviceAccounts []string, namespace string) []string {
+	var qualified []string
+	for _, sa := range serviceAccounts {
+		ns, name := splitNamespacedName(sa, namespace)
+		qualified = append(qualified, ns+"/"+name)
+	}
+	return qualified
+}
@@ -0,0 +1,242 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"helm.sh/helm/v3/pkg/action"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// Strategies of rolling out blueprint changes
+const (
+	// InPlaceRollout upgrades the module releases in place
+	InPlaceRollout = "inplace"
+	// BlueGreenRollout deploys the module releases of a new blueprint generation next to the serving releases,
+	// and switches to them once they are ready
+	BlueGreenRollout = "bluegreen"
+)
+
+// RolledBackError is reported when the module releases of a new blueprint generation have been rolled back
+const RolledBackError = "rollout of generation %d has been rolled back"
+
+// revisionReleaseName returns the name of the release of a module instance in the given blueprint revision
+func revisionReleaseName(applicationName, uuid, instanceName string, revision int64) string {
+	if revision == 0 {
+		return managerUtils.GetReleaseName(applicationName, uuid, instanceName)
+	}
+	return managerUtils.GetReleaseName(applicationName, uuid, instanceName+"-r"+strconv.FormatInt(revision, 10))
+}
+
+// revisionRenames maps the release names of the module instances, as they are referenced by the plotter,
+// to the release names of the given revision
+func revisionRenames(applicationName, uuid string, instanceNames []string, revision int64) map[string]string {
+	renames := map[string]string{}
+	if revision == 0 {
+		return renames
+	}
+	for _, instanceName := range instanceNames {
+		renames[managerUtils.GetReleaseName(applicationName, uuid, instanceName)] =
+			revisionReleaseName(applicationName, uuid, instanceName, revision)
+	}
+	return renames
+}
+
+// servedReleaseNames maps the release names of the module instances in the plotter to the release names
+// of the revisions that serve the workload in the clusters of the plotter
+func servedReleaseNames(applicationName, uuid string, plotterSpec *fapp.PlotterSpec,
+	blueprints map[string]fapp.MetaBlueprint) map[string]string {
+	renames := map[string]string{}
+	for _, flow := range plotterSpec.Flows {
+		for _, subFlow := range flow.SubFlows {
+			for _, steps := range subFlow.Steps {
+				for _, step := range steps {
+					revision := blueprints[step.Cluster].Status.Revision
+					instanceNames := []string{}
+					for _, module := range plotterSpec.Templates[step.Template].Modules {
+						instanceNames = append(instanceNames, managerUtils.CreateStepName(module.Name, flow.AssetID, module.Scope))
+					}
+					for name, revisionName := range revisionRenames(applicationName, uuid, instanceNames, revision) {
+						renames[name] = revisionName
+					}
+				}
+			}
+		}
+	}
+	return renames
+}
+
+// isNameChar checks whether the character may be a part of a release name
+func isNameChar(c byte) bool {
+	return c == '-' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// renameReleases replaces the release names referenced in a string, e.g. in the host name of a module service.
+// A release name is not replaced if it is a part of a longer name.
+func renameReleases(value string, renames map[string]string) string {
+	for name, revisionName := range renames {
+		var result strings.Builder
+		rest := value
+		for i := strings.Index(rest, name); i >= 0; i = strings.Index(rest, name) {
+			end := i + len(name)
+			if (i > 0 && isNameChar(rest[i-1])) || (end < len(rest) && isNameChar(rest[end])) {
+				result.WriteString(rest[:end])
+			} else {
+				result.WriteString(rest[:i])
+				result.WriteString(revisionName)
+			}
+			rest = rest[end:]
+		}
+		result.WriteString(rest)
+		value = result.String()
+	}
+	return value
+}
+
+// renameValues replaces the release names referenced in the strings of the given values
+func renameValues(value interface{}, renames map[string]string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return renameReleases(v, renames)
+	case map[string]interface{}:
+		renamed := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			renamed[key] = renameValues(val, renames)
+		}
+		return renamed
+	case []interface{}:
+		renamed := make([]interface{}, len(v))
+		for i, val := range v {
+			renamed[i] = renameValues(val, renames)
+		}
+		return renamed
+	default:
+		return value
+	}
+}
+
+// renameNetwork replaces the release names of the modules in the same cluster that the module communicates with
+func renameNetwork(network *fapp.ModuleNetwork, cluster string, renames map[string]string) *fapp.ModuleNetwork {
+	renamed := network.DeepCopy()
+	for _, deployments := range [][]fapp.ModuleDeployment{renamed.Ingress, renamed.Egress} {
+		for i := range deployments {
+			if deployments[i].Cluster != cluster {
+				continue
+			}
+			if revisionName, found := renames[deployments[i].Release]; found {
+				deployments[i].Release = revisionName
+			}
+			for j := range deployments[i].URLs {
+				deployments[i].URLs[j] = renameReleases(deployments[i].URLs[j], renames)
+			}
+		}
+	}
+	return renamed
+}
+
+// renameConnection replaces the release names referenced in the properties of a connection
+func renameConnection(connection taxonomy.Connection, renames map[string]string) taxonomy.Connection {
+	if len(renames) == 0 {
+		return connection
+	}
+	renamed := taxonomy.Connection{Name: connection.Name,
+		AdditionalProperties: serde.Properties{Items: make(map[string]interface{})}}
+	for key, val := range connection.AdditionalProperties.Items {
+		renamed.AdditionalProperties.Items[key] = renameValues(val, renames)
+	}
+	return renamed
+}
+
+// rolloutRevision returns the revision of the module releases deployed for the observed blueprint generation.
+// If the blue-green strategy is used, a rollout is started when the spec of a deployed blueprint changes.
+// The releases of a previous rollout that has not completed are removed.
+func (r *BlueprintReconciler) rolloutRevision(ctx context.Context, cfg *action.Configuration, blueprint *fapp.Blueprint,
+	updateRequired bool, log *zerolog.Logger) int64 {
+	rollout := blueprint.Status.Rollout
+	if !updateRequired {
+		if rollout != nil && rollout.Phase == fapp.RolloutProgressing {
+			return rollout.Generation
+		}
+		return blueprint.Status.Revision
+	}
+	if rollout != nil {
+		r.removeRolloutReleases(ctx, cfg, blueprint, rollout.Generation, log)
+		blueprint.Status.Rollout = nil
+	}
+	if environment.GetBlueprintRolloutStrategy() != BlueGreenRollout || len(blueprint.Status.Releases) == 0 {
+		return blueprint.Status.Revision
+	}
+	log.Info().Msg("Rolling out generation " + strconv.FormatInt(blueprint.GetGeneration(), 10) + " next to the serving releases")
+	blueprint.Status.Rollout = &fapp.BlueprintRollout{
+		Generation: blueprint.GetGeneration(),
+		Phase:      fapp.RolloutProgressing,
+		StartTime:  metav1.Now(),
+	}
+	// the states of the serving releases do not apply to the releases of the new generation
+	blueprint.Status.ModulesState = map[string]fapp.ObservedState{}
+	return blueprint.GetGeneration()
+}
+
+// progressRollout rolls back the rollout if its releases have failed or have not become ready in time,
+// and switches to its releases once all of them are ready.
+// It returns false as long as the releases of the previous revision serve the workload.
+func (r *BlueprintReconciler) progressRollout(ctx context.Context, cfg *action.Configuration, blueprint *fapp.Blueprint,
+	ready bool, log *zerolog.Logger) bool {
+	rollout := blueprint.Status.Rollout
+	failure := strings.TrimSpace(blueprint.Status.ObservedState.Error)
+	// if an error exists it is logged in LogEnvVariables and a default value is used
+	timeout, _ := environment.GetBlueprintRolloutTimeout()
+	if failure == "" && !ready && time.Since(rollout.StartTime.Time) > timeout {
+		failure = "the module releases are not ready after " + timeout.String()
+	}
+	if failure != "" {
+		log.Error().Str(logging.ACTION, logging.DELETE).Msg("Rolling back generation " +
+			strconv.FormatInt(rollout.Generation, 10) + ": " + failure)
+		r.removeRolloutReleases(ctx, cfg, blueprint, rollout.Generation, log)
+		rollout.Phase = fapp.RolloutRolledBack
+		rollout.Message = failure
+		blueprint.Status.ObservedState.Error = rolledBackError(rollout)
+		return false
+	}
+	if !ready {
+		return false
+	}
+	log.Info().Msg("Switching to the releases of generation " + strconv.FormatInt(rollout.Generation, 10))
+	blueprint.Status.Revision = rollout.Generation
+	blueprint.Status.Rollout = nil
+	return true
+}
+
+// removeRolloutReleases uninstalls the releases deployed for the given blueprint generation
+func (r *BlueprintReconciler) removeRolloutReleases(ctx context.Context, cfg *action.Configuration, blueprint *fapp.Blueprint,
+	generation int64, log *zerolog.Logger) {
+	for release, version := range blueprint.Status.Releases {
+		if version != generation {
+			continue
+		}
+		if err := r.uninstallRelease(ctx, cfg, blueprint.Spec.ModulesNamespace, release); err != nil {
+			log.Error().Err(err).Str(logging.ACTION, logging.DELETE).Msg("Error uninstalling release " + release)
+			continue
+		}
+		delete(blueprint.Status.Releases, release)
+	}
+}
+
+// rolledBackError returns the error reported for a rolled back rollout
+func rolledBackError(rollout *fapp.BlueprintRollout) string {
+	return fmt.Sprintf(RolledBackError, rollout.Generation) + ": " + rollout.Message
+}
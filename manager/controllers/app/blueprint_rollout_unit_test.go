@@ -0,0 +1,215 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/helm"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// releasesHelmer keeps the installed releases by name, new releases get the configured status
+type releasesHelmer struct {
+	*helm.Fake
+	status   release.Status
+	releases map[string]*release.Release
+}
+
+func newReleasesHelmer() *releasesHelmer {
+	return &releasesHelmer{Fake: helm.NewEmptyFake(), status: release.StatusDeployed, releases: map[string]*release.Release{}}
+}
+
+func (h *releasesHelmer) Install(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	h.releases[releaseName] = &release.Release{Name: releaseName, Config: vals, Info: &release.Info{Status: h.status}}
+	return h.releases[releaseName], nil
+}
+
+func (h *releasesHelmer) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *chart.Chart, kubeNamespace,
+	releaseName string, vals map[string]interface{}) (*release.Release, error) {
+	return h.Install(ctx, cfg, chrt, kubeNamespace, releaseName, vals)
+}
+
+func (h *releasesHelmer) Status(cfg *action.Configuration, releaseName string) (*release.Release, error) {
+	return h.releases[releaseName], nil
+}
+
+func (h *releasesHelmer) IsInstalled(cfg *action.Configuration, releaseName string) (bool, error) {
+	_, found := h.releases[releaseName]
+	return found, nil
+}
+
+func (h *releasesHelmer) Uninstall(cfg *action.Configuration, releaseName string) (*release.UninstallReleaseResponse, error) {
+	delete(h.releases, releaseName)
+	return &release.UninstallReleaseResponse{}, nil
+}
+
+func (h *releasesHelmer) releaseNames() []string {
+	names := []string{}
+	for name := range h.releases {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestRenameReleases(t *testing.T) {
+	g := gomega.NewWithT(t)
+	renames := revisionRenames("notebook", "1234", []string{"read", "copy"}, 3)
+	g.Expect(renames).To(gomega.Equal(map[string]string{
+		"notebook1234-read": "notebook1234-read-r3",
+		"notebook1234-copy": "notebook1234-copy-r3",
+	}))
+	g.Expect(revisionRenames("notebook", "1234", []string{"read"}, 0)).To(gomega.BeEmpty())
+
+	// names that are a part of longer names are not replaced
+	g.Expect(renameReleases("grpc://notebook1234-read.fybrik-blueprints:80", renames)).
+		To(gomega.Equal("grpc://notebook1234-read-r3.fybrik-blueprints:80"))
+	g.Expect(renameReleases("notebook1234-read-other.ns notebook1234-reader", renames)).
+		To(gomega.Equal("notebook1234-read-other.ns notebook1234-reader"))
+	g.Expect(renameReleases("notebook1234-read-r3.ns", renames)).To(gomega.Equal("notebook1234-read-r3.ns"))
+
+	values := map[string]interface{}{
+		"assets": []interface{}{map[string]interface{}{"endpoint": "http://notebook1234-copy:8080", "port": 8080}},
+		"name":   "notebook1234-read",
+	}
+	g.Expect(renameValues(values, renames)).To(gomega.Equal(map[string]interface{}{
+		"assets": []interface{}{map[string]interface{}{"endpoint": "http://notebook1234-copy-r3:8080", "port": 8080}},
+		"name":   "notebook1234-read-r3",
+	}))
+	g.Expect(values["name"]).To(gomega.Equal("notebook1234-read"))
+
+	network := &fapp.ModuleNetwork{
+		Ingress: []fapp.ModuleDeployment{{Cluster: "cluster1", Release: "notebook1234-copy", URLs: []string{"notebook1234-copy:80"}}},
+		Egress:  []fapp.ModuleDeployment{{Cluster: "cluster2", Release: "notebook1234-read", URLs: []string{"notebook1234-read:80"}}},
+	}
+	renamed := renameNetwork(network, "cluster1", renames)
+	g.Expect(renamed.Ingress[0].Release).To(gomega.Equal("notebook1234-copy-r3"))
+	g.Expect(renamed.Ingress[0].URLs).To(gomega.Equal([]string{"notebook1234-copy-r3:80"}))
+	// modules in other clusters are not rolled out with the blueprint
+	g.Expect(renamed.Egress[0]).To(gomega.Equal(network.Egress[0]))
+	g.Expect(network.Ingress[0].Release).To(gomega.Equal("notebook1234-copy"))
+}
+
+func TestServedReleaseNames(t *testing.T) {
+	g := gomega.NewWithT(t)
+	plotterSpec := &fapp.PlotterSpec{
+		Templates: map[string]fapp.Template{
+			"read": {Modules: []fapp.ModuleInfo{{Name: "arrow-flight", Scope: fapp.Workload}}},
+		},
+		Flows: []fapp.Flow{{AssetID: "xyz", SubFlows: []fapp.SubFlow{{
+			Steps: [][]fapp.DataFlowStep{{{Cluster: "cluster1", Template: "read"}}},
+		}}}},
+	}
+	blueprints := map[string]fapp.MetaBlueprint{}
+	g.Expect(servedReleaseNames("notebook", "1234", plotterSpec, blueprints)).To(gomega.BeEmpty())
+
+	blueprints["cluster1"] = fapp.MetaBlueprint{Status: fapp.BlueprintStatus{Revision: 2}}
+	renames := servedReleaseNames("notebook", "1234", plotterSpec, blueprints)
+	g.Expect(renames).To(gomega.Equal(map[string]string{"notebook1234-arrow-flight": "notebook1234-arrow-flight-r2"}))
+
+	connection := taxonomy.Connection{Name: "fybrik-arrow-flight", AdditionalProperties: serde.Properties{
+		Items: map[string]interface{}{"fybrik-arrow-flight": map[string]interface{}{
+			"hostname": "notebook1234-arrow-flight.fybrik-blueprints", "port": 80}}}}
+	renamed := renameConnection(connection, renames)
+	g.Expect(renamed.AdditionalProperties.Items["fybrik-arrow-flight"]).To(gomega.Equal(map[string]interface{}{
+		"hostname": "notebook1234-arrow-flight-r2.fybrik-blueprints", "port": 80}))
+}
+
+// This test checks that blueprint changes are rolled out next to the serving releases, and rolled back if they fail
+func TestBlueprintBlueGreenRollout(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv(environment.BlueprintRolloutStrategyKey, BlueGreenRollout)
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	blueprint.Spec.ModulesNamespace = environment.GetDefaultModulesNamespace()
+	s := utils.NewScheme(g)
+	helmer := newReleasesHelmer()
+	r := &BlueprintReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).Build(),
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helmer,
+	}
+	reconcileGeneration := func(generation int64) {
+		blueprint.Generation = generation
+		_, err = r.reconcile(context.Background(), nil, &r.Log, blueprint)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	baseReleases := []string{"notebook1234-notebook-copy-batch", "notebook1234-notebook-read-module"}
+	revisionReleases := []string{"notebook1234-notebook-copy-batch-r2", "notebook1234-notebook-read-module-r2"}
+
+	// the first generation is deployed in place
+	reconcileGeneration(1)
+	reconcileGeneration(1)
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(blueprint.Status.Rollout).To(gomega.BeNil())
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(baseReleases))
+
+	// a new generation is deployed next to the serving releases
+	reconcileGeneration(2)
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeFalse())
+	g.Expect(blueprint.Status.Rollout).ToNot(gomega.BeNil())
+	g.Expect(blueprint.Status.Rollout.Phase).To(gomega.Equal(fapp.RolloutProgressing))
+	g.Expect(blueprint.Status.Revision).To(gomega.BeZero())
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(append(revisionReleases, baseReleases...)))
+
+	// the new releases are ready - the old releases are removed
+	reconcileGeneration(2)
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(blueprint.Status.Rollout).To(gomega.BeNil())
+	g.Expect(blueprint.Status.Revision).To(gomega.Equal(int64(2)))
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(revisionReleases))
+	g.Expect(blueprint.Status.Releases).To(gomega.HaveLen(2))
+
+	// failed releases of a new generation are rolled back
+	helmer.status = release.StatusFailed
+	reconcileGeneration(3)
+	g.Expect(helmer.releaseNames()).To(gomega.HaveLen(4))
+	reconcileGeneration(3)
+	g.Expect(blueprint.Status.Rollout.Phase).To(gomega.Equal(fapp.RolloutRolledBack))
+	g.Expect(blueprint.Status.ObservedState.Error).To(gomega.HavePrefix("rollout of generation 3 has been rolled back"))
+	g.Expect(blueprint.Status.Revision).To(gomega.Equal(int64(2)))
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(revisionReleases))
+	// the rolled back generation is not deployed again
+	reconcileGeneration(3)
+	g.Expect(blueprint.Status.ObservedState.Error).To(gomega.HavePrefix("rollout of generation 3 has been rolled back"))
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(revisionReleases))
+
+	// releases that are not ready in time are rolled back
+	helmer.status = release.StatusPendingInstall
+	reconcileGeneration(4)
+	g.Expect(blueprint.Status.Rollout.Phase).To(gomega.Equal(fapp.RolloutProgressing))
+	g.Expect(helmer.releaseNames()).To(gomega.HaveLen(4))
+	blueprint.Status.Rollout.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+	reconcileGeneration(4)
+	g.Expect(blueprint.Status.Rollout.Phase).To(gomega.Equal(fapp.RolloutRolledBack))
+	g.Expect(blueprint.Status.Rollout.Message).To(gomega.Equal("the module releases are not ready after 10m0s"))
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(revisionReleases))
+
+	// in-place upgrades keep the names of the serving releases
+	t.Setenv(environment.BlueprintRolloutStrategyKey, InPlaceRollout)
+	helmer.status = release.StatusDeployed
+	reconcileGeneration(5)
+	reconcileGeneration(5)
+	g.Expect(blueprint.Status.ObservedState.Ready).To(gomega.BeTrue())
+	g.Expect(blueprint.Status.Rollout).To(gomega.BeNil())
+	g.Expect(helmer.releaseNames()).To(gomega.ConsistOf(revisionReleases))
+	g.Expect(blueprint.Status.Releases).To(gomega.HaveKeyWithValue(revisionReleases[0], int64(5)))
+}
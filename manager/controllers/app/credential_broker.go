@@ -0,0 +1,194 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/utils"
+)
+
+const brokeredSecretHashLength = 10
+
+// brokeredSecretName returns the name of the secret of the release that holds a copy of the given credentials
+func brokeredSecretName(releaseName string, source taxonomy.SecretRef) string {
+	return releaseName + "-" + utils.Hash(source.Namespace+"/"+source.Name, brokeredSecretHashLength)
+}
+
+// brokerSecretRefs points the datastores of the module arguments to the copies of their credentials in the
+// modules namespace. It returns the credentials to copy, mapped by the names of the copies.
+func brokerSecretRefs(arguments *fapp.ModuleArguments, namespace, releaseName string) map[string]taxonomy.SecretRef {
+	sources := map[string]taxonomy.SecretRef{}
+	for i := range arguments.Assets {
+		for _, dataStore := range arguments.Assets[i].Arguments {
+			if dataStore == nil || dataStore.SecretRef == nil {
+				continue
+			}
+			name := brokeredSecretName(releaseName, *dataStore.SecretRef)
+			sources[name] = *dataStore.SecretRef
+			dataStore.SecretRef = &taxonomy.SecretRef{Name: name, Namespace: namespace}
+		}
+	}
+	return sources
+}
+
+// apiReader returns the reader of the objects that are not cached by the manager
+func (r *BlueprintReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// deliverCredentials copies the credential keys that the module uses into secrets of the release,
+// which only the service account of the release can read. The copies are owned by the service account,
+// and are deleted with it when the release is uninstalled.
+func (r *BlueprintReconciler) deliverCredentials(ctx context.Context, blueprint *fapp.Blueprint, releaseName string,
+	keys []string, sources map[string]taxonomy.SecretRef, log *zerolog.Logger) error {
+	if !environment.IsCredentialBrokerEnabled() {
+		return nil
+	}
+	namespace := blueprint.Spec.ModulesNamespace
+	if len(sources) == 0 {
+		return r.revokeCredentials(ctx, namespace, releaseName)
+	}
+	labels := managerUtils.CopyFybrikLabels(blueprint.Labels)
+	labels[managerUtils.CredentialsReleaseLabel] = releaseName
+	serviceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: releaseName, Namespace: namespace, Labels: labels},
+	}
+	if err := r.applyBrokerObject(ctx, serviceAccount); err != nil {
+		return errors.WithMessage(err, "failed to create the service account of release "+releaseName)
+	}
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ServiceAccount", Name: serviceAccount.Name, UID: serviceAccount.UID}
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, OwnerReferences: []metav1.OwnerReference{owner}}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := r.credentialKeys(ctx, sources[name], keys)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("Secret %s/%s has none of the credential keys %v of release %s",
+				sources[name].Namespace, sources[name].Name, keys, releaseName)
+		}
+		secret := &corev1.Secret{ObjectMeta: meta(name), Type: corev1.SecretTypeOpaque, Data: data}
+		if err = r.applyBrokerObject(ctx, secret); err != nil {
+			return errors.WithMessage(err, "failed to copy the credentials of release "+releaseName)
+		}
+	}
+	if err := r.deleteStaleCredentials(ctx, namespace, releaseName, names); err != nil {
+		return err
+	}
+
+	role := &rbacv1.Role{
+		ObjectMeta: meta(releaseName),
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, ResourceNames: names},
+		},
+	}
+	if err := r.applyBrokerObject(ctx, role); err != nil {
+		return errors.WithMessage(err, "failed to create the credentials role of release "+releaseName)
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: meta(releaseName),
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name},
+		Subjects:   []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: serviceAccount.Name, Namespace: namespace}},
+	}
+	if err := r.applyBrokerObject(ctx, roleBinding); err != nil {
+		return errors.WithMessage(err, "failed to create the credentials role binding of release "+releaseName)
+	}
+	log.Debug().Str(logging.ACTION, logging.CREATE).Msgf("Delivered credentials %v to release %s", names, releaseName)
+	return nil
+}
+
+// credentialKeys returns the given keys of the secret, or all its keys if no keys are given
+func (r *BlueprintReconciler) credentialKeys(ctx context.Context, source taxonomy.SecretRef, keys []string) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := r.apiReader().Get(ctx, types.NamespacedName{Namespace: source.Namespace, Name: source.Name}, secret); err != nil {
+		return nil, errors.WithMessage(err, "failed to read the credentials secret "+source.Namespace+"/"+source.Name)
+	}
+	if len(keys) == 0 {
+		return secret.Data, nil
+	}
+	data := map[string][]byte{}
+	for _, key := range keys {
+		if value, found := secret.Data[key]; found {
+			data[key] = value
+		}
+	}
+	return data, nil
+}
+
+// applyBrokerObject creates the object, or updates it if it exists.
+// Existing objects are read from the API server since the objects of the modules namespace are not cached.
+func (r *BlueprintReconciler) applyBrokerObject(ctx context.Context, obj client.Object) error {
+	err := r.Create(ctx, obj)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	existing, _ := obj.DeepCopyObject().(client.Object)
+	if err = r.apiReader().Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return r.Update(ctx, obj)
+}
+
+// deleteStaleCredentials deletes the credential copies of the release that the release does not use anymore
+func (r *BlueprintReconciler) deleteStaleCredentials(ctx context.Context, namespace, releaseName string, names []string) error {
+	secrets := &corev1.SecretList{}
+	if err := r.apiReader().List(ctx, secrets, client.InNamespace(namespace),
+		client.MatchingLabels{managerUtils.CredentialsReleaseLabel: releaseName}); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if utils.HasString(secrets.Items[i].Name, names) {
+			continue
+		}
+		if err := r.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revokeCredentials deletes the service account of the release together with the credential copies and the role
+// that grants access to them
+func (r *BlueprintReconciler) revokeCredentials(ctx context.Context, namespace, releaseName string) error {
+	if err := r.DeleteAllOf(ctx, &corev1.Secret{}, client.InNamespace(namespace),
+		client.MatchingLabels{managerUtils.CredentialsReleaseLabel: releaseName}); err != nil {
+		return err
+	}
+	objectMeta := metav1.ObjectMeta{Name: releaseName, Namespace: namespace}
+	for _, obj := range []client.Object{&rbacv1.RoleBinding{ObjectMeta: objectMeta}, &rbacv1.Role{ObjectMeta: objectMeta},
+		&corev1.ServiceAccount{ObjectMeta: objectMeta}} {
+		if err := r.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// This test checks that the credential broker copies the credential keys of the module into secrets that only
+// the service account of the release can read, and deletes them with the release
+func TestCredentialBroker(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv(environment.VaultEnabledKey, "false")
+	t.Setenv(environment.CredentialBrokerEnabledKey, "true")
+	blueprint, err := readBlueprint("../../testdata/blueprint.yaml")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	namespace := environment.GetDefaultModulesNamespace()
+	blueprint.Spec.ModulesNamespace = namespace
+	instanceName := "notebook-read-module"
+	source := taxonomy.SecretRef{Name: "secret-name", Namespace: "default"}
+	module := blueprint.Spec.Modules[instanceName]
+	module.Arguments.Assets[0].Arguments[0].SecretRef = &source
+	module.CredentialKeys = []string{"access_key", "secret_key"}
+	blueprint.Spec.Modules[instanceName] = module
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: source.Name, Namespace: source.Namespace},
+		Data:       map[string][]byte{"access_key": []byte("id"), "secret_key": []byte("key"), "admin_token": []byte("token")},
+	}
+	s := utils.NewScheme(g)
+	g.Expect(rbacv1.AddToScheme(s)).To(gomega.Succeed())
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(credentials).Build()
+	helmer := newReleasesHelmer()
+	r := &BlueprintReconciler{
+		Client: cl,
+		Name:   "BlueprintTestController",
+		Log:    logging.LogInit(logging.CONTROLLER, "test-blueprint-controller"),
+		Scheme: s,
+		Helmer: helmer,
+	}
+	reconcileGeneration := func(generation int64) {
+		blueprint.Generation = generation
+		_, err = r.reconcile(context.Background(), nil, &r.Log, blueprint)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(blueprint.Status.ObservedState.Error).To(gomega.BeEmpty())
+	}
+	releaseName := "notebook1234-notebook-read-module"
+	copyName := brokeredSecretName(releaseName, source)
+	key := types.NamespacedName{Namespace: namespace, Name: releaseName}
+
+	// the release reads the credential keys of the module from a copy in the modules namespace
+	reconcileGeneration(1)
+	values := helmer.releases[releaseName].Config
+	g.Expect(values["serviceAccount"]).To(gomega.Equal(map[string]interface{}{"create": false, "name": releaseName}))
+	dataStore := values["assets"].([]interface{})[0].(map[string]interface{})["args"].([]interface{})[0].(map[string]interface{})
+	g.Expect(dataStore["secretRef"]).To(gomega.Equal(map[string]interface{}{"name": copyName, "namespace": namespace}))
+	g.Expect(blueprint.Spec.Modules[instanceName].Arguments.Assets[0].Arguments[0].SecretRef).To(gomega.Equal(&source))
+	// modules without credentials in secrets keep their service account
+	g.Expect(helmer.releases["notebook1234-notebook-copy-batch"].Config).ToNot(gomega.HaveKey("serviceAccount"))
+
+	serviceAccount := &corev1.ServiceAccount{}
+	g.Expect(cl.Get(context.Background(), key, serviceAccount)).To(gomega.Succeed())
+	copied := &corev1.Secret{}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: copyName}, copied)).To(gomega.Succeed())
+	g.Expect(copied.Data).To(gomega.Equal(map[string][]byte{"access_key": []byte("id"), "secret_key": []byte("key")}))
+	g.Expect(copied.OwnerReferences).To(gomega.HaveLen(1))
+	g.Expect(copied.OwnerReferences[0].Name).To(gomega.Equal(releaseName))
+	g.Expect(copied.OwnerReferences[0].UID).To(gomega.Equal(serviceAccount.UID))
+	role := &rbacv1.Role{}
+	g.Expect(cl.Get(context.Background(), key, role)).To(gomega.Succeed())
+	g.Expect(role.Rules).To(gomega.HaveLen(1))
+	g.Expect(role.Rules[0].ResourceNames).To(gomega.Equal([]string{copyName}))
+	g.Expect(role.Rules[0].Verbs).To(gomega.Equal([]string{"get"}))
+	roleBinding := &rbacv1.RoleBinding{}
+	g.Expect(cl.Get(context.Background(), key, roleBinding)).To(gomega.Succeed())
+	g.Expect(roleBinding.Subjects).To(gomega.Equal([]rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: releaseName,
+		Namespace: namespace}}))
+
+	// the copies are refreshed when the release is deployed again
+	credentials.Data["secret_key"] = []byte("rotated")
+	g.Expect(cl.Update(context.Background(), credentials)).To(gomega.Succeed())
+	reconcileGeneration(2)
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: copyName}, copied)).To(gomega.Succeed())
+	g.Expect(copied.Data["secret_key"]).To(gomega.Equal([]byte("rotated")))
+
+	// the copies of a deployed release are refreshed on every reconcile, without deploying the release again
+	credentials.Data["secret_key"] = []byte("rotated-again")
+	g.Expect(cl.Update(context.Background(), credentials)).To(gomega.Succeed())
+	reconcileGeneration(2)
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: copyName}, copied)).To(gomega.Succeed())
+	g.Expect(copied.Data["secret_key"]).To(gomega.Equal([]byte("rotated-again")))
+
+	// the copies are deleted when the release no longer uses the credentials
+	module.Arguments.Assets[0].Arguments[0].SecretRef = nil
+	blueprint.Spec.Modules[instanceName] = module
+	reconcileGeneration(3)
+	g.Expect(helmer.releases[releaseName].Config).ToNot(gomega.HaveKey("serviceAccount"))
+	secrets := &corev1.SecretList{}
+	g.Expect(cl.List(context.Background(), secrets, client.InNamespace(namespace))).To(gomega.Succeed())
+	g.Expect(secrets.Items).To(gomega.BeEmpty())
+	g.Expect(cl.Get(context.Background(), key, &corev1.ServiceAccount{})).ToNot(gomega.Succeed())
+
+	// the copies are deleted with the release
+	module.Arguments.Assets[0].Arguments[0].SecretRef = &source
+	blueprint.Spec.Modules[instanceName] = module
+	reconcileGeneration(4)
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: copyName}, copied)).To(gomega.Succeed())
+	g.Expect(r.uninstallRelease(context.Background(), nil, namespace, releaseName)).To(gomega.Succeed())
+	g.Expect(cl.List(context.Background(), secrets, client.InNamespace(namespace))).To(gomega.Succeed())
+	g.Expect(secrets.Items).To(gomega.BeEmpty())
+	g.Expect(cl.Get(context.Background(), key, &rbacv1.Role{})).ToNot(gomega.Succeed())
+	g.Expect(cl.Get(context.Background(), key, &rbacv1.RoleBinding{})).ToNot(gomega.Succeed())
+	g.Expect(cl.Get(context.Background(), key, &corev1.ServiceAccount{})).ToNot(gomega.Succeed())
+}
@@ -0,0 +1,218 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/datapath"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/utils"
+)
+
+const (
+	// leases that are renewed for less than this duration are replaced by new leases
+	minLeaseDuration = time.Minute
+	// time to wait before renewing a lease again if the renewal has failed
+	leaseRetryInterval     = time.Minute
+	leasedSecretHashLength = 20
+)
+
+// vaultPath returns the path of a secret as it is used by the vault client, without the /v1/ prefix
+func vaultPath(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "/"), "v1/")
+}
+
+// isDynamicSecret checks whether the credentials are issued by one of the configured vault dynamic secrets engines
+func isDynamicSecret(credentialsPath string) bool {
+	path := vaultPath(credentialsPath)
+	for _, enginePath := range environment.GetVaultDynamicSecretsPaths() {
+		if strings.HasPrefix(path, enginePath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// leasedSecretPath returns the path of the secret of the application that holds the credentials issued with the lease
+func leasedSecretPath(uuid, leaseID string) string {
+	return environment.GetVaultLeasedSecretsPath() + "/" + uuid + "/" + utils.Hash(leaseID, leasedSecretHashLength)
+}
+
+// renewTime returns the time at which a lease of the given duration is renewed
+func renewTime(duration time.Duration) metav1.Time {
+	return metav1.NewTime(time.Now().Add(duration / 2))
+}
+
+// issueLeases leases the dynamic credentials of the assets for the modules.
+// The credentials are stored in a secret of the application, and the modules are pointed to this secret
+// instead of the dynamic secret, so that the manager controls the lifetime of the credentials.
+func (r *FybrikApplicationReconciler) issueLeases(applicationContext ApplicationContext,
+	requirements []datapath.DataInfo) (map[string]fappv1.CredentialLease, error) {
+	leases := map[string]fappv1.CredentialLease{}
+	if r.Vault == nil {
+		return leases, nil
+	}
+	for i := range requirements {
+		sourcePath := requirements[i].DataDetails.Credentials
+		if !isDynamicSecret(sourcePath) {
+			continue
+		}
+		assetID := requirements[i].Context.DataSetID
+		lease, err := r.leaseCredentials(applicationContext.UUID, sourcePath, "")
+		if err != nil {
+			setErrorCondition(applicationContext, assetID, err.Error())
+			// the leases issued so far are not used
+			if revokeErr := r.revokeLeases(applicationContext, leases); revokeErr != nil {
+				applicationContext.Log.Error().Err(revokeErr).Str(logging.ACTION, logging.DELETE).Msg("Error revoking credential leases")
+			}
+			return nil, err
+		}
+		applicationContext.Log.Debug().Str(logging.DATASETID, assetID).Str(logging.ACTION, logging.CREATE).
+			Msg("Leased dynamic credentials from " + sourcePath)
+		leases[assetID] = *lease
+		requirements[i].DataDetails.Credentials = lease.SecretPath
+	}
+	return leases, nil
+}
+
+// leaseCredentials creates a lease on a dynamic secret and stores the issued credentials in the given secret,
+// or in a new secret of the application if the secret path is empty
+func (r *FybrikApplicationReconciler) leaseCredentials(uuid, sourcePath, secretPath string) (*fappv1.CredentialLease, error) {
+	lease, err := r.Vault.CreateLease(vaultPath(sourcePath))
+	if err != nil {
+		return nil, err
+	}
+	if secretPath == "" {
+		secretPath = leasedSecretPath(uuid, lease.ID)
+	}
+	if err = r.Vault.AddSecret(secretPath, lease.Data); err != nil {
+		if revokeErr := r.Vault.RevokeLease(lease.ID); revokeErr != nil {
+			err = errors.Append(err, revokeErr)
+		}
+		return nil, err
+	}
+	return &fappv1.CredentialLease{
+		LeaseID:    lease.ID,
+		SourcePath: sourcePath,
+		SecretPath: "/v1/" + secretPath,
+		Renewable:  lease.Renewable,
+		RenewTime:  renewTime(lease.Duration),
+	}, nil
+}
+
+// revokeLeases revokes the leases and deletes the secrets holding the leased credentials.
+// The revoked leases are removed from the given map.
+func (r *FybrikApplicationReconciler) revokeLeases(applicationContext ApplicationContext,
+	leases map[string]fappv1.CredentialLease) error {
+	if len(leases) == 0 {
+		return nil
+	}
+	if r.Vault == nil {
+		// the manager is no longer configured to use dynamic secrets, the leases expire on their own
+		applicationContext.Log.Warn().Msg("Credential leases can not be revoked since vault dynamic secrets are not configured")
+		for assetID := range leases {
+			delete(leases, assetID)
+		}
+		return nil
+	}
+	var errMsgs []string
+	for assetID, lease := range leases {
+		if err := r.Vault.RevokeLease(lease.LeaseID); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+			continue
+		}
+		if err := r.Vault.DeleteSecret(vaultPath(lease.SecretPath)); err != nil {
+			errMsgs = append(errMsgs, err.Error())
+			continue
+		}
+		applicationContext.Log.Debug().Str(logging.DATASETID, assetID).Str(logging.ACTION, logging.DELETE).
+			Msg("Revoked the credential lease " + lease.LeaseID)
+		delete(leases, assetID)
+	}
+	if len(errMsgs) != 0 {
+		return errors.New(strings.Join(errMsgs, Separator))
+	}
+	return nil
+}
+
+// settleLeases replaces the leases of the application by the new leases if the data path that uses them has been
+// deployed. Otherwise the new leases are revoked, and the modules of the previous data path keep their credentials.
+func (r *FybrikApplicationReconciler) settleLeases(applicationContext ApplicationContext,
+	leases map[string]fappv1.CredentialLease, deployed bool) {
+	if deployed {
+		r.replaceLeases(applicationContext, leases)
+		return
+	}
+	if err := r.revokeLeases(applicationContext, leases); err != nil {
+		applicationContext.Log.Error().Err(err).Str(logging.ACTION, logging.DELETE).Msg("Error revoking credential leases")
+	}
+}
+
+// replaceLeases makes the given leases the leases of the application, and revokes the previous ones
+func (r *FybrikApplicationReconciler) replaceLeases(applicationContext ApplicationContext,
+	leases map[string]fappv1.CredentialLease) {
+	previous := applicationContext.Application.Status.CredentialLeases
+	if err := r.revokeLeases(applicationContext, previous); err != nil {
+		applicationContext.Log.Error().Err(err).Str(logging.ACTION, logging.DELETE).Msg("Error revoking credential leases")
+	}
+	// leases that could not be revoked are kept to be revoked with the application
+	for assetID, lease := range previous {
+		if _, found := leases[assetID]; !found {
+			leases[assetID] = lease
+		}
+	}
+	if len(leases) == 0 {
+		leases = nil
+	}
+	applicationContext.Application.Status.CredentialLeases = leases
+}
+
+// renewLeases renews the leases of the application that are due for renewal.
+// A lease that can not be renewed is replaced by a new lease on the same dynamic secret, and the previous lease
+// expires on its own, so that the modules can switch to the new credentials.
+// It returns the time until the next lease is due, or zero if the application has no leases.
+func (r *FybrikApplicationReconciler) renewLeases(applicationContext ApplicationContext) time.Duration {
+	leases := applicationContext.Application.Status.CredentialLeases
+	if len(leases) == 0 || r.Vault == nil {
+		return 0
+	}
+	var requeueAfter time.Duration
+	for assetID, lease := range leases {
+		if time.Until(lease.RenewTime.Time) <= 0 {
+			renewed, err := r.renewLease(applicationContext.UUID, &lease)
+			if err != nil {
+				applicationContext.Log.Error().Err(err).Str(logging.DATASETID, assetID).Msg("Error renewing the credential lease " + lease.LeaseID)
+				lease.RenewTime = metav1.NewTime(time.Now().Add(leaseRetryInterval))
+			} else {
+				lease = *renewed
+			}
+			leases[assetID] = lease
+		}
+		if until := time.Until(lease.RenewTime.Time); requeueAfter == 0 || until < requeueAfter {
+			requeueAfter = until
+		}
+	}
+	return requeueAfter
+}
+
+// renewLease extends the lease if possible, and otherwise replaces it by a new lease on the same dynamic secret
+func (r *FybrikApplicationReconciler) renewLease(uuid string, lease *fappv1.CredentialLease) (*fappv1.CredentialLease, error) {
+	if lease.Renewable {
+		renewed, err := r.Vault.RenewLease(lease.LeaseID, 0)
+		if err == nil && renewed.Duration >= minLeaseDuration {
+			result := *lease
+			result.RenewTime = renewTime(renewed.Duration)
+			return &result, nil
+		}
+	}
+	// the modules keep reading the credentials from the same secret
+	return r.leaseCredentials(uuid, lease.SourcePath, vaultPath(lease.SecretPath))
+}
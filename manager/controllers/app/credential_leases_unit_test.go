@@ -0,0 +1,166 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/mockup"
+	"fybrik.io/fybrik/manager/controllers/utils"
+	dcclient "fybrik.io/fybrik/pkg/connectors/datacatalog/clients"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/vault"
+)
+
+// dynamicCredentialsCatalog returns assets whose credentials are issued by a dynamic secrets engine,
+// and fails to return the failing asset
+type dynamicCredentialsCatalog struct {
+	dcclient.DataCatalog
+	credentials string
+	failing     taxonomy.AssetID
+}
+
+func (c *dynamicCredentialsCatalog) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
+	if in.AssetID == c.failing {
+		return nil, errors.New("the catalog failed to return the asset")
+	}
+	response, err := c.DataCatalog.GetAssetInfo(ctx, in, creds)
+	if err != nil {
+		return nil, err
+	}
+	response = response.DeepCopy()
+	response.Credentials = c.credentials
+	return response, nil
+}
+
+// This test checks that the dynamic credentials of the assets are leased for the modules, renewed, and revoked
+// when the data paths are computed again or the application is deleted
+func TestCredentialLeases(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv(environment.VaultEnabledKey, "true")
+	t.Setenv(environment.VaultDynamicSecretsPathsKey, "database/creds")
+
+	namespaced := types.NamespacedName{Name: "read-test", Namespace: "default"}
+	application := &fappv1.FybrikApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Spec.Data[0] = fappv1.DataContext{
+		DataSetID:    "s3/allow-dataset",
+		Requirements: fappv1.DataRequirements{Interface: &taxonomy.Interface{Protocol: mockup.ArrowFlight}},
+	}
+	application.SetGeneration(1)
+	application.SetUID("31")
+	s := utils.NewScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(application).Build()
+	readModule := &fappv1.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-read-parquet.yaml", readModule)).NotTo(gomega.HaveOccurred())
+	readModule.Namespace = environment.GetAdminCRsNamespace()
+	g.Expect(cl.Create(context.Background(), readModule)).To(gomega.Succeed())
+
+	credentials := map[string]interface{}{"username": "v-module-x1", "password": "generated"}
+	vaultConnection := vault.NewDummyConnection()
+	g.Expect(vaultConnection.AddSecret("database/creds/readonly", credentials)).To(gomega.Succeed())
+	r := createTestFybrikApplicationController(cl, s)
+	g.Expect(r).NotTo(gomega.BeNil())
+	r.Vault = vaultConnection
+	catalog := &dynamicCredentialsCatalog{DataCatalog: r.DataCatalog, credentials: "/v1/database/creds/readonly"}
+	r.DataCatalog = catalog
+
+	reconcileApplication := func() time.Duration {
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: namespaced})
+		g.Expect(err).NotTo(gomega.HaveOccurred())
+		g.Expect(cl.Get(context.Background(), namespaced, application)).To(gomega.Succeed())
+		g.Expect(getErrorMessages(application)).To(gomega.BeEmpty())
+		return result.RequeueAfter
+	}
+	assetID := application.Spec.Data[0].DataSetID
+
+	// the credentials are leased and passed to the modules in a secret of the application
+	requeueAfter := reconcileApplication()
+	g.Expect(requeueAfter).To(gomega.BeNumerically("~", vault.DummyLeaseDuration/2, time.Minute))
+	g.Expect(application.Status.CredentialLeases).To(gomega.HaveKey(assetID))
+	lease := application.Status.CredentialLeases[assetID]
+	g.Expect(vaultConnection.Leases()).To(gomega.ConsistOf(lease.LeaseID))
+	g.Expect(lease.SourcePath).To(gomega.Equal("/v1/database/creds/readonly"))
+	g.Expect(lease.SecretPath).To(gomega.HavePrefix("/v1/fybrik-leases/"))
+	g.Expect(application.Finalizers).NotTo(gomega.BeEmpty())
+	secret, err := vaultConnection.GetSecret(vaultPath(lease.SecretPath))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	expected, _ := json.Marshal(credentials)
+	g.Expect(secret).To(gomega.Equal(string(expected)))
+	plotter := &fappv1.Plotter{}
+	g.Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: application.Status.Generated.Namespace,
+		Name: application.Status.Generated.Name}, plotter)).To(gomega.Succeed())
+	g.Expect(plotter.Spec.Assets[assetID].DataStore.Vault[string(taxonomy.ReadFlow)].SecretPath).To(gomega.Equal(lease.SecretPath))
+
+	// leases that are due are renewed
+	application.Status.CredentialLeases[assetID] = fappv1.CredentialLease{LeaseID: lease.LeaseID, SourcePath: lease.SourcePath,
+		SecretPath: lease.SecretPath, Renewable: true, RenewTime: metav1.NewTime(time.Now().Add(-time.Second))}
+	g.Expect(cl.Status().Update(context.Background(), application)).To(gomega.Succeed())
+	g.Expect(reconcileApplication()).To(gomega.BeNumerically(">", time.Minute))
+	renewed := application.Status.CredentialLeases[assetID]
+	g.Expect(renewed.LeaseID).To(gomega.Equal(lease.LeaseID))
+	g.Expect(renewed.RenewTime.After(time.Now())).To(gomega.BeTrue())
+
+	// leases that can not be renewed are replaced, and the previous lease expires on its own
+	renewed.Renewable = false
+	renewed.RenewTime = metav1.NewTime(time.Now().Add(-time.Second))
+	application.Status.CredentialLeases[assetID] = renewed
+	g.Expect(cl.Status().Update(context.Background(), application)).To(gomega.Succeed())
+	reconcileApplication()
+	rotated := application.Status.CredentialLeases[assetID]
+	g.Expect(rotated.LeaseID).NotTo(gomega.Equal(lease.LeaseID))
+	g.Expect(rotated.SecretPath).To(gomega.Equal(lease.SecretPath))
+	g.Expect(vaultConnection.Leases()).To(gomega.ConsistOf(lease.LeaseID, rotated.LeaseID))
+	g.Expect(vaultConnection.RevokeLease(lease.LeaseID)).To(gomega.Succeed())
+
+	// the leases are revoked when the data paths are computed again
+	application.SetGeneration(2)
+	g.Expect(cl.Update(context.Background(), application)).To(gomega.Succeed())
+	reconcileApplication()
+	replaced := application.Status.CredentialLeases[assetID]
+	g.Expect(replaced.LeaseID).NotTo(gomega.Equal(rotated.LeaseID))
+	g.Expect(vaultConnection.Leases()).To(gomega.ConsistOf(replaced.LeaseID))
+	g.Expect(replaced.SecretPath).NotTo(gomega.Equal(rotated.SecretPath))
+	_, err = vaultConnection.GetSecret(vaultPath(rotated.SecretPath))
+	g.Expect(err).To(gomega.HaveOccurred())
+
+	// the leases are kept if the new data paths are not deployed, since the modules of the previous data paths use them
+	catalog.failing = "s3/failing-dataset"
+	failing := application.Spec.Data[0].DeepCopy()
+	failing.DataSetID = string(catalog.failing)
+	application.Spec.Data = append(application.Spec.Data, *failing)
+	application.SetGeneration(3)
+	g.Expect(cl.Update(context.Background(), application)).To(gomega.Succeed())
+	_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: namespaced})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(cl.Get(context.Background(), namespaced, application)).To(gomega.Succeed())
+	g.Expect(getErrorMessages(application)).NotTo(gomega.BeEmpty())
+	g.Expect(application.Status.CredentialLeases[assetID].LeaseID).To(gomega.Equal(replaced.LeaseID))
+	g.Expect(vaultConnection.Leases()).To(gomega.ConsistOf(replaced.LeaseID))
+	_, err = vaultConnection.GetSecret(vaultPath(replaced.SecretPath))
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// the leases are revoked when the application is deleted
+	appContext := ApplicationContext{Application: application, Log: &r.Log}
+	application.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	g.Expect(r.removeFinalizers(context.Background(), appContext)).To(gomega.Succeed())
+	g.Expect(application.Status.CredentialLeases).To(gomega.BeEmpty())
+	g.Expect(vaultConnection.Leases()).To(gomega.BeEmpty())
+	_, err = vaultConnection.GetSecret(vaultPath(replaced.SecretPath))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -4,11 +4,16 @@
 package app
 
 import (
+	"context"
+
 	"github.com/rs/zerolog/log"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/vault"
 )
 
@@ -20,7 +25,7 @@ import (
 // Returns:
 // - an error if happened
 // - the new asset identifier
-func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID string,
+func (r *FybrikApplicationReconciler) RegisterAsset(ctx context.Context, assetID string, catalogID string,
 	info *fapp.DatasetDetails, input *fapp.FybrikApplication) (string, error) {
 	r.Log.Trace().Msg("RegisterAsset")
 	details := datacatalog.ResourceDetails{}
@@ -50,6 +55,8 @@ func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID st
 		Credentials:          creds,
 		DestinationCatalogID: catalogID,
 		DestinationAssetID:   assetID,
+		// the asset is registered again if the new asset identifier could not be stored in the application status
+		IdempotencyKey: AssetIdempotencyKey(utils.GetFybrikApplicationUUID(input), assetID),
 	}
 	// credentialPath is constructed even if vault is not used for credential management
 	// in order to enable the connector to get the credentials directly from the secret
@@ -58,7 +65,7 @@ func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID st
 
 	var err error
 	var response *datacatalog.CreateAssetResponse
-	if response, err = r.DataCatalog.CreateAsset(&request, credentialPath); err != nil {
+	if response, err = r.DataCatalog.CreateAsset(ctx, &request, credentialPath); err != nil {
 		log.Error().Err(err).Msg("failed to receive the catalog connector response")
 		return "", err
 	}
@@ -71,3 +78,33 @@ func (r *FybrikApplicationReconciler) RegisterAsset(assetID string, catalogID st
 
 	return response.AssetID, nil
 }
+
+// AssetIdempotencyKey returns the key that identifies the registration of the asset written for the given dataset
+// of an application, so that the catalog creates a single asset for it
+func AssetIdempotencyKey(uuid, assetID string) string {
+	return uuid + "/" + assetID
+}
+
+// DeleteAsset removes an asset from the catalog once it has been deleted by a delete flow
+// Input arguments:
+// - assetID: DataSetID as it appears in fybrik-application
+// Returns:
+// - an error if happened
+func (r *FybrikApplicationReconciler) DeleteAsset(ctx context.Context, assetID string, input *fapp.FybrikApplication) error {
+	r.Log.Trace().Msg("DeleteAsset")
+	request := datacatalog.DeleteAssetRequest{
+		AssetID: taxonomy.AssetID(assetID),
+	}
+	// credentialPath is constructed even if vault is not used for credential management
+	// in order to enable the connector to get the credentials directly from the secret
+	// using the secret information extracted from the credentialPath string.
+	credentialPath := vault.PathForReadingKubeSecret(input.Namespace, input.Spec.SecretRef)
+
+	response, err := r.DataCatalog.DeleteAsset(ctx, &request, credentialPath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to receive the catalog connector response")
+		return err
+	}
+	log.Debug().Str(logging.DATASETID, assetID).Msgf("Catalog connector delete status: %s", response.Status)
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/helm"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/tracing"
+)
+
+// ReleaseState is the deployment state of a module release
+type ReleaseState string
+
+const (
+	// DeployedRelease indicates that the release resources have been applied
+	DeployedRelease ReleaseState = "deployed"
+	// FailedRelease indicates that the last deployment of the release has failed
+	FailedRelease ReleaseState = "failed"
+	// PendingRelease indicates that the deployment of the release is in progress
+	PendingRelease ReleaseState = "pending"
+)
+
+// ReleaseStatus holds the deployment state of a module release and the current state of its resources
+type ReleaseStatus struct {
+	State     ReleaseState
+	Resources []*unstructured.Unstructured
+}
+
+// ModuleDeployer deploys module releases from a specific type of package
+type ModuleDeployer interface {
+	// Deploy installs the release, or upgrades it if it has been installed before
+	Deploy(ctx context.Context, chartSpec *fapp.ChartSpec, namespace, releaseName string, values map[string]interface{},
+		log *zerolog.Logger) error
+	// Status returns the status of the release, or nil if the release does not exist
+	Status(ctx context.Context, namespace, releaseName string) (*ReleaseStatus, error)
+	// Uninstall deletes the release and its resources
+	Uninstall(ctx context.Context, namespace, releaseName string) error
+}
+
+// moduleDeployer returns the deployer of the given deployment type
+func (r *BlueprintReconciler) moduleDeployer(cfg *action.Configuration, deploymentType fapp.DeploymentType) (ModuleDeployer, error) {
+	switch deploymentType {
+	case fapp.HelmDeployment, "":
+		return &helmDeployer{helmer: r.Helmer, cfg: cfg}, nil
+	case fapp.ManifestsDeployment:
+		return newObjectsDeployer(r.Client, renderManifests), nil
+	case fapp.KustomizeDeployment:
+		return newObjectsDeployer(r.Client, renderKustomization), nil
+	default:
+		return nil, errors.Errorf("unsupported module deployment type %s", deploymentType)
+	}
+}
+
+// uninstallRelease uninstalls a release that is not a part of the blueprint anymore.
+// Releases of manifests and kustomize deployments are identified by their inventory, other releases are helm releases.
+// The credentials delivered to the release by the credential broker are deleted with it.
+func (r *BlueprintReconciler) uninstallRelease(ctx context.Context, cfg *action.Configuration, namespace, releaseName string) error {
+	if environment.IsCredentialBrokerEnabled() {
+		if err := r.revokeCredentials(ctx, namespace, releaseName); err != nil {
+			return errors.WithMessage(err, "failed to delete the credentials of release "+releaseName)
+		}
+	}
+	objects := newObjectsDeployer(r.Client, nil)
+	status, err := objects.Status(ctx, namespace, releaseName)
+	if err != nil {
+		return err
+	}
+	if status != nil {
+		return objects.Uninstall(ctx, namespace, releaseName)
+	}
+	helmReleases := &helmDeployer{helmer: r.Helmer, cfg: cfg}
+	return helmReleases.Uninstall(ctx, namespace, releaseName)
+}
+
+// helmDeployer deploys modules packaged as helm charts
+type helmDeployer struct {
+	helmer helm.Interface
+	cfg    *action.Configuration
+}
+
+func (d *helmDeployer) Deploy(ctx context.Context, chartSpec *fapp.ChartSpec, namespace, releaseName string,
+	values map[string]interface{}, log *zerolog.Logger) error {
+	tmpDir, err := os.MkdirTemp(environment.GetDataDir(), "fybrik-helm-")
+	if err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed to create temporary directory for chart pull")
+	}
+	defer func(log *zerolog.Logger) {
+		if err = os.RemoveAll(tmpDir); err != nil {
+			log.Error().Msgf("Error while calling RemoveAll on directory %s created for pulling helm chart", tmpDir)
+		}
+	}(log)
+
+	err = d.helmer.Pull(d.cfg, chartSpec.Name, tmpDir)
+	if err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed chart pull")
+	}
+	helmChart, err := d.helmer.Load(chartSpec.Name, tmpDir)
+	if err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed chart load")
+	}
+	inst, err := d.helmer.IsInstalled(d.cfg, releaseName)
+	// TODO should we return err if it is not nil?
+	var rel *release.Release
+	if inst && err == nil {
+		rel, err = d.release(ctx, true, helmChart, namespace, releaseName, values)
+		if err != nil {
+			return errors.WithMessage(err, chartSpec.Name+": failed upgrade")
+		}
+	} else {
+		rel, err = d.release(ctx, false, helmChart, namespace, releaseName, values)
+		if err != nil {
+			return errors.WithMessage(err, chartSpec.Name+": failed install")
+		}
+	}
+	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("--- Release Status ---\n\n" + string(rel.Info.Status) + "\n\n")
+	return nil
+}
+
+// release upgrades the release, or installs it, in a traced span
+func (d *helmDeployer) release(ctx context.Context, upgrade bool, helmChart *chart.Chart, namespace, releaseName string,
+	values map[string]interface{}) (rel *release.Release, err error) {
+	operation := "helmInstall"
+	if upgrade {
+		operation = "helmUpgrade"
+	}
+	ctx, span := tracing.Tracer(TracerName).Start(ctx, operation, trace.WithAttributes(releaseAttributes(namespace, releaseName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	if upgrade {
+		return d.helmer.Upgrade(ctx, d.cfg, helmChart, namespace, releaseName, values)
+	}
+	return d.helmer.Install(ctx, d.cfg, helmChart, namespace, releaseName, values)
+}
+
+func (d *helmDeployer) Status(ctx context.Context, namespace, releaseName string) (*ReleaseStatus, error) {
+	rel, err := d.helmer.Status(d.cfg, releaseName)
+	if err != nil || rel == nil {
+		return nil, err
+	}
+	status := &ReleaseStatus{}
+	switch rel.Info.Status {
+	case release.StatusDeployed:
+		status.State = DeployedRelease
+	case release.StatusFailed:
+		status.State = FailedRelease
+	default:
+		status.State = PendingRelease
+		return status, nil
+	}
+	// get all resources for the given helm release in their current state
+	for versionKind := range rel.Info.Resources {
+		for _, obj := range rel.Info.Resources[versionKind] {
+			unstr, convErr := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+			if convErr != nil {
+				return nil, errors.WithMessage(convErr, "error getting resources")
+			}
+			status.Resources = append(status.Resources, &unstructured.Unstructured{Object: unstr})
+		}
+	}
+	return status, nil
+}
+
+func (d *helmDeployer) Uninstall(ctx context.Context, namespace, releaseName string) (err error) {
+	_, span := tracing.Tracer(TracerName).Start(ctx, "helmUninstall", trace.WithAttributes(releaseAttributes(namespace, releaseName)...))
+	defer func() { tracing.EndSpan(span, err) }()
+	_, err = d.helmer.Uninstall(d.cfg, releaseName)
+	return err
+}
+
+// releaseAttributes returns the span attributes that identify a module release
+func releaseAttributes(namespace, releaseName string) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("release.name", releaseName), attribute.String("release.namespace", namespace)}
+}
@@ -0,0 +1,430 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/sprig/v3"
+	"github.com/rs/zerolog"
+	"helm.sh/helm/v3/pkg/cli"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"oras.land/oras-go/pkg/content"
+	"oras.land/oras-go/pkg/oras"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	// ConfigMapSourcePrefix is the prefix of module package names that refer to a ConfigMap
+	ConfigMapSourcePrefix = "configmap://"
+	// OCISourcePrefix is the optional prefix of module package names that refer to an OCI artifact
+	OCISourcePrefix = "oci://"
+	// KustomizeValuesFile is the file the module values are written to before a kustomization is built
+	KustomizeValuesFile = "fybrik-values.yaml"
+
+	inventoryPrefix       = "fybrik-release-"
+	inventoryStateKey     = "state"
+	inventoryResourcesKey = "resources"
+	yamlBufferSize        = 4096
+)
+
+// renderFunc renders the objects of a release from the package files in the given directory
+type renderFunc func(dir, namespace, releaseName string, values map[string]interface{}) ([]*unstructured.Unstructured, error)
+
+// objectReference identifies an object that belongs to a release
+type objectReference struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+// objectsDeployer deploys modules packaged as Kubernetes manifests or kustomizations.
+// The objects of a release are applied directly, and are recorded in an inventory ConfigMap
+// in the release namespace that is used to compute the release status and to delete the release.
+type objectsDeployer struct {
+	client client.Client
+	render renderFunc
+}
+
+func newObjectsDeployer(cl client.Client, render renderFunc) *objectsDeployer {
+	return &objectsDeployer{client: cl, render: render}
+}
+
+func (d *objectsDeployer) Deploy(ctx context.Context, chartSpec *fapp.ChartSpec, namespace, releaseName string,
+	values map[string]interface{}, log *zerolog.Logger) error {
+	tmpDir, err := os.MkdirTemp(environment.GetDataDir(), "fybrik-manifests-")
+	if err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed to create temporary directory for package pull")
+	}
+	defer func(log *zerolog.Logger) {
+		if err = os.RemoveAll(tmpDir); err != nil {
+			log.Error().Msgf("Error while calling RemoveAll on directory %s created for pulling module package", tmpDir)
+		}
+	}(log)
+
+	if err = d.fetch(ctx, chartSpec.Name, tmpDir); err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed package pull")
+	}
+	objects, err := d.render(tmpDir, namespace, releaseName, values)
+	if err != nil {
+		return errors.WithMessage(err, chartSpec.Name+": failed to render the package")
+	}
+	previous, _, err := d.readInventory(ctx, namespace, releaseName)
+	if err != nil {
+		return err
+	}
+	applied := []objectReference{}
+	for _, obj := range objects {
+		if err = d.setNamespace(obj, namespace); err == nil {
+			err = d.apply(ctx, obj)
+		}
+		if err != nil {
+			// keep track of all objects that might exist, so that they are deleted later
+			_ = d.writeInventory(ctx, namespace, releaseName, FailedRelease, mergeReferences(previous, applied))
+			return errors.WithMessagef(err, "%s: failed to apply %s %s", chartSpec.Name, obj.GetKind(), obj.GetName())
+		}
+		applied = append(applied, referenceOf(obj))
+	}
+	// delete the objects that are not a part of the release anymore
+	for _, ref := range previous {
+		if !containsReference(applied, ref) {
+			if err = d.delete(ctx, ref); err != nil {
+				return err
+			}
+		}
+	}
+	log.Trace().Str(logging.ACTION, logging.CREATE).Msgf("%d objects of release %s were applied", len(applied), releaseName)
+	return d.writeInventory(ctx, namespace, releaseName, DeployedRelease, applied)
+}
+
+func (d *objectsDeployer) Status(ctx context.Context, namespace, releaseName string) (*ReleaseStatus, error) {
+	refs, state, err := d.readInventory(ctx, namespace, releaseName)
+	if err != nil || state == "" {
+		return nil, err
+	}
+	status := &ReleaseStatus{State: state}
+	if state != DeployedRelease {
+		return status, nil
+	}
+	// get all resources of the release in their current state
+	for _, ref := range refs {
+		obj := objectFromReference(ref)
+		err = d.client.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		if apierrors.IsNotFound(err) {
+			// a resource has been deleted, the release should be deployed again
+			return &ReleaseStatus{State: FailedRelease}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		status.Resources = append(status.Resources, obj)
+	}
+	return status, nil
+}
+
+func (d *objectsDeployer) Uninstall(ctx context.Context, namespace, releaseName string) error {
+	refs, _, err := d.readInventory(ctx, namespace, releaseName)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if err = d.delete(ctx, ref); err != nil {
+			return err
+		}
+	}
+	return client.IgnoreNotFound(d.client.Delete(ctx, newConfigMap(namespace, inventoryPrefix+releaseName)))
+}
+
+// fetch copies the package files to the given directory
+func (d *objectsDeployer) fetch(ctx context.Context, source, dir string) error {
+	if strings.HasPrefix(source, ConfigMapSourcePrefix) {
+		namespace, name := splitNamespacedName(strings.TrimPrefix(source, ConfigMapSourcePrefix), environment.GetAdminCRsNamespace())
+		cm := newConfigMap(namespace, name)
+		if err := d.client.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil {
+			return err
+		}
+		files, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+		for file, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, filepath.Base(file)), []byte(data), 0o600); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	// the artifact is pulled with the registry credentials used for helm charts
+	registry, err := content.NewRegistry(content.RegistryOptions{Configs: []string{cli.New().RegistryConfig}})
+	if err != nil {
+		return err
+	}
+	store := content.NewFile(dir)
+	defer store.Close()
+	_, err = oras.Copy(ctx, registry, strings.TrimPrefix(source, OCISourcePrefix), store, "")
+	return err
+}
+
+// setNamespace places a namespaced object in the release namespace unless a namespace is set,
+// and removes the namespace of a cluster-scoped object. The scope of the object kind is resolved with
+// the RESTMapper when the object is applied, so that objects of kinds defined by the package are supported.
+func (d *objectsDeployer) setNamespace(obj *unstructured.Unstructured, namespace string) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := d.client.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+	} else if obj.GetNamespace() == "" {
+		obj.SetNamespace(namespace)
+	}
+	return nil
+}
+
+// apply creates the object, or updates it if it exists
+func (d *objectsDeployer) apply(ctx context.Context, obj *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(obj.GroupVersionKind())
+	err := d.client.Get(ctx, client.ObjectKeyFromObject(obj), existing)
+	if apierrors.IsNotFound(err) {
+		return d.client.Create(ctx, obj)
+	}
+	if err != nil {
+		return err
+	}
+	obj.SetResourceVersion(existing.GetResourceVersion())
+	return d.client.Update(ctx, obj)
+}
+
+func (d *objectsDeployer) delete(ctx context.Context, ref objectReference) error {
+	return client.IgnoreNotFound(d.client.Delete(ctx, objectFromReference(ref), client.PropagationPolicy("Background")))
+}
+
+// readInventory returns the objects and the state of a release, or an empty state if the release does not exist
+func (d *objectsDeployer) readInventory(ctx context.Context, namespace, releaseName string) (
+	[]objectReference, ReleaseState, error) {
+	inventory := newConfigMap(namespace, inventoryPrefix+releaseName)
+	err := d.client.Get(ctx, client.ObjectKeyFromObject(inventory), inventory)
+	if apierrors.IsNotFound(err) {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	data, _, _ := unstructured.NestedStringMap(inventory.Object, "data")
+	refs := []objectReference{}
+	if err = json.Unmarshal([]byte(data[inventoryResourcesKey]), &refs); err != nil {
+		return nil, "", errors.WithMessage(err, "invalid inventory of release "+releaseName)
+	}
+	return refs, ReleaseState(data[inventoryStateKey]), nil
+}
+
+func (d *objectsDeployer) writeInventory(ctx context.Context, namespace, releaseName string, state ReleaseState,
+	refs []objectReference) error {
+	data, err := json.Marshal(refs)
+	if err != nil {
+		return err
+	}
+	inventory := newConfigMap(namespace, inventoryPrefix+releaseName)
+	inventory.SetLabels(map[string]string{managerUtils.KubernetesInstance: releaseName})
+	inventory.Object["data"] = map[string]interface{}{inventoryStateKey: string(state), inventoryResourcesKey: string(data)}
+	return d.apply(ctx, inventory)
+}
+
+// renderManifests renders the manifest templates in the given directory.
+// The templates use the go template syntax with the sprig functions, as helm templates do,
+// and can refer to .Values, .Release.Name and .Release.Namespace.
+func renderManifests(dir, namespace, releaseName string, values map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	files, err := packageFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	data := map[string]interface{}{
+		"Values":  values,
+		"Release": map[string]interface{}{"Name": releaseName, "Namespace": namespace},
+	}
+	var objects []*unstructured.Unstructured
+	for _, file := range files {
+		if ext := filepath.Ext(file); ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		rendered, renderErr := renderManifest(file, data)
+		if renderErr != nil {
+			return nil, errors.WithMessage(renderErr, "invalid manifest "+filepath.Base(file))
+		}
+		objects = append(objects, rendered...)
+	}
+	return setReleaseLabels(objects, releaseName), nil
+}
+
+func renderManifest(file string, data map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	text, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(filepath.Base(file)).Funcs(sprig.TxtFuncMap()).Option("missingkey=zero").Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err = tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	return decodeObjects(&out)
+}
+
+// renderKustomization builds the kustomization in the given directory.
+// The module values are written to fybrik-values.yaml, so that the kustomization can use them,
+// for example in a configMapGenerator.
+func renderKustomization(dir, namespace, releaseName string, values map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	root, err := kustomizationRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	valuesYAML, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(filepath.Join(root, KustomizeValuesFile), valuesYAML, 0o600); err != nil {
+		return nil, err
+	}
+	resMap, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(filesys.MakeFsOnDisk(), root)
+	if err != nil {
+		return nil, err
+	}
+	objects := []*unstructured.Unstructured{}
+	for _, res := range resMap.Resources() {
+		obj, mapErr := res.Map()
+		if mapErr != nil {
+			return nil, mapErr
+		}
+		objects = append(objects, &unstructured.Unstructured{Object: obj})
+	}
+	return setReleaseLabels(objects, releaseName), nil
+}
+
+// kustomizationRoot returns the directory of the top level kustomization file in the package
+func kustomizationRoot(dir string) (string, error) {
+	files, err := packageFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	root := ""
+	for _, file := range files {
+		switch filepath.Base(file) {
+		case "kustomization.yaml", "kustomization.yml", "Kustomization":
+			if root == "" || len(filepath.Dir(file)) < len(root) {
+				root = filepath.Dir(file)
+			}
+		}
+	}
+	if root == "" {
+		return "", errors.New("no kustomization file found in the package")
+	}
+	return root, nil
+}
+
+// packageFiles returns the files of the package sorted by their path
+func packageFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func decodeObjects(reader io.Reader) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, yamlBufferSize)
+	for {
+		obj := map[string]interface{}{}
+		err := decoder.Decode(&obj)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objects = append(objects, &unstructured.Unstructured{Object: obj})
+	}
+}
+
+// setReleaseLabels labels the objects with the release name
+func setReleaseLabels(objects []*unstructured.Unstructured, releaseName string) []*unstructured.Unstructured {
+	for _, obj := range objects {
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[managerUtils.KubernetesInstance] = releaseName
+		obj.SetLabels(labels)
+	}
+	return objects
+}
+
+func referenceOf(obj *unstructured.Unstructured) objectReference {
+	return objectReference{APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+func objectFromReference(ref objectReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+	obj.SetNamespace(ref.Namespace)
+	obj.SetName(ref.Name)
+	return obj
+}
+
+func containsReference(refs []objectReference, ref objectReference) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeReferences(refs, added []objectReference) []objectReference {
+	merged := append([]objectReference{}, refs...)
+	for _, ref := range added {
+		if !containsReference(merged, ref) {
+			merged = append(merged, ref)
+		}
+	}
+	return merged
+}
+
+// newConfigMap returns an unstructured ConfigMap, that is read directly from the cluster rather than from the cache
+func newConfigMap(namespace, name string) *unstructured.Unstructured {
+	return managerUtils.CreateUnstructured("", "v1", "ConfigMap", name, namespace)
+}
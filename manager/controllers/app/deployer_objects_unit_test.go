@@ -0,0 +1,213 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	testConfigManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  uuid: {{ .Values.uuid | quote }}
+`
+	testServiceAccountManifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}
+`
+	testManifest            = testConfigManifest + "---\n" + testServiceAccountManifest
+	testClusterRoleManifest = `apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ .Release.Name }}-reader
+  namespace: {{ .Release.Namespace }}
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["get"]
+`
+)
+
+// newObjectsScheme returns the scheme of the objects deployer tests, and a RESTMapper of its kinds
+func newObjectsScheme(g *gomega.WithT) (*runtime.Scheme, apimeta.RESTMapper) {
+	s := managerUtils.NewScheme(g)
+	g.Expect(rbacv1.AddToScheme(s)).To(gomega.Succeed())
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apimeta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), apimeta.RESTScopeNamespace)
+	mapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), apimeta.RESTScopeRoot)
+	return s, mapper
+}
+
+func writeFiles(g *gomega.WithT, dir string, files map[string]string) {
+	for name, data := range files {
+		g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600)).To(gomega.Succeed())
+	}
+}
+
+func TestRenderManifests(t *testing.T) {
+	g := gomega.NewWithT(t)
+	dir := t.TempDir()
+	writeFiles(g, dir, map[string]string{"manifests.yaml": testManifest, "README.md": "not a manifest"})
+
+	objects, err := renderManifests(dir, modulesNamespace, "my-release", map[string]interface{}{"uuid": "123"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(objects).To(gomega.HaveLen(2))
+	g.Expect(objects[0].GetName()).To(gomega.Equal("my-release-config"))
+	g.Expect(objects[0].Object["data"]).To(gomega.Equal(map[string]interface{}{"uuid": "123"}))
+	for _, obj := range objects {
+		g.Expect(obj.GetLabels()).To(gomega.HaveKeyWithValue(managerUtils.KubernetesInstance, "my-release"))
+	}
+
+	writeFiles(g, dir, map[string]string{"invalid.yaml": "{{ .Values"})
+	_, err = renderManifests(dir, modulesNamespace, "my-release", nil)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestRenderKustomization(t *testing.T) {
+	g := gomega.NewWithT(t)
+	dir := t.TempDir()
+	writeFiles(g, dir, map[string]string{
+		"kustomization.yaml": `resources:
+- sa.yaml
+configMapGenerator:
+- name: module-values
+  files:
+  - fybrik-values.yaml
+generatorOptions:
+  disableNameSuffixHash: true
+`,
+		"sa.yaml": `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: module
+`,
+	})
+
+	objects, err := renderKustomization(dir, modulesNamespace, "my-release", map[string]interface{}{"uuid": "123"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(objects).To(gomega.HaveLen(2))
+	kinds := []string{}
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetKind())
+		g.Expect(obj.GetLabels()).To(gomega.HaveKeyWithValue(managerUtils.KubernetesInstance, "my-release"))
+		if obj.GetKind() == "ConfigMap" {
+			g.Expect(obj.Object["data"]).To(gomega.HaveKeyWithValue(KustomizeValuesFile, "uuid: \"123\"\n"))
+		}
+	}
+	g.Expect(kinds).To(gomega.ConsistOf("ConfigMap", "ServiceAccount"))
+
+	_, err = renderKustomization(t.TempDir(), modulesNamespace, "my-release", nil)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+// This test checks the deployment, the status and the deletion of a release of a manifests module stored in a ConfigMap
+func TestObjectsDeployer(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	log := logging.LogInit(logging.CONTROLLER, "test-objects-deployer")
+	source := &corev1.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Name: "my-module-manifests", Namespace: "fybrik-system"},
+		Data:       map[string]string{"manifests.yaml": testManifest},
+	}
+	s, mapper := newObjectsScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(source).Build()
+	deployer := newObjectsDeployer(cl, renderManifests)
+	chartSpec := &fapp.ChartSpec{Name: "configmap://fybrik-system/my-module-manifests", Type: fapp.ManifestsDeployment}
+	values := map[string]interface{}{"uuid": "123"}
+
+	status, err := deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status).To(gomega.BeNil())
+
+	g.Expect(deployer.Deploy(ctx, chartSpec, modulesNamespace, "my-release", values, &log)).To(gomega.Succeed())
+	status, err = deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.State).To(gomega.Equal(DeployedRelease))
+	g.Expect(status.Resources).To(gomega.HaveLen(2))
+	for _, obj := range status.Resources {
+		g.Expect(obj.GetNamespace()).To(gomega.Equal(modulesNamespace))
+	}
+
+	// objects that are removed from the package are deleted on upgrade
+	source.Data["manifests.yaml"] = testConfigManifest
+	g.Expect(cl.Update(ctx, source)).To(gomega.Succeed())
+	g.Expect(deployer.Deploy(ctx, chartSpec, modulesNamespace, "my-release", values, &log)).To(gomega.Succeed())
+	sa := &corev1.ServiceAccount{}
+	err = cl.Get(ctx, client.ObjectKey{Namespace: modulesNamespace, Name: "my-release"}, sa)
+	g.Expect(err).To(gomega.HaveOccurred())
+	status, err = deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.Resources).To(gomega.HaveLen(1))
+
+	// a deleted object fails the release, so that it is deployed again
+	config := &corev1.ConfigMap{ObjectMeta: meta.ObjectMeta{Namespace: modulesNamespace, Name: "my-release-config"}}
+	g.Expect(cl.Delete(ctx, config)).To(gomega.Succeed())
+	status, err = deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.State).To(gomega.Equal(FailedRelease))
+
+	g.Expect(deployer.Deploy(ctx, chartSpec, modulesNamespace, "my-release", values, &log)).To(gomega.Succeed())
+	g.Expect(deployer.Uninstall(ctx, modulesNamespace, "my-release")).To(gomega.Succeed())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(config), config)).ToNot(gomega.Succeed())
+	status, err = deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status).To(gomega.BeNil())
+}
+
+// This test checks that cluster-scoped objects are deployed without a namespace, and that objects of unknown kinds fail the release
+func TestObjectsDeployerClusterScopedObjects(t *testing.T) {
+	g := gomega.NewWithT(t)
+	ctx := context.Background()
+	log := logging.LogInit(logging.CONTROLLER, "test-objects-deployer")
+	source := &corev1.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{Name: "my-module-manifests", Namespace: "fybrik-system"},
+		Data:       map[string]string{"manifests.yaml": testConfigManifest + "---\n" + testClusterRoleManifest},
+	}
+	s, mapper := newObjectsScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithRESTMapper(mapper).WithObjects(source).Build()
+	deployer := newObjectsDeployer(cl, renderManifests)
+	chartSpec := &fapp.ChartSpec{Name: "configmap://fybrik-system/my-module-manifests", Type: fapp.ManifestsDeployment}
+
+	g.Expect(deployer.Deploy(ctx, chartSpec, modulesNamespace, "my-release", nil, &log)).To(gomega.Succeed())
+	role := &rbacv1.ClusterRole{}
+	g.Expect(cl.Get(ctx, client.ObjectKey{Name: "my-release-reader"}, role)).To(gomega.Succeed())
+	config := &corev1.ConfigMap{}
+	g.Expect(cl.Get(ctx, client.ObjectKey{Namespace: modulesNamespace, Name: "my-release-config"}, config)).To(gomega.Succeed())
+	status, err := deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.State).To(gomega.Equal(DeployedRelease))
+	g.Expect(status.Resources).To(gomega.HaveLen(2))
+
+	g.Expect(deployer.Uninstall(ctx, modulesNamespace, "my-release")).To(gomega.Succeed())
+	g.Expect(cl.Get(ctx, client.ObjectKeyFromObject(role), role)).ToNot(gomega.Succeed())
+
+	// the scope of objects of unknown kinds can not be resolved
+	source.Data["manifests.yaml"] = testConfigManifest + "---\napiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: unknown\n"
+	g.Expect(cl.Update(ctx, source)).To(gomega.Succeed())
+	g.Expect(deployer.Deploy(ctx, chartSpec, modulesNamespace, "my-release", nil, &log)).ToNot(gomega.Succeed())
+	status, err = deployer.Status(ctx, modulesNamespace, "my-release")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(status.State).To(gomega.Equal(FailedRelease))
+}
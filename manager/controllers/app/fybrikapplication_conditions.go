@@ -10,6 +10,7 @@ import (
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 )
 
 // Condition indices are static. Conditions always present in the status.
@@ -80,7 +81,7 @@ func isReady(application *fapp.FybrikApplication) bool {
 	}
 	for _, asset := range application.Spec.Data {
 		assetState := application.Status.AssetStates[asset.DataSetID]
-		if len(assetState.Conditions) == 0 {
+		if !hasConditions(&assetState) {
 			return false
 		}
 		if assetState.Conditions[DenyConditionIndex].Status == corev1.ConditionFalse &&
@@ -97,9 +98,37 @@ func getErrorMessages(application *fapp.FybrikApplication) string {
 	}
 	var errorMsgs []string
 	for _, state := range application.Status.AssetStates {
+		if !hasConditions(&state) {
+			continue
+		}
 		if state.Conditions[ErrorConditionIndex].Status == corev1.ConditionTrue {
 			errorMsgs = append(errorMsgs, state.Conditions[ErrorConditionIndex].Message)
 		}
 	}
 	return strings.Join(errorMsgs, "\n")
 }
+
+// hasConditions checks whether the conditions of the asset state have been initialized
+func hasConditions(state *fapp.AssetState) bool {
+	return len(state.Conditions) >= numConditions
+}
+
+// getApplicationState returns the state of the application reported in the metrics: error, deny, ready or pending.
+// Applications whose status has not been initialized yet are pending.
+func getApplicationState(application *fapp.FybrikApplication) string {
+	if application.Status.ValidApplication == corev1.ConditionFalse || getErrorMessages(application) != "" {
+		return metrics.ErrorState
+	}
+	for _, state := range application.Status.AssetStates {
+		if !hasConditions(&state) {
+			return metrics.PendingState
+		}
+		if state.Conditions[DenyConditionIndex].Status == corev1.ConditionTrue {
+			return metrics.DenyState
+		}
+	}
+	if application.Status.Ready {
+		return metrics.ReadyState
+	}
+	return metrics.PendingState
+}
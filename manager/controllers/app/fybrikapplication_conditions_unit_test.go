@@ -0,0 +1,41 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+	corev1 "k8s.io/api/core/v1"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/metrics"
+)
+
+// This test checks that the state of applications whose status has not been initialized is reported as pending
+func TestApplicationStateOfUninitializedStatus(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+
+	application := &fappv1.FybrikApplication{
+		Spec: fappv1.FybrikApplicationSpec{Data: []fappv1.DataContext{{DataSetID: "s3/allow-dataset"}}},
+	}
+	g.Expect(getApplicationState(application)).To(gomega.Equal(metrics.PendingState))
+
+	application.Status.AssetStates = map[string]fappv1.AssetState{"s3/allow-dataset": {}}
+	g.Expect(getApplicationState(application)).To(gomega.Equal(metrics.PendingState))
+	g.Expect(isReady(application)).To(gomega.BeFalse())
+
+	application.Status.AssetStates["s3/allow-dataset"] = fappv1.AssetState{
+		Conditions: []fappv1.Condition{{Type: fappv1.ReadyCondition, Status: corev1.ConditionTrue}},
+	}
+	g.Expect(getApplicationState(application)).To(gomega.Equal(metrics.PendingState))
+	g.Expect(getErrorMessages(application)).To(gomega.BeEmpty())
+
+	log := zerolog.Nop()
+	initStatus(application)
+	setDenyCondition(ApplicationContext{Application: application, Log: &log}, "s3/allow-dataset", "denied")
+	g.Expect(getApplicationState(application)).To(gomega.Equal(metrics.DenyState))
+}
@@ -13,6 +13,8 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -39,12 +41,14 @@ import (
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/infrastructure"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/multicluster"
 	"fybrik.io/fybrik/pkg/serde"
+	"fybrik.io/fybrik/pkg/tracing"
 	"fybrik.io/fybrik/pkg/validate"
 	"fybrik.io/fybrik/pkg/vault"
 )
@@ -62,20 +66,33 @@ type FybrikApplicationReconciler struct {
 	StorageManager    storage.StorageManagerInterface
 	ConfigEvaluator   adminconfig.EvaluatorInterface
 	Infrastructure    *infrastructure.AttributeManager
+	// Vault leases the dynamic credentials of the assets, it is nil if vault dynamic secrets are not configured
+	Vault vault.Interface
 }
 
 type ApplicationContext struct {
+	// Ctx is the context of the reconcile cycle carrying the trace span of the application
+	Ctx         context.Context
 	Log         *zerolog.Logger
 	Application *fappv1.FybrikApplication
 	UUID        string
 }
 
+// GetContext returns the context of the reconcile cycle, or a background context if it has not been set
+func (a ApplicationContext) GetContext() context.Context {
+	if a.Ctx == nil {
+		return context.Background()
+	}
+	return a.Ctx
+}
+
 var ApplicationTaxonomy = environment.GetDataDir() + "/taxonomy/fybrik_application.json"
 var DataCatalogGetAssetResponseTaxonomy = environment.GetDataDir() + "/taxonomy/datacatalog.json#/definitions/GetAssetResponse"
 var DataCatalogCreateAssetResponseTaxonomy = environment.GetDataDir() + "/taxonomy/datacatalog.json#/definitions/CreateAssetResponse"
 
 const (
 	FybrikApplicationKind = "FybrikApplication"
+	TracerName            = "fybrik.io/fybrik/manager/controllers/app"
 	PlotterUpdatePrefix   = "plotter_"
 	Separator             = " ; "
 )
@@ -97,7 +114,8 @@ const (
 // The outcome is a Plotter containing multiple Blueprints that run on different clusters
 //
 //nolint:gocyclo
-func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() { metrics.ObserveReconcile(FybrikApplicationKind, result, err) }()
 	sublog := r.Log.With().Str(FybrikApplicationKind, req.NamespacedName.String()).Logger()
 
 	sublog.Trace().Msg("*** FybrikApplication Reconcile ***")
@@ -121,7 +139,7 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Log the fybrikapplication
 	logging.LogStructure(FybrikApplicationKind, application, &log, zerolog.TraceLevel, true, true)
-	applicationContext := ApplicationContext{Log: &log, Application: application, UUID: uuid}
+	applicationContext := ApplicationContext{Ctx: ctx, Log: &log, Application: application, UUID: uuid}
 	if plotterUpdate && (application.Status.Generated == nil || application.Status.Generated.AppVersion != application.GetGeneration()) {
 		// plotter update has been received but it does not match the fybrik application status
 		// this can happen if the plotter has just been created, and the application status was not updated by the server
@@ -165,6 +183,8 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			return ctrl.Result{}, err
 		}
 		r.checkReadiness(applicationContext, resourceStatus)
+		// the endpoints are switched once the module releases of a new revision serve the workload
+		r.advertiseEndpoints(applicationContext, nil)
 	} else if (observedStatus.ObservedGeneration != appVersion) || !generationComplete {
 		// spec has been changed, or there was a failure to allocate a plotter
 		if result, err := r.reconcile(applicationContext); err != nil || result.Requeue || (result.RequeueAfter > 0) {
@@ -176,13 +196,16 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 		application.Status.ObservedGeneration = appVersion
 	}
+	// renew the credential leases that are due, and reconcile again when the next lease is due
+	requeueAfter := r.renewLeases(applicationContext)
 	application.Status.Ready = isReady(application)
 	log.Trace().Str(logging.ACTION, logging.UPDATE).Msg("Updating status for desired generation " + fmt.Sprint(application.GetGeneration()))
 	if err := utils.UpdateStatus(ctx, r.Client, application, observedStatus); err != nil {
 		return ctrl.Result{}, err
 	}
 	// add finalizers if some resources have been allocated (plotter, datasets)
-	if application.Status.Generated != nil || (len(application.Status.ProvisionedStorage) > 0) {
+	if application.Status.Generated != nil || (len(application.Status.ProvisionedStorage) > 0) ||
+		(len(application.Status.CredentialLeases) > 0) {
 		if err := r.addFinalizers(ctx, applicationContext); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -192,7 +215,7 @@ func (r *FybrikApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		// trigger a new reconcile
 		return ctrl.Result{Requeue: true}, nil
 	}
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *FybrikApplicationReconciler) checkReadiness(applicationContext ApplicationContext, status fappv1.ObservedState) {
@@ -216,6 +239,20 @@ func (r *FybrikApplicationReconciler) checkReadiness(applicationContext Applicat
 			continue
 		}
 
+		// remove the asset from the catalog once the delete module has completed successfully
+		if dataCtx.Flow == taxonomy.DeleteFlow {
+			state := applicationContext.Application.Status.AssetStates[assetID]
+			if !state.Deleted {
+				if err := r.DeleteAsset(applicationContext.GetContext(), assetID, applicationContext.Application); err != nil {
+					// log an error and make a new attempt to delete the asset
+					setErrorCondition(applicationContext, assetID, err.Error())
+					continue
+				}
+				state.Deleted = true
+				applicationContext.Application.Status.AssetStates[assetID] = state
+			}
+		}
+
 		// register assets if the ready state has been received
 		if dataCtx.Requirements.FlowParams.Catalog != "" {
 			if applicationContext.Application.Status.AssetStates[assetID].CatalogedAsset != "" {
@@ -245,7 +282,7 @@ func (r *FybrikApplicationReconciler) checkReadiness(applicationContext Applicat
 			}
 			applicationContext.Application.Status.ProvisionedStorage[assetID] = provisioned
 			// register the asset
-			if newAssetID, err := r.RegisterAsset(assetID, dataCtx.Requirements.FlowParams.Catalog,
+			if newAssetID, err := r.RegisterAsset(applicationContext.GetContext(), assetID, dataCtx.Requirements.FlowParams.Catalog,
 				&provisioned, applicationContext.Application); err == nil {
 				state := applicationContext.Application.Status.AssetStates[assetID]
 				state.CatalogedAsset = newAssetID
@@ -303,6 +340,10 @@ func (r *FybrikApplicationReconciler) addFinalizers(ctx context.Context, applica
 }
 
 func (r *FybrikApplicationReconciler) deleteExternalResources(applicationContext ApplicationContext) error {
+	// revoke the leases on the dynamic credentials used by the modules
+	if err := r.revokeLeases(applicationContext, applicationContext.Application.Status.CredentialLeases); err != nil {
+		return err
+	}
 	// clear provisioned storage
 	// References to buckets (Dataset resources) are deleted. Buckets that are persistent will not be removed upon Dataset deletion.
 	var deletedKeys []string
@@ -310,7 +351,7 @@ func (r *FybrikApplicationReconciler) deleteExternalResources(applicationContext
 	for datasetID, datasetDetails := range applicationContext.Application.Status.ProvisionedStorage {
 		var err error
 		if !datasetDetails.Persistent {
-			err = r.deleteTemporaryStorage(datasetDetails)
+			err = r.deleteTemporaryStorage(applicationContext.GetContext(), datasetDetails)
 		}
 		if err != nil {
 			errMsgs = append(errMsgs, err.Error())
@@ -363,11 +404,42 @@ func setVirtualEndpoints(application *fappv1.FybrikApplication, flows []fappv1.F
 	}
 }
 
+// advertiseEndpoints populates the endpoints in the status of the fybrikapplication with the services of the module releases
+// that serve the workload. These differ from the planned services once the modules have been rolled out side by side.
+// The flows of the generated plotter are used if no plotter spec is given.
+func (r *FybrikApplicationReconciler) advertiseEndpoints(applicationContext ApplicationContext, plotterSpec *fappv1.PlotterSpec) {
+	application := applicationContext.Application
+	plotter := &fappv1.Plotter{}
+	if ref := application.Status.Generated; ref != nil {
+		if err := r.Get(applicationContext.GetContext(), types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name},
+			plotter); err != nil && plotterSpec == nil {
+			applicationContext.Log.Error().Err(err).Msg("Error getting the generated plotter")
+			return
+		}
+	}
+	if plotterSpec == nil {
+		plotterSpec = &plotter.Spec
+	}
+	setVirtualEndpoints(application, plotterSpec.Flows)
+	renames := servedReleaseNames(application.Name, applicationContext.UUID, plotterSpec, plotter.Status.Blueprints)
+	for assetID, state := range application.Status.AssetStates {
+		state.Endpoint = renameConnection(state.Endpoint, renames)
+		application.Status.AssetStates[assetID] = state
+	}
+}
+
 // reconcile receives either FybrikApplication CRD
 // or a status update from the generated resource
-func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationContext) (ctrl.Result, error) {
+func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationContext) (result ctrl.Result, err error) {
 	// Log the request received - i.e. the fybrikapplication.spec
 	applicationContext.Log.Trace().Msg("*** reconcile ***")
+	ctx, span := tracing.Tracer(TracerName).Start(applicationContext.GetContext(), "reconcile",
+		trace.WithAttributes(
+			attribute.String("application.name", applicationContext.Application.Name),
+			attribute.String("application.namespace", applicationContext.Application.Namespace),
+			attribute.String(utils.FybrikAppUUID, applicationContext.UUID)))
+	defer func() { tracing.EndSpan(span, err) }()
+	applicationContext.Ctx = ctx
 
 	// Data User created or updated the FybrikApplication
 
@@ -382,6 +454,11 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	// restrict the modules to the versions that the application may use
+	if env.Modules, err = SelectModuleVersions(env.Modules, applicationContext.Application); err != nil {
+		applicationContext.Application.Status.ErrorMessage = err.Error()
+		return ctrl.Result{}, nil
+	}
 	// workload cluster is common for all datasets in the given application
 	workloadCluster, err := r.GetWorkloadCluster(applicationContext, env)
 	if err != nil {
@@ -405,6 +482,17 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 		}
 		requirements = append(requirements, req)
 	}
+	// lease the dynamic credentials of the assets for the modules
+	// the leases of the previous data paths are revoked once the new ones are in place,
+	// so that the modules do not keep credentials that the changed policies no longer grant
+	leases, err := r.issueLeases(applicationContext, requirements)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// the leases are applied only once the new data path has been deployed, since the modules of the previous
+	// data path keep using the previous leases until then
+	deployed := false
+	defer func() { r.settleLeases(applicationContext, leases, deployed) }()
 	// check if can proceed
 	if len(requirements) == 0 {
 		return ctrl.Result{}, nil
@@ -419,17 +507,17 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 		return ctrl.Result{}, err
 	}
 	// clean irrelevant buckets and update the application status with the provisioned storage
-	if err := r.updateProvisionedStorageStatus(applicationContext, provisionedStorage); err != nil {
+	if err = r.updateProvisionedStorageStatus(applicationContext, provisionedStorage); err != nil {
 		return ctrl.Result{}, err
 	}
-	setVirtualEndpoints(applicationContext.Application, plotterSpec.Flows)
+	r.advertiseEndpoints(applicationContext, plotterSpec)
 	ownerRef := &fappv1.ResourceReference{
 		Name:       applicationContext.Application.Name,
 		Namespace:  applicationContext.Application.Namespace,
 		AppVersion: applicationContext.Application.GetGeneration()}
 
 	resourceRef := r.ResourceInterface.CreateResourceReference(ownerRef)
-	if err := r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, plotterSpec,
+	if err = r.ResourceInterface.CreateOrUpdateResource(ownerRef, resourceRef, plotterSpec,
 		applicationContext.Application.Labels, applicationContext.UUID); err != nil {
 		applicationContext.Log.Error().Err(err).Str(logging.ACTION, logging.CREATE).Msgf("Error creating %s", resourceRef.Kind)
 		if err.Error() == InvalidClusterConfiguration {
@@ -438,7 +526,9 @@ func (r *FybrikApplicationReconciler) reconcile(applicationContext ApplicationCo
 		}
 		return ctrl.Result{}, err
 	}
+	deployed = true
 	applicationContext.Application.Status.Generated = resourceRef
+	applicationContext.Application.Status.Modules = UsedModuleVersions(plotterSpec, env.Modules)
 	applicationContext.Log.Trace().Str(logging.ACTION, logging.CREATE).Msgf("Created %s successfully!", resourceRef.Kind)
 	// propagating connector messages to the status
 	for key, val := range messages {
@@ -454,6 +544,17 @@ func (r *FybrikApplicationReconciler) Environment() (*datapath.Environment, erro
 		r.Log.Error().Err(err).Msg("Error while listing modules")
 		return nil, err
 	}
+	// modules whose dependencies can not be deployed are not used in data paths
+	unresolved := []string{}
+	for name, module := range moduleMap {
+		if _, err = GetDependencies(module, moduleMap); err != nil {
+			r.Log.Warn().Err(err).Msgf("ignoring module %s with unresolved dependencies", name)
+			unresolved = append(unresolved, name)
+		}
+	}
+	for _, name := range unresolved {
+		delete(moduleMap, name)
+	}
 	r.Log.Info().Msg("Listing modules")
 	for m := range moduleMap {
 		r.Log.Info().Msgf("Module: %s", m)
@@ -530,11 +631,14 @@ func (r *FybrikApplicationReconciler) ValidateAssetResponse(response interface{}
 // It also returns messages from data catalog and/or policy manager
 // to be propagated to the application status (relevant for the ready state of the asset)
 func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo, appContext ApplicationContext,
-	workloadCluster multicluster.Cluster, env *datapath.Environment) (string, error) {
+	workloadCluster multicluster.Cluster, env *datapath.Environment) (message string, err error) {
+	ctx, span := tracing.Tracer(TracerName).Start(appContext.GetContext(), "constructDataInfo",
+		trace.WithAttributes(attribute.String(logging.DATASETID, req.Context.DataSetID)))
+	defer func() { tracing.EndSpan(span, err) }()
+	appContext.Ctx = ctx
 	// Call the DataCatalog service to get info about the dataset
 	input := appContext.Application
 	log := appContext.Log.With().Str(logging.DATASETID, req.Context.DataSetID).Logger()
-	var err error
 	// retrieve and propagate messages from catalog and policy manager
 	// if there are no errors to construct the data plane
 	var catalogMsg, governanceMsg string
@@ -551,7 +655,7 @@ func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo,
 			AssetID:       taxonomy.AssetID(req.Context.DataSetID),
 			OperationType: datacatalog.READ}
 
-		if response, err = r.DataCatalog.GetAssetInfo(&request, credentialPath); err != nil {
+		if response, err = r.DataCatalog.GetAssetInfo(ctx, &request, credentialPath); err != nil {
 			log.Error().Err(err).Msg("failed to receive the catalog connector response")
 			// return the error from the data catalog
 			return "", err
@@ -565,6 +669,10 @@ func (r *FybrikApplicationReconciler) constructDataInfo(req *datapath.DataInfo,
 		}
 		logging.LogStructure("Catalog connector response", response, &log, zerolog.DebugLevel, false, false)
 		catalogMsg = response.Message
+		// record the revision of the metadata that the governance decisions are based on
+		state := input.Status.AssetStates[req.Context.DataSetID]
+		state.Revision = response.Revision
+		input.Status.AssetStates[req.Context.DataSetID] = state
 		response.DeepCopyInto(req.DataDetails)
 	} else if req.Context.Requirements.FlowParams.ResourceMetadata != nil {
 		// Fill req.DataDetails with the metadata from the fybrikapplication
@@ -659,7 +767,7 @@ func (r *FybrikApplicationReconciler) checkGovernanceActions(configEvaluatorInpu
 		actions, _, err := LookupPolicyDecisions(req.Context.DataSetID, resMetadata, r.PolicyManager, appContext, &reqAction)
 		if err == nil {
 			req.StorageRequirements[geo] = actions
-		} else if err.Error() != WriteNotAllowed {
+		} else if errors.Cause(err).Error() != WriteNotAllowed {
 			// received an error from the connector
 			return "", err
 		}
@@ -750,6 +858,9 @@ func (r *FybrikApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	numReconciles := environment.GetEnvAsInt(controllers.ApplicationConcurrentReconcilesConfiguration,
 		controllers.DefaultApplicationConcurrentReconciles)
 
+	if err := metrics.RegisterApplicationsCollector(r.countApplicationsByState); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{MaxConcurrentReconciles: numReconciles}).
 		For(&fappv1.FybrikApplication{}).
@@ -758,6 +869,19 @@ func (r *FybrikApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}, handler.EnqueueRequestsFromMapFunc(mapFn)).Complete(r)
 }
 
+// countApplicationsByState returns the number of FybrikApplications in each state
+func (r *FybrikApplicationReconciler) countApplicationsByState() (map[string]int, error) {
+	var applications fappv1.FybrikApplicationList
+	if err := r.List(context.Background(), &applications); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for i := range applications.Items {
+		counts[getApplicationState(&applications.Items[i])]++
+	}
+	return counts, nil
+}
+
 // AnalyzeError analyzes whether the given error is fatal, or a retrial attempt can be made.
 // Reasons for retrial can be either communication problems with external services, or kubernetes
 // problems to perform some action on a resource.
@@ -776,7 +900,10 @@ func AnalyzeError(appContext ApplicationContext, assetID string, err error) {
 		}
 	}
 	switch cause {
-	case dcclient.AssetIDNotFound, dcclient.AccessForbidden, ReadAccessDenied, CopyNotAllowed, WriteNotAllowed:
+	case ReadAccessDenied, WriteNotAllowed:
+		// the message of a governance denial names the policy managers that denied the access
+		setDenyCondition(appContext, assetID, err.Error())
+	case dcclient.AssetIDNotFound, dcclient.AccessForbidden, CopyNotAllowed:
 		setDenyCondition(appContext, assetID, cause)
 	default:
 		setErrorCondition(appContext, assetID, cause)
@@ -805,6 +932,12 @@ func (r *FybrikApplicationReconciler) GetAllModules() (map[string]*fappv1.Fybrik
 			r.Log.Warn().Msgf("ignoring invalid module %s", module.Name)
 			continue
 		}
+		if len(module.Status.Conditions) > ModuleReadinessConditionIndex &&
+			module.Status.Conditions[ModuleReadinessConditionIndex].Status == v1.ConditionFalse {
+			r.Log.Warn().Msgf("ignoring unhealthy module %s: %s", module.Name,
+				module.Status.Conditions[ModuleReadinessConditionIndex].Message)
+			continue
+		}
 		refineCapabilities(module)
 		moduleMap[moduleList.Items[ind].Name] = &moduleList.Items[ind]
 	}
@@ -849,13 +982,13 @@ func (r *FybrikApplicationReconciler) getStorageAccounts() ([]*fappv2.FybrikStor
 	return accounts, nil
 }
 
-func (r *FybrikApplicationReconciler) deleteTemporaryStorage(datasetDetails fappv1.DatasetDetails) error {
+func (r *FybrikApplicationReconciler) deleteTemporaryStorage(ctx context.Context, datasetDetails fappv1.DatasetDetails) error {
 	req := &storagemanager.DeleteStorageRequest{
 		Connection: datasetDetails.Details.Connection,
 		Secret:     datasetDetails.SecretRef,
 		Opts:       storagemanager.Options{},
 	}
-	return r.StorageManager.DeleteStorage(req)
+	return r.StorageManager.DeleteStorage(ctx, req)
 }
 
 func (r *FybrikApplicationReconciler) updateProvisionedStorageStatus(applicationContext ApplicationContext,
@@ -865,7 +998,7 @@ func (r *FybrikApplicationReconciler) updateProvisionedStorageStatus(application
 	for datasetID, provisioned := range applicationContext.Application.Status.ProvisionedStorage {
 		if _, found := provisionedStorage[datasetID]; !found {
 			if !provisioned.Persistent {
-				if err := r.deleteTemporaryStorage(provisioned); err != nil {
+				if err := r.deleteTemporaryStorage(applicationContext.GetContext(), provisioned); err != nil {
 					return err
 				}
 			}
@@ -891,13 +1024,17 @@ func (r *FybrikApplicationReconciler) updateProvisionedStorageStatus(application
 
 func (r *FybrikApplicationReconciler) buildSolution(applicationContext ApplicationContext, env *datapath.Environment,
 	requirements []datapath.DataInfo) (map[string]NewAssetInfo, *fappv1.PlotterSpec, error) {
+	ctx, span := tracing.Tracer(TracerName).Start(applicationContext.GetContext(), "buildSolution")
+	defer span.End()
 	plotterGen := &PlotterGenerator{
+		Ctx:                ctx,
 		Client:             r.Client,
 		Log:                applicationContext.Log,
 		Owner:              types.NamespacedName{Namespace: applicationContext.Application.Namespace, Name: applicationContext.Application.Name},
 		UUID:               applicationContext.UUID,
 		StorageManager:     r.StorageManager,
 		ProvisionedStorage: make(map[string]NewAssetInfo),
+		Modules:            env.Modules,
 	}
 
 	plotterSpec := &fappv1.PlotterSpec{
@@ -909,7 +1046,7 @@ func (r *FybrikApplicationReconciler) buildSolution(applicationContext Applicati
 		Templates:        map[string]fappv1.Template{},
 	}
 
-	paths, err := solve(env, requirements, applicationContext.Log)
+	paths, err := solve(ctx, env, requirements, applicationContext.Log)
 	if err != nil {
 		applicationContext.Application.Status.ErrorMessage = err.Error()
 		return plotterGen.ProvisionedStorage, plotterSpec, nil
@@ -1971,3 +1971,92 @@ func TestEmptyInterface(t *testing.T) {
 	// check plotter creation
 	g.Expect(application.Status.Generated).ToNot(gomega.BeNil())
 }
+
+// This test checks the delete flow: a delete module is selected and the asset is removed
+// from the catalog once the plotter is ready
+func TestDeleteAsset(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	namespaced := types.NamespacedName{
+		Name:      "delete-test",
+		Namespace: "default",
+	}
+	adminCRsNamespace := environment.GetAdminCRsNamespace()
+	application := &fappv1.FybrikApplication{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/data-usage.yaml", application)).NotTo(gomega.HaveOccurred())
+	application.Name = namespaced.Name
+	application.Spec.Selector = fappv1.Selector{}
+	application.Spec.Data[0] = fappv1.DataContext{
+		DataSetID: "s3/allow-dataset",
+		Flow:      taxonomy.DeleteFlow,
+	}
+	application.SetGeneration(1)
+	application.SetUID("30")
+	// Objects to track in the fake client.
+	objs := []runtime.Object{
+		application,
+	}
+
+	// Register operator types with the runtime scheme.
+	s := utils.NewScheme(g)
+
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	// Delete module
+	deleteModule := &fappv1.FybrikModule{}
+	g.Expect(readObjectFromFile("../../testdata/unittests/module-delete.yaml", deleteModule)).NotTo(gomega.HaveOccurred())
+	deleteModule.Namespace = adminCRsNamespace
+	g.Expect(cl.Create(context.Background(), deleteModule)).NotTo(gomega.HaveOccurred(), "the delete module could not be created")
+
+	// Create a FybrikApplicationReconciler object with the scheme and fake client.
+	r := createTestFybrikApplicationController(cl, s)
+	g.Expect(r).NotTo(gomega.BeNil())
+
+	req := reconcile.Request{
+		NamespacedName: namespaced,
+	}
+
+	_, err := r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+
+	err = cl.Get(context.Background(), req.NamespacedName, application)
+	g.Expect(err).To(gomega.BeNil(), "Cannot fetch fybrikapplication")
+	g.Expect(getErrorMessages(application)).To(gomega.BeEmpty())
+	// check plotter creation
+	g.Expect(application.Status.Generated).ToNot(gomega.BeNil())
+	plotterObjectKey := types.NamespacedName{
+		Namespace: application.Status.Generated.Namespace,
+		Name:      application.Status.Generated.Name,
+	}
+	plotter := &fappv1.Plotter{}
+	err = cl.Get(context.Background(), plotterObjectKey, plotter)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(plotter.Spec.Flows).To(gomega.HaveLen(1))
+	g.Expect(plotter.Spec.Flows[0].FlowType).To(gomega.Equal(taxonomy.DeleteFlow))
+	g.Expect(plotter.Spec.Templates).To(gomega.HaveLen(1))
+	catalog := r.DataCatalog.(*mockup.DataCatalogDummy)
+	g.Expect(catalog.DeletedAssets()).To(gomega.BeEmpty())
+
+	// mark the plotter as ready
+	plotter.Status.ObservedState.Ready = true
+	g.Expect(cl.Update(context.Background(), plotter)).NotTo(gomega.HaveOccurred())
+
+	// the new reconcile should remove the asset from the catalog
+	newReq := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: req.Namespace, Name: "plotter_" + req.Name}}
+	_, err = r.Reconcile(context.Background(), newReq)
+	g.Expect(err).To(gomega.BeNil())
+	err = cl.Get(context.Background(), req.NamespacedName, application)
+	g.Expect(err).To(gomega.BeNil(), "Cannot fetch fybrikapplication")
+	g.Expect(application.Status.Ready).To(gomega.BeTrue())
+	g.Expect(application.Status.AssetStates["s3/allow-dataset"].Deleted).To(gomega.BeTrue())
+	g.Expect(catalog.DeletedAssets()).To(gomega.ConsistOf("s3/allow-dataset"))
+
+	// additional plotter updates should not delete the asset again
+	_, err = r.Reconcile(context.Background(), newReq)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(catalog.DeletedAssets()).To(gomega.HaveLen(1))
+}
@@ -5,23 +5,26 @@ package app
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	"github.com/rs/zerolog"
 	corev1 "k8s.io/api/core/v1"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
-	"fybrik.io/fybrik/pkg/validate"
+	"fybrik.io/fybrik/pkg/metrics"
+	pkgutils "fybrik.io/fybrik/pkg/utils"
 )
 
 // FybrikModuleReconciler reconciles a FybrikModule object
@@ -35,12 +38,16 @@ type FybrikModuleReconciler struct {
 var ModuleTaxonomy = environment.GetDataDir() + "/taxonomy/fybrik_module.json"
 
 const (
-	ModuleValidationConditionIndex = 0
-	FybrikModuleKind               = "FybrikModule"
+	ModuleValidationConditionIndex   = 0
+	ModuleDependenciesConditionIndex = 1
+	ModuleDeprecationConditionIndex  = 2
+	ModuleReadinessConditionIndex    = 3
+	FybrikModuleKind                 = "FybrikModule"
 )
 
 // Reconcile validates FybrikModule CRD
-func (r *FybrikModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *FybrikModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() { metrics.ObserveReconcile(FybrikModuleKind, result, err) }()
 	log := r.Log.With().Str(logging.CONTROLLER, FybrikModuleKind).Str(logging.MODULE, req.NamespacedName.String()).Logger()
 
 	// obtain FybrikModule resource
@@ -85,11 +92,145 @@ func (r *FybrikModuleReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		moduleContext.Status.Conditions[ModuleValidationConditionIndex] = condition
 	}
 
+	// the dependencies are resolved in every reconcile, since they depend on other modules
+	if err = r.resolveDependencies(ctx, moduleContext); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.checkDeprecatedUsage(ctx, moduleContext); err != nil {
+		return ctrl.Result{}, err
+	}
+	// modules with a health check are checked periodically
+	result.RequeueAfter = r.checkHealth(ctx, moduleContext)
+
 	// Update CRD status in case of change (other than deletion, which was handled separately)
 	if moduleContext.DeletionTimestamp.IsZero() {
-		return ctrl.Result{}, utils.UpdateStatus(ctx, r.Client, moduleContext, observedStatus)
+		return result, utils.UpdateStatus(ctx, r.Client, moduleContext, observedStatus)
+	}
+	return result, nil
+}
+
+// resolveDependencies sets the condition that indicates whether the module dependencies are available and acyclic
+func (r *FybrikModuleReconciler) resolveDependencies(ctx context.Context, module *fapp.FybrikModule) error {
+	if len(module.Status.Conditions) <= ModuleDependenciesConditionIndex {
+		module.Status.Conditions = append(module.Status.Conditions,
+			fapp.Condition{Type: fapp.DependenciesResolvedCondition, Status: corev1.ConditionUnknown})
+	}
+	moduleMap, err := r.validModules(ctx, module.Namespace)
+	if err != nil {
+		return err
+	}
+	// the module is resolved even if it is not valid itself
+	moduleMap[module.Name] = module
+	condition := &module.Status.Conditions[ModuleDependenciesConditionIndex]
+	condition.ObservedGeneration = module.GetGeneration()
+	if _, err = GetDependencies(module, moduleMap); err != nil {
+		r.Log.Warn().Str(logging.MODULE, module.Name).Err(err).Msg("Fybrik module dependencies can not be resolved")
+		condition.Status = corev1.ConditionFalse
+		condition.Message = err.Error()
+	} else {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = ""
+	}
+	return nil
+}
+
+// checkDeprecatedUsage sets the condition that indicates whether a deprecated module version is still used by applications
+func (r *FybrikModuleReconciler) checkDeprecatedUsage(ctx context.Context, module *fapp.FybrikModule) error {
+	if len(module.Status.Conditions) <= ModuleDeprecationConditionIndex {
+		module.Status.Conditions = append(module.Status.Conditions,
+			fapp.Condition{Type: fapp.DeprecatedInUseCondition, Status: corev1.ConditionUnknown})
+	}
+	condition := &module.Status.Conditions[ModuleDeprecationConditionIndex]
+	condition.ObservedGeneration = module.GetGeneration()
+	condition.Status = corev1.ConditionFalse
+	condition.Message = ""
+	if !module.Spec.Deprecated {
+		return nil
+	}
+	var applications fapp.FybrikApplicationList
+	if err := r.List(ctx, &applications); err != nil {
+		return err
+	}
+	users := []string{}
+	for ind := range applications.Items {
+		application := &applications.Items[ind]
+		if version, used := application.Status.Modules[module.Name]; used && version == module.Spec.Version {
+			users = append(users, application.Namespace+"/"+application.Name)
+		}
+	}
+	if len(users) > 0 {
+		sort.Strings(users)
+		condition.Status = corev1.ConditionTrue
+		condition.Message = fmt.Sprintf("deprecated version %s is used by %s", module.Spec.Version, strings.Join(users, ", "))
+	}
+	return nil
+}
+
+// deprecatedModules returns reconcile requests for the deprecated modules,
+// so that their usage is checked again when an application is changed
+func (r *FybrikModuleReconciler) deprecatedModules(obj client.Object) []reconcile.Request {
+	var moduleList fapp.FybrikModuleList
+	if err := r.List(context.Background(), &moduleList, client.InNamespace(environment.GetAdminCRsNamespace())); err != nil {
+		r.Log.Error().Err(err).Msg("Error while listing modules")
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for ind := range moduleList.Items {
+		if moduleList.Items[ind].Spec.Deprecated {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&moduleList.Items[ind])})
+		}
+	}
+	return requests
+}
+
+// validModules returns the modules in the given namespace that have not failed validation, mapped by their name
+func (r *FybrikModuleReconciler) validModules(ctx context.Context, namespace string) (map[string]*fapp.FybrikModule, error) {
+	var moduleList fapp.FybrikModuleList
+	if err := r.List(ctx, &moduleList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	moduleMap := make(map[string]*fapp.FybrikModule)
+	for ind := range moduleList.Items {
+		module := &moduleList.Items[ind]
+		if len(module.Status.Conditions) > ModuleValidationConditionIndex &&
+			module.Status.Conditions[ModuleValidationConditionIndex].Status == corev1.ConditionFalse {
+			continue
+		}
+		moduleMap[module.Name] = module
+	}
+	return moduleMap, nil
+}
+
+// dependentModules returns reconcile requests for the modules that depend on the given module,
+// so that their dependencies are resolved again when the module is changed
+func (r *FybrikModuleReconciler) dependentModules(obj client.Object) []reconcile.Request {
+	var moduleList fapp.FybrikModuleList
+	if err := r.List(context.Background(), &moduleList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error().Err(err).Msg("Error while listing modules")
+		return []reconcile.Request{}
+	}
+	// a dependency refers either to the FybrikModule or to its module name
+	names := []string{obj.GetName()}
+	if changed, ok := obj.(*fapp.FybrikModule); ok {
+		names = append(names, changed.GetModuleName())
+	}
+	requests := []reconcile.Request{}
+	for ind := range moduleList.Items {
+		module := &moduleList.Items[ind]
+		if module.Name != obj.GetName() && dependsOnAny(ModuleDependencies(module, nil), names) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		}
+	}
+	return requests
+}
+
+func dependsOnAny(dependencies, names []string) bool {
+	for _, name := range names {
+		if pkgutils.HasString(name, dependencies) {
+			return true
+		}
 	}
-	return ctrl.Result{}, nil
+	return false
 }
 
 // NewFybrikModuleReconciler creates a new reconciler for FybrikModules
@@ -103,33 +244,14 @@ func NewFybrikModuleReconciler(mgr ctrl.Manager, name string) *FybrikModuleRecon
 }
 
 func ValidateFybrikModule(module *fapp.FybrikModule, taxonomyFile string) error {
-	var allErrs []*field.Error
-
-	// Convert Fybrik module Go struct to JSON
-	moduleJSON, err := json.Marshal(&module.Spec)
-	if err != nil {
-		return err
-	}
-
-	// Validate Fybrik module against taxonomy
-	allErrs, err = validate.TaxonomyCheck(moduleJSON, taxonomyFile)
-	if err != nil {
-		return err
-	}
-
-	// Return any error
-	if len(allErrs) == 0 {
-		return nil
-	}
-
-	return apierrors.NewInvalid(
-		schema.GroupKind{Group: "app.fybrik.io", Kind: FybrikModuleKind},
-		module.Name, allErrs)
+	return module.ValidateFybrikModule(taxonomyFile)
 }
 
 // SetupWithManager registers Module controller
 func (r *FybrikModuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&fapp.FybrikModule{}).
+		Watches(&source.Kind{Type: &fapp.FybrikModule{}}, handler.EnqueueRequestsFromMapFunc(r.dependentModules)).
+		Watches(&source.Kind{Type: &fapp.FybrikApplication{}}, handler.EnqueueRequestsFromMapFunc(r.deprecatedModules)).
 		Complete(r)
 }
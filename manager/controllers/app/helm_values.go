@@ -18,4 +18,14 @@ type HelmValues struct {
 	Labels map[string]string `json:"labels"`
 	// Application unique identifier
 	UUID string `json:"uuid"`
+	// Service account of the module release, set if the credentials are delivered by the credential broker
+	ServiceAccount *ServiceAccountValues `json:"serviceAccount,omitempty"`
+}
+
+// ServiceAccountValues select an existing service account for the module release
+type ServiceAccountValues struct {
+	// Create is false since the service account is created by the manager
+	Create bool `json:"create"`
+	// Name of the service account
+	Name string `json:"name"`
 }
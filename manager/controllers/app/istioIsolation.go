@@ -0,0 +1,290 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlutil "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+// Backends of the network isolation of the modules
+const (
+	NetworkPolicyBackend = "networkpolicy"
+	IstioBackend         = "istio"
+)
+
+const (
+	IstioSecurityGroup        = "security.istio.io"
+	IstioSecurityVersion      = "v1beta1"
+	AuthorizationPolicyKind   = "AuthorizationPolicy"
+	PeerAuthenticationKind    = "PeerAuthentication"
+	UnsupportedNPBackendError = "unsupported network isolation backend %s"
+)
+
+// defaultServiceAccount is the service account of the pods that do not specify one
+const defaultServiceAccount = "default"
+
+// createIsolationPolicies creates the resources that isolate the module release according to the configured backend
+func (r *BlueprintReconciler) createIsolationPolicies(ctx context.Context,
+	releaseName string, network *fapp.ModuleNetwork, blueprint *fapp.Blueprint, log *zerolog.Logger) error {
+	switch environment.GetNPBackend() {
+	case NetworkPolicyBackend:
+		return r.createNetworkPolicies(ctx, releaseName, network, blueprint, log)
+	case IstioBackend:
+		return r.createIstioPolicies(ctx, releaseName, network, blueprint, log)
+	default:
+		return errors.Errorf(UnsupportedNPBackendError, environment.GetNPBackend())
+	}
+}
+
+// cleanupIsolationPolicies deletes the isolation resources of the blueprint modules
+func (r *BlueprintReconciler) cleanupIsolationPolicies(ctx context.Context, blueprint *fapp.Blueprint) error {
+	switch environment.GetNPBackend() {
+	case NetworkPolicyBackend:
+		return r.cleanupNetworkPolicies(ctx, blueprint)
+	case IstioBackend:
+		return r.cleanupIstioPolicies(ctx, blueprint)
+	default:
+		return errors.Errorf(UnsupportedNPBackendError, environment.GetNPBackend())
+	}
+}
+
+// createIstioPolicies creates an AuthorizationPolicy that allows only the user workload and the upstream modules
+// to reach the module release, and a PeerAuthentication that enforces mutual TLS, so that the workload identities
+// used by the AuthorizationPolicy are verified.
+func (r *BlueprintReconciler) createIstioPolicies(ctx context.Context,
+	releaseName string, network *fapp.ModuleNetwork, blueprint *fapp.Blueprint, logger *zerolog.Logger) error {
+	log := logger.With().Str(managerUtils.KubernetesInstance, releaseName).Logger()
+	log.Trace().Str(logging.ACTION, logging.CREATE).Msg("Creating Istio policies for " + releaseName)
+
+	sources, err := r.createIstioSources(ctx, network, blueprint, &log)
+	if err != nil {
+		return err
+	}
+	labels := managerUtils.CopyFybrikLabels(blueprint.Labels)
+	selector := map[string]interface{}{
+		"matchLabels": map[string]interface{}{managerUtils.KubernetesInstance: releaseName},
+	}
+	// an ALLOW policy without rules denies all requests
+	rules := []interface{}{}
+	if len(sources) > 0 {
+		rules = append(rules, map[string]interface{}{"from": sources})
+	}
+	authorizationPolicy := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, AuthorizationPolicyKind,
+		releaseName, blueprint.Spec.ModulesNamespace)
+	authorizationSpec := map[string]interface{}{
+		"selector": selector,
+		"action":   "ALLOW",
+		"rules":    rules,
+	}
+	if err = r.createOrUpdateIstioResource(ctx, authorizationPolicy, labels, authorizationSpec, &log); err != nil {
+		return err
+	}
+	peerAuthentication := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, PeerAuthenticationKind,
+		releaseName, blueprint.Spec.ModulesNamespace)
+	peerAuthenticationSpec := map[string]interface{}{
+		"selector": selector,
+		"mtls":     map[string]interface{}{"mode": "STRICT"},
+	}
+	return r.createOrUpdateIstioResource(ctx, peerAuthentication, labels, peerAuthenticationSpec, &log)
+}
+
+func (r *BlueprintReconciler) createOrUpdateIstioResource(ctx context.Context, obj *unstructured.Unstructured,
+	labels map[string]string, spec map[string]interface{}, log *zerolog.Logger) error {
+	res, err := ctrlutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.SetLabels(labels)
+		obj.Object["spec"] = spec
+		return nil
+	})
+	if err != nil {
+		return errors.WithMessagef(err, "failed to create %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	log.Trace().Str(logging.ACTION, logging.CREATE).Msgf("%s %s/%s was createdOrUpdated result: %s",
+		obj.GetKind(), obj.GetNamespace(), obj.GetName(), res)
+	return nil
+}
+
+// refreshIsolationPolicies updates the isolation resources of a deployed module release.
+// The upstream modules of a module are deployed after it, and the Istio sources identify them by the service accounts
+// of their workloads, so the sources are complete only once the upstream modules are deployed.
+func (r *BlueprintReconciler) refreshIsolationPolicies(ctx context.Context,
+	releaseName string, network *fapp.ModuleNetwork, blueprint *fapp.Blueprint, log *zerolog.Logger) error {
+	if !environment.IsNPEnabled() || environment.GetNPBackend() != IstioBackend || len(network.Ingress) == 0 {
+		return nil
+	}
+	return r.createIstioPolicies(ctx, releaseName, network, blueprint, log)
+}
+
+// createIstioSources returns the sources that are allowed to reach the module.
+// The user workload is identified by its service accounts, or by its namespaces if no service accounts are given.
+// The upstream modules in the cluster are identified by the service accounts of their workloads, and the upstream
+// modules in other clusters by the IP blocks of the gateways of their clusters.
+func (r *BlueprintReconciler) createIstioSources(ctx context.Context, network *fapp.ModuleNetwork, blueprint *fapp.Blueprint,
+	log *zerolog.Logger) ([]interface{}, error) {
+	sources := []interface{}{}
+	if network.Endpoint {
+		application := blueprint.Spec.Application
+		if application == nil {
+			err := errors.New(NilApplicationDetailsError)
+			log.Error().Err(err).Send()
+			return nil, err
+		}
+		if len(application.ServiceAccounts) == 0 && len(application.IPBlocks) == 0 && len(application.Namespaces) == 0 &&
+			application.WorkloadSelector.Size() == 0 {
+			err := errors.New(EmptyApplicationDetailsError)
+			log.Error().Err(err).Send()
+			return nil, err
+		}
+		switch {
+		case len(application.ServiceAccounts) > 0:
+			principals := []interface{}{}
+			for _, sa := range application.ServiceAccounts {
+				namespace, name := splitNamespacedName(sa, managerUtils.GetApplicationNamespaceFromLabels(blueprint.Labels))
+				principals = append(principals, istioPrincipal(namespace, name))
+			}
+			sources = append(sources, istioSource("principals", principals))
+		case len(application.Namespaces) > 0:
+			namespaces := []interface{}{}
+			for _, ns := range application.Namespaces {
+				namespaces = append(namespaces, ns)
+			}
+			sources = append(sources, istioSource("namespaces", namespaces))
+		case application.WorkloadSelector.Size() > 0:
+			// workload labels can not be used as a source, the workload is expected to run in the application namespace
+			log.Warn().Str(logging.ACTION, logging.CREATE).Msg("No service accounts are defined for the application, " +
+				"allowing access from the application namespace")
+			sources = append(sources, istioSource("namespaces",
+				[]interface{}{managerUtils.GetApplicationNamespaceFromLabels(blueprint.Labels)}))
+		}
+		if len(application.IPBlocks) > 0 {
+			ipBlocks := []interface{}{}
+			for _, block := range application.IPBlocks {
+				ipBlocks = append(ipBlocks, block.CIDR)
+			}
+			sources = append(sources, istioSource("ipBlocks", ipBlocks))
+		}
+	}
+	principals := []interface{}{}
+	ipBlocks := []interface{}{}
+	for _, ingress := range network.Ingress {
+		if ingress.Cluster != "" && ingress.Cluster != blueprint.Spec.Cluster {
+			// a module in another cluster connects through the egress gateways of its cluster
+			if len(ingress.IPBlocks) == 0 {
+				log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("No gateway IP blocks are defined for cluster %s, ingress from %s is not allowed",
+					ingress.Cluster, ingress.Release)
+			}
+			for _, block := range ingress.IPBlocks {
+				ipBlocks = append(ipBlocks, block)
+			}
+			continue
+		}
+		if ingress.Release == "" {
+			log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("Ingress has empty release %v", ingress)
+			continue
+		}
+		serviceAccounts, err := r.releaseServiceAccounts(ctx, blueprint.Spec.ModulesNamespace, ingress.Release)
+		if err != nil {
+			return nil, err
+		}
+		if len(serviceAccounts) == 0 {
+			// the policy is refreshed once the upstream module is deployed
+			log.Debug().Str(logging.ACTION, logging.CREATE).Msgf("Release %s has no workloads yet", ingress.Release)
+		}
+		for _, sa := range serviceAccounts {
+			principals = append(principals, istioPrincipal(blueprint.Spec.ModulesNamespace, sa))
+		}
+	}
+	if len(principals) > 0 {
+		sources = append(sources, istioSource("principals", principals))
+	}
+	if len(ipBlocks) > 0 {
+		sources = append(sources, istioSource("ipBlocks", ipBlocks))
+	}
+	return sources, nil
+}
+
+// workloadServiceAccountPaths are the paths of the service account names in the workload kinds of module releases
+var workloadServiceAccountPaths = map[schema.GroupVersionKind][]string{
+	appsv1.SchemeGroupVersion.WithKind("Deployment"):  {"spec", "template", "spec", "serviceAccountName"},
+	appsv1.SchemeGroupVersion.WithKind("StatefulSet"): {"spec", "template", "spec", "serviceAccountName"},
+	appsv1.SchemeGroupVersion.WithKind("DaemonSet"):   {"spec", "template", "spec", "serviceAccountName"},
+	batchv1.SchemeGroupVersion.WithKind("Job"):        {"spec", "template", "spec", "serviceAccountName"},
+	batchv1.SchemeGroupVersion.WithKind("CronJob"):    {"spec", "jobTemplate", "spec", "template", "spec", "serviceAccountName"},
+}
+
+// releaseServiceAccounts returns the sorted names of the service accounts of the workloads of a module release
+func (r *BlueprintReconciler) releaseServiceAccounts(ctx context.Context, namespace, releaseName string) ([]string, error) {
+	serviceAccounts := map[string]bool{}
+	for gvk, path := range workloadServiceAccountPaths {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.Client.List(ctx, list, client.InNamespace(namespace),
+			client.MatchingLabels{managerUtils.KubernetesInstance: releaseName}); err != nil {
+			return nil, errors.WithMessagef(err, "failed to list the %s workloads of release %s", gvk.Kind, releaseName)
+		}
+		for i := range list.Items {
+			name, _, _ := unstructured.NestedString(list.Items[i].Object, path...)
+			if name == "" {
+				name = defaultServiceAccount
+			}
+			serviceAccounts[name] = true
+		}
+	}
+	names := make([]string, 0, len(serviceAccounts))
+	for name := range serviceAccounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// splitNamespacedName returns the namespace and the name of an object given as namespace/name or name
+func splitNamespacedName(value, defaultNamespace string) (namespace, name string) {
+	if ns, n, found := strings.Cut(value, "/"); found {
+		return ns, n
+	}
+	return defaultNamespace, value
+}
+
+func istioSource(field string, values []interface{}) interface{} {
+	return map[string]interface{}{"source": map[string]interface{}{field: values}}
+}
+
+func istioPrincipal(namespace, serviceAccount string) string {
+	return fmt.Sprintf("%s/ns/%s/sa/%s", environment.GetIstioTrustDomain(), namespace, serviceAccount)
+}
+
+func (r *BlueprintReconciler) cleanupIstioPolicies(ctx context.Context, blueprint *fapp.Blueprint) error {
+	l := client.MatchingLabels{}
+	l[managerUtils.ApplicationNameLabel] = blueprint.Labels[managerUtils.ApplicationNameLabel]
+	l[managerUtils.ApplicationNamespaceLabel] = blueprint.Labels[managerUtils.ApplicationNamespaceLabel]
+	l[managerUtils.BlueprintNameLabel] = blueprint.Name
+	l[managerUtils.BlueprintNamespaceLabel] = blueprint.Namespace
+	r.Log.Trace().Str(logging.ACTION, logging.DELETE).Msgf("Delete Istio policies with labels %v", l)
+	for _, kind := range []string{AuthorizationPolicyKind, PeerAuthenticationKind} {
+		obj := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, kind, "", "")
+		if err := r.Client.DeleteAllOf(ctx, obj, client.InNamespace(environment.GetDefaultModulesNamespace()), l); err != nil {
+			r.Log.Error().Err(err).Msg("Error while deleting " + kind)
+			return err
+		}
+	}
+	r.Log.Trace().Str(logging.ACTION, logging.DELETE).Msg("Istio policies were deleted")
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+)
+
+func createIstioTestBlueprint() *fapp.Blueprint {
+	return &fapp.Blueprint{
+		ObjectMeta: meta.ObjectMeta{
+			Name:      "my-blueprint",
+			Namespace: "fybrik-system",
+			Labels: map[string]string{
+				managerUtils.ApplicationNameLabel:      "my-app",
+				managerUtils.ApplicationNamespaceLabel: "my-ns",
+			},
+		},
+		Spec: fapp.BlueprintSpec{
+			Cluster:          myCluster,
+			ModulesNamespace: modulesNamespace,
+			Application: &fapp.ApplicationDetails{
+				ServiceAccounts: []string{"my-ns/my-sa", "other-sa"},
+				IPBlocks:        []*netv1.IPBlock{{CIDR: "10.0.0.0/16"}},
+			},
+		},
+	}
+}
+
+// This test checks the Istio policies of a module that serves the user workload and is read by other modules
+func TestCreateIstioPolicies(t *testing.T) {
+	g := gomega.NewWithT(t)
+	s := managerUtils.NewScheme(g)
+	r := createTestFybrikBlueprintController(s)
+	blueprint := createIstioTestBlueprint()
+	// the upstream module in the cluster runs with a service account that is not named after its release
+	upstream := &appsv1.Deployment{
+		ObjectMeta: meta.ObjectMeta{Name: "reader", Namespace: modulesNamespace,
+			Labels: map[string]string{managerUtils.KubernetesInstance: "my-release-000"}},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{ServiceAccountName: "reader-sa"}}},
+	}
+	g.Expect(r.Create(context.Background(), upstream)).To(gomega.Succeed())
+	network := &fapp.ModuleNetwork{
+		Endpoint: true,
+		Ingress: []fapp.ModuleDeployment{
+			{Cluster: myCluster, Release: "my-release-000"},
+			{Cluster: myCluster + "-remote", Release: "my-release-111", IPBlocks: []string{"192.168.0.0/24"}},
+			// an upstream module that is not deployed yet
+			{Cluster: myCluster, Release: "my-release-444"},
+		},
+	}
+	g.Expect(r.createIstioPolicies(context.Background(), "my-release-222", network, blueprint, &r.Log)).To(gomega.Succeed())
+
+	policy := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, AuthorizationPolicyKind, "", "")
+	g.Expect(r.Get(context.Background(), client.ObjectKey{Name: "my-release-222", Namespace: modulesNamespace}, policy)).
+		To(gomega.Succeed())
+	g.Expect(policy.GetLabels()).To(gomega.HaveKeyWithValue(managerUtils.ApplicationNameLabel, "my-app"))
+	selector, _, _ := unstructured.NestedStringMap(policy.Object, "spec", "selector", "matchLabels")
+	g.Expect(selector).To(gomega.Equal(map[string]string{managerUtils.KubernetesInstance: "my-release-222"}))
+	rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", "rules")
+	g.Expect(rules).To(gomega.HaveLen(1))
+	from, _, _ := unstructured.NestedSlice(rules[0].(map[string]interface{}), "from")
+	g.Expect(from).To(gomega.ConsistOf(
+		map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{
+			"cluster.local/ns/my-ns/sa/my-sa", "cluster.local/ns/my-ns/sa/other-sa"}}},
+		map[string]interface{}{"source": map[string]interface{}{"ipBlocks": []interface{}{"10.0.0.0/16"}}},
+		map[string]interface{}{"source": map[string]interface{}{"principals": []interface{}{
+			"cluster.local/ns/" + modulesNamespace + "/sa/reader-sa"}}},
+		map[string]interface{}{"source": map[string]interface{}{"ipBlocks": []interface{}{"192.168.0.0/24"}}},
+	))
+
+	peerAuthentication := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, PeerAuthenticationKind, "", "")
+	g.Expect(r.Get(context.Background(), client.ObjectKey{Name: "my-release-222", Namespace: modulesNamespace},
+		peerAuthentication)).To(gomega.Succeed())
+	mode, _, _ := unstructured.NestedString(peerAuthentication.Object, "spec", "mtls", "mode")
+	g.Expect(mode).To(gomega.Equal("STRICT"))
+}
+
+// This test checks that a module without allowed sources denies all requests
+func TestCreateIstioPoliciesDenyAll(t *testing.T) {
+	g := gomega.NewWithT(t)
+	s := managerUtils.NewScheme(g)
+	r := createTestFybrikBlueprintController(s)
+	blueprint := createIstioTestBlueprint()
+	g.Expect(r.createIstioPolicies(context.Background(), "my-release-333", &fapp.ModuleNetwork{}, blueprint, &r.Log)).
+		To(gomega.Succeed())
+
+	policy := managerUtils.CreateUnstructured(IstioSecurityGroup, IstioSecurityVersion, AuthorizationPolicyKind, "", "")
+	g.Expect(r.Get(context.Background(), client.ObjectKey{Name: "my-release-333", Namespace: modulesNamespace}, policy)).
+		To(gomega.Succeed())
+	action, _, _ := unstructured.NestedString(policy.Object, "spec", "action")
+	g.Expect(action).To(gomega.Equal("ALLOW"))
+	rules, _, _ := unstructured.NestedSlice(policy.Object, "spec", "rules")
+	g.Expect(rules).To(gomega.BeEmpty())
+
+	// the user workload must be identified when the module is an endpoint
+	blueprint.Spec.Application = &fapp.ApplicationDetails{}
+	err := r.createIstioPolicies(context.Background(), "my-release-333", &fapp.ModuleNetwork{Endpoint: true}, blueprint, &r.Log)
+	g.Expect(err).Should(gomega.MatchError(EmptyApplicationDetailsError))
+}
+
+func TestQualifyServiceAccounts(t *testing.T) {
+	g := gomega.NewWithT(t)
+	g.Expect(qualifyServiceAccounts([]string{"ns1/sa1", "sa2"}, "app-ns")).To(gomega.Equal([]string{"ns1/sa1", "app-ns/sa2"}))
+	g.Expect(qualifyServiceAccounts(nil, "app-ns")).To(gomega.BeNil())
+}
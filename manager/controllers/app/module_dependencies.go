@@ -0,0 +1,192 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// Connectors that a module can depend on
+const (
+	DataCatalogConnectorDependency    = "datacatalog"
+	PolicyManagerConnectorDependency  = "policymanager"
+	StorageManagerConnectorDependency = "storagemanager"
+)
+
+// Control plane features that a module can depend on
+const (
+	VaultFeature            = "vault"
+	NetworkIsolationFeature = "networkIsolation"
+	IstioFeature            = "istio"
+	OptimizerFeature        = "optimizer"
+)
+
+const DependencyCycleError = "dependency cycle"
+
+// availableConnectors returns the connectors that are configured in the control plane
+func availableConnectors() map[string]bool {
+	return map[string]bool{
+		DataCatalogConnectorDependency:    environment.GetDataCatalogServiceAddress() != "",
+		PolicyManagerConnectorDependency:  os.Getenv(environment.MainPolicyManagerConnectorURLKey) != "",
+		StorageManagerConnectorDependency: environment.GetStorageManagerAddress() != "",
+	}
+}
+
+// availableFeatures returns the optional control plane features that are enabled
+func availableFeatures() map[string]bool {
+	return map[string]bool{
+		VaultFeature:            environment.IsVaultEnabled(),
+		NetworkIsolationFeature: environment.IsNPEnabled(),
+		IstioFeature:            environment.IsNPEnabled() && environment.GetNPBackend() == IstioBackend,
+		OptimizerFeature:        environment.UseCSP(),
+	}
+}
+
+// GetDependencies returns the modules that the given module depends on, directly or indirectly, in topological order:
+// every module appears after the modules it depends on. Connector and feature dependencies are checked against the
+// control plane configuration. An error is returned if a dependency is not available or if the dependencies form a cycle.
+func GetDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.FybrikModule) ([]*fapp.FybrikModule, error) {
+	resolver := &dependencyResolver{
+		moduleMap:  moduleMap,
+		connectors: availableConnectors(),
+		features:   availableFeatures(),
+		visiting:   map[string]bool{},
+		resolved:   map[string]bool{},
+	}
+	if err := resolver.visit(module); err != nil {
+		return nil, err
+	}
+	// the module itself is the last one
+	return resolver.sorted[:len(resolver.sorted)-1], nil
+}
+
+// ModuleDependencies returns the names of the FybrikModules that the given module directly depends on.
+// A dependency that refers to a module name rather than to a FybrikModule is resolved against the given module map.
+func ModuleDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.FybrikModule) []string {
+	var names []string
+	for _, dependency := range module.Spec.Dependencies {
+		if dependency.Type != fapp.Module {
+			continue
+		}
+		if dependencyModule, found := findModule(dependency.Name, moduleMap); found {
+			names = append(names, dependencyModule.Name)
+		} else {
+			names = append(names, dependency.Name)
+		}
+	}
+	return names
+}
+
+// findModule returns the FybrikModule with the given name or, if there is none,
+// the latest version among the FybrikModules with the given module name
+func findModule(name string, moduleMap map[string]*fapp.FybrikModule) (*fapp.FybrikModule, bool) {
+	if module, found := moduleMap[name]; found {
+		return module, true
+	}
+	var latest *fapp.FybrikModule
+	for _, module := range moduleMap {
+		if module.GetModuleName() != name {
+			continue
+		}
+		if latest == nil || compareModuleVersions(module, latest) > 0 ||
+			(compareModuleVersions(module, latest) == 0 && module.Name > latest.Name) {
+			latest = module
+		}
+	}
+	return latest, latest != nil
+}
+
+// dependencyResolver sorts the module dependencies with a depth-first search
+type dependencyResolver struct {
+	moduleMap  map[string]*fapp.FybrikModule
+	connectors map[string]bool
+	features   map[string]bool
+	// path holds the modules that are being visited, from the module whose dependencies are resolved
+	path     []string
+	visiting map[string]bool
+	resolved map[string]bool
+	sorted   []*fapp.FybrikModule
+}
+
+func (r *dependencyResolver) visit(module *fapp.FybrikModule) error {
+	if r.resolved[module.Name] {
+		return nil
+	}
+	if r.visiting[module.Name] {
+		cycle := []string{}
+		for i, name := range r.path {
+			if name == module.Name {
+				cycle = append(cycle, r.path[i:]...)
+				break
+			}
+		}
+		cycle = append(cycle, module.Name)
+		return errors.Errorf("%s: %s", DependencyCycleError, strings.Join(cycle, " -> "))
+	}
+	r.visiting[module.Name] = true
+	r.path = append(r.path, module.Name)
+	for _, dependency := range module.Spec.Dependencies {
+		switch dependency.Type {
+		case fapp.Module:
+			dependencyModule, found := findModule(dependency.Name, r.moduleMap)
+			if !found {
+				return errors.Errorf("module %s depends on module %s, which does not exist or is not valid",
+					module.Name, dependency.Name)
+			}
+			if err := r.visit(dependencyModule); err != nil {
+				return err
+			}
+		case fapp.Connector:
+			if !r.connectors[dependency.Name] {
+				return errors.Errorf("module %s depends on connector %s, which is not configured", module.Name, dependency.Name)
+			}
+		case fapp.Feature:
+			if !r.features[dependency.Name] {
+				return errors.Errorf("module %s depends on feature %s, which is not enabled", module.Name, dependency.Name)
+			}
+		}
+	}
+	r.path = r.path[:len(r.path)-1]
+	delete(r.visiting, module.Name)
+	r.resolved[module.Name] = true
+	r.sorted = append(r.sorted, module)
+	return nil
+}
+
+// sortModuleInstances returns the instance names of the blueprint modules in topological order:
+// every instance appears after the instances it depends on. Instances without dependencies are sorted by name.
+func sortModuleInstances(modules map[string]fapp.BlueprintModule) []string {
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]string, 0, len(modules))
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		// dependencies are acyclic by construction, marking the instance first guards against a cycle anyway
+		visited[name] = true
+		for _, dependency := range modules[name].DependsOn {
+			if _, found := modules[dependency]; found {
+				visit(dependency)
+			}
+		}
+		sorted = append(sorted, name)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return sorted
+}
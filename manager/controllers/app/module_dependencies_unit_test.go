@@ -0,0 +1,178 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/datapath"
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+func newDependentModule(name string, dependencies ...fapp.Dependency) *fapp.FybrikModule {
+	return &fapp.FybrikModule{
+		ObjectMeta: meta.ObjectMeta{Name: name, Namespace: environment.GetAdminCRsNamespace()},
+		Spec: fapp.FybrikModuleSpec{
+			Type:         "service",
+			Chart:        fapp.ChartSpec{Name: "registry/" + name + ":0.1.0"},
+			Dependencies: dependencies,
+			Capabilities: []fapp.ModuleCapability{{Capability: "read", Scope: fapp.Workload}},
+		},
+	}
+}
+
+func moduleDependency(name string) fapp.Dependency {
+	return fapp.Dependency{Type: fapp.Module, Name: name}
+}
+
+func moduleNames(modules []*fapp.FybrikModule) []string {
+	names := []string{}
+	for _, module := range modules {
+		names = append(names, module.Name)
+	}
+	return names
+}
+
+func TestGetDependenciesOrder(t *testing.T) {
+	g := gomega.NewWithT(t)
+	moduleMap := map[string]*fapp.FybrikModule{
+		"a": newDependentModule("a", moduleDependency("b"), moduleDependency("c")),
+		"b": newDependentModule("b", moduleDependency("d")),
+		"c": newDependentModule("c", moduleDependency("d")),
+		"d": newDependentModule("d"),
+	}
+	dependencies, err := GetDependencies(moduleMap["a"], moduleMap)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	// every module follows its dependencies, and shared dependencies appear once
+	g.Expect(moduleNames(dependencies)).To(gomega.Equal([]string{"d", "b", "c"}))
+
+	dependencies, err = GetDependencies(moduleMap["d"], moduleMap)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(dependencies).To(gomega.BeEmpty())
+}
+
+func TestGetDependenciesErrors(t *testing.T) {
+	g := gomega.NewWithT(t)
+	moduleMap := map[string]*fapp.FybrikModule{
+		"a": newDependentModule("a", moduleDependency("b")),
+		"b": newDependentModule("b", moduleDependency("c")),
+		"c": newDependentModule("c", moduleDependency("a")),
+		"d": newDependentModule("d", moduleDependency("missing")),
+		"e": newDependentModule("e", fapp.Dependency{Type: fapp.Feature, Name: VaultFeature}),
+		"f": newDependentModule("f", fapp.Dependency{Type: fapp.Connector, Name: DataCatalogConnectorDependency}),
+	}
+	_, err := GetDependencies(moduleMap["a"], moduleMap)
+	g.Expect(err).To(gomega.MatchError(DependencyCycleError + ": a -> b -> c -> a"))
+
+	_, err = GetDependencies(moduleMap["d"], moduleMap)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("module missing")))
+
+	t.Setenv(environment.VaultEnabledKey, "false")
+	_, err = GetDependencies(moduleMap["e"], moduleMap)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("feature vault")))
+	t.Setenv(environment.VaultEnabledKey, "true")
+	_, err = GetDependencies(moduleMap["e"], moduleMap)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	t.Setenv(environment.CatalogConnectorServiceAddressKey, "")
+	_, err = GetDependencies(moduleMap["f"], moduleMap)
+	g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("connector datacatalog")))
+	t.Setenv(environment.CatalogConnectorServiceAddressKey, "http://localhost:8080")
+	_, err = GetDependencies(moduleMap["f"], moduleMap)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+}
+
+func TestSortModuleInstances(t *testing.T) {
+	g := gomega.NewWithT(t)
+	modules := map[string]fapp.BlueprintModule{
+		"reader-123": {Name: "reader", DependsOn: []string{"cache", "proxy"}},
+		"proxy":      {Name: "proxy", DependsOn: []string{"cache"}},
+		"cache":      {Name: "cache"},
+		"another":    {Name: "another", DependsOn: []string{"not-in-blueprint"}},
+	}
+	g.Expect(sortModuleInstances(modules)).To(gomega.Equal([]string{"another", "cache", "proxy", "reader-123"}))
+}
+
+// This test checks that a selected module is the first module of its template, and that it is followed by the modules
+// it depends on, where every dependency comes after the dependencies of its own
+func TestAddTemplateWithDependencies(t *testing.T) {
+	g := gomega.NewWithT(t)
+	moduleMap := map[string]*fapp.FybrikModule{
+		"reader": newDependentModule("reader", moduleDependency("proxy")),
+		"proxy":  newDependentModule("proxy", moduleDependency("cache")),
+		"cache":  newDependentModule("cache"),
+	}
+	moduleMap["reader"].Spec.Capabilities[0].Scope = fapp.Asset
+	p := &PlotterGenerator{Modules: moduleMap}
+	plotterSpec := &fapp.PlotterSpec{Templates: map[string]fapp.Template{}}
+	element := &datapath.ResolvedEdge{Edge: datapath.Edge{Module: moduleMap["reader"], CapabilityIndex: 0}}
+	g.Expect(p.addTemplate(element, plotterSpec, "reader-read")).To(gomega.Succeed())
+
+	modules := plotterSpec.Templates["reader-read"].Modules
+	names := []string{}
+	for indx := range modules {
+		names = append(names, modules[indx].Name)
+	}
+	g.Expect(names).To(gomega.Equal([]string{"reader", "cache", "proxy"}))
+	g.Expect(modules[0].Name).To(gomega.Equal("reader"))
+	g.Expect(modules[0].Scope).To(gomega.Equal(fapp.Asset))
+	g.Expect(modules[0].Dependencies).To(gomega.Equal([]string{"proxy"}))
+	g.Expect(modules[1].Name).To(gomega.Equal("cache"))
+	g.Expect(modules[2].Name).To(gomega.Equal("proxy"))
+	g.Expect(modules[2].Scope).To(gomega.Equal(fapp.Workload))
+	g.Expect(modules[2].Dependencies).To(gomega.Equal([]string{"cache"}))
+
+	dependencies := templateDependencies(&fapp.Template{Modules: modules})
+	g.Expect(dependencies).To(gomega.HaveLen(2))
+	g.Expect(dependencies).To(gomega.HaveKey("cache"))
+	g.Expect(dependencies).To(gomega.HaveKey("proxy"))
+
+	moduleMap["cache"].Spec.Dependencies = []fapp.Dependency{moduleDependency("reader")}
+	g.Expect(p.addTemplate(element, plotterSpec, "reader-read")).ToNot(gomega.Succeed())
+}
+
+// This test checks the dependencies condition of the FybrikModule status
+func TestFybrikModuleDependenciesCondition(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("ENABLE_WEBHOOKS", "true")
+	a := newDependentModule("module-a", moduleDependency("module-b"))
+	b := newDependentModule("module-b", moduleDependency("module-a"))
+	c := newDependentModule("module-c", moduleDependency("module-d"))
+	s := managerUtils.NewScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(a, b, c).Build()
+	r := createTestFybrikModuleController(cl, s)
+
+	conditionOf := func(module *fapp.FybrikModule) fapp.Condition {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		result := &fapp.FybrikModule{}
+		g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(module), result)).To(gomega.Succeed())
+		g.Expect(result.Status.Conditions).To(gomega.HaveLen(4))
+		g.Expect(result.Status.Conditions[ModuleValidationConditionIndex].Status).To(gomega.Equal(corev1.ConditionTrue))
+		return result.Status.Conditions[ModuleDependenciesConditionIndex]
+	}
+
+	condition := conditionOf(a)
+	g.Expect(condition.Type).To(gomega.Equal(fapp.DependenciesResolvedCondition))
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(condition.Message).To(gomega.Equal(DependencyCycleError + ": module-a -> module-b -> module-a"))
+
+	g.Expect(conditionOf(c).Status).To(gomega.Equal(corev1.ConditionFalse))
+	// the module is reconciled again when its dependency is created
+	d := newDependentModule("module-d")
+	g.Expect(cl.Create(context.Background(), d)).To(gomega.Succeed())
+	g.Expect(r.dependentModules(d)).To(gomega.Equal([]reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(c)}}))
+	g.Expect(conditionOf(c).Status).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(conditionOf(d).Status).To(gomega.Equal(corev1.ConditionTrue))
+}
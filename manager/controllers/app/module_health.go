@@ -0,0 +1,119 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	kstatus "sigs.k8s.io/cli-utils/pkg/kstatus/status"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+)
+
+const (
+	defaultHealthCheckPeriod  = 30 * time.Second
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+// checkHealth sets the readiness condition of the module according to its health check.
+// Modules without a health check are assumed to be ready.
+// It returns the time until the next health check, or zero if the module has no health check.
+func (r *FybrikModuleReconciler) checkHealth(ctx context.Context, module *fapp.FybrikModule) time.Duration {
+	if len(module.Status.Conditions) <= ModuleReadinessConditionIndex {
+		module.Status.Conditions = append(module.Status.Conditions,
+			fapp.Condition{Type: fapp.ReadyCondition, Status: corev1.ConditionUnknown})
+	}
+	condition := &module.Status.Conditions[ModuleReadinessConditionIndex]
+	condition.ObservedGeneration = module.GetGeneration()
+	healthCheck := module.Spec.HealthCheck
+	if healthCheck == nil {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = ""
+		return 0
+	}
+	if err := r.probeModule(ctx, healthCheck); err != nil {
+		if condition.Status != corev1.ConditionFalse {
+			r.Log.Warn().Str(logging.MODULE, module.Name).Err(err).Msg("Fybrik module is not healthy")
+		}
+		condition.Status = corev1.ConditionFalse
+		condition.Message = err.Error()
+	} else {
+		condition.Status = corev1.ConditionTrue
+		condition.Message = ""
+	}
+	if healthCheck.PeriodSeconds > 0 {
+		return time.Duration(healthCheck.PeriodSeconds) * time.Second
+	}
+	return defaultHealthCheckPeriod
+}
+
+// probeModule returns an error if the health endpoint of the module does not respond successfully
+// or if one of its workloads is not ready
+func (r *FybrikModuleReconciler) probeModule(ctx context.Context, healthCheck *fapp.ModuleHealthCheck) error {
+	if healthCheck.URL != "" {
+		timeout := defaultHealthCheckTimeout
+		if healthCheck.TimeoutSeconds > 0 {
+			timeout = time.Duration(healthCheck.TimeoutSeconds) * time.Second
+		}
+		if err := probeURL(ctx, healthCheck.URL, timeout); err != nil {
+			return err
+		}
+	}
+	for i := range healthCheck.Workloads {
+		if err := r.checkWorkload(ctx, &healthCheck.Workloads[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// probeURL sends a GET request to the health endpoint and expects a 2xx status code
+func probeURL(ctx context.Context, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return errors.Wrap(err, "invalid health endpoint "+url)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "health endpoint "+url+" is not reachable")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("health endpoint %s responded with status code %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// checkWorkload computes the status of the referenced workload with kstatus and expects it to be current
+func (r *FybrikModuleReconciler) checkWorkload(ctx context.Context, workload *fapp.WorkloadReference) error {
+	namespace := workload.Namespace
+	if namespace == "" {
+		namespace = environment.GetDefaultModulesNamespace()
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(workload.APIVersion)
+	obj.SetKind(workload.Kind)
+	name := workload.Kind + " " + namespace + "/" + workload.Name
+	if err := r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: workload.Name}, obj); err != nil {
+		return errors.Wrap(err, "could not get workload "+name)
+	}
+	result, err := kstatus.Compute(obj)
+	if err != nil {
+		return errors.Wrap(err, "could not compute the status of workload "+name)
+	}
+	if result.Status != kstatus.CurrentStatus {
+		return errors.Errorf("workload %s is not ready: %s", name, result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// This test checks the readiness condition of modules with a health endpoint
+func TestFybrikModuleHealthEndpoint(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("ENABLE_WEBHOOKS", "true")
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	module := newDependentModule("shared-service")
+	module.Spec.Capabilities[0].Scope = fapp.Cluster
+	module.Spec.HealthCheck = &fapp.ModuleHealthCheck{URL: server.URL, PeriodSeconds: 10}
+	noHealthCheck := newDependentModule("plugin")
+	s := managerUtils.NewScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(module, noHealthCheck).Build()
+	r := createTestFybrikModuleController(cl, s)
+
+	conditionOf := func(module *fapp.FybrikModule, requeueAfter time.Duration) fapp.Condition {
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(result.RequeueAfter).To(gomega.Equal(requeueAfter))
+		reconciled := &fapp.FybrikModule{}
+		g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(module), reconciled)).To(gomega.Succeed())
+		return reconciled.Status.Conditions[ModuleReadinessConditionIndex]
+	}
+
+	condition := conditionOf(module, 10*time.Second)
+	g.Expect(condition.Type).To(gomega.Equal(fapp.ReadyCondition))
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionTrue))
+
+	healthy = false
+	condition = conditionOf(module, 10*time.Second)
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(condition.Message).To(gomega.ContainSubstring("responded with status code 503"))
+
+	// modules without a health check are assumed to be ready and are not checked again
+	g.Expect(conditionOf(noHealthCheck, 0).Status).To(gomega.Equal(corev1.ConditionTrue))
+
+	// unhealthy modules are not used in data paths
+	application := &FybrikApplicationReconciler{Client: cl}
+	modules, err := application.GetAllModules()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(modules).To(gomega.HaveKey(noHealthCheck.Name))
+	g.Expect(modules).ToNot(gomega.HaveKey(module.Name))
+
+	healthy = true
+	g.Expect(conditionOf(module, 10*time.Second).Status).To(gomega.Equal(corev1.ConditionTrue))
+	modules, err = application.GetAllModules()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(modules).To(gomega.HaveKey(module.Name))
+}
+
+// This test checks the readiness condition of modules that refer to their workloads
+func TestFybrikModuleHealthWorkloads(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("ENABLE_WEBHOOKS", "true")
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta.ObjectMeta{Name: "shared-service", Namespace: environment.GetDefaultModulesNamespace(), Generation: 1},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+	}
+	module := newDependentModule("shared-service")
+	module.Spec.HealthCheck = &fapp.ModuleHealthCheck{Workloads: []fapp.WorkloadReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: deployment.Name},
+	}}
+	s := managerUtils.NewScheme(g)
+	g.Expect(appsv1.AddToScheme(s)).To(gomega.Succeed())
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(module, deployment).Build()
+	r := createTestFybrikModuleController(cl, s)
+
+	conditionOf := func() fapp.Condition {
+		result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(result.RequeueAfter).To(gomega.Equal(defaultHealthCheckPeriod))
+		reconciled := &fapp.FybrikModule{}
+		g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(module), reconciled)).To(gomega.Succeed())
+		return reconciled.Status.Conditions[ModuleReadinessConditionIndex]
+	}
+
+	condition := conditionOf()
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(condition.Message).To(gomega.HavePrefix("workload Deployment " + environment.GetDefaultModulesNamespace() +
+		"/shared-service is not ready"))
+
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1,
+		ReadyReplicas: 1, AvailableReplicas: 1, Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable"},
+			{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+		}}
+	g.Expect(cl.Update(context.Background(), deployment)).To(gomega.Succeed())
+	g.Expect(conditionOf().Status).To(gomega.Equal(corev1.ConditionTrue))
+
+	g.Expect(cl.Delete(context.Background(), deployment)).To(gomega.Succeed())
+	condition = conditionOf()
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionFalse))
+	g.Expect(condition.Message).To(gomega.ContainSubstring("could not get workload"))
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/Masterminds/semver/v3"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	"fybrik.io/fybrik/pkg/datapath"
+)
+
+// moduleVersion returns the semantic version of the module, or nil if the module is not versioned
+func moduleVersion(module *fapp.FybrikModule) *semver.Version {
+	if module.Spec.Version == "" {
+		return nil
+	}
+	version, err := semver.NewVersion(module.Spec.Version)
+	if err != nil {
+		return nil
+	}
+	return version
+}
+
+// compareModuleVersions compares the versions of two modules, a module without a version precedes any versioned module
+func compareModuleVersions(a, b *fapp.FybrikModule) int {
+	versionA := moduleVersion(a)
+	versionB := moduleVersion(b)
+	switch {
+	case versionA == nil && versionB == nil:
+		return 0
+	case versionA == nil:
+		return -1
+	case versionB == nil:
+		return 1
+	}
+	return versionA.Compare(versionB)
+}
+
+// isPinned checks whether the constraint is an exact version, that pins the given module version
+func isPinned(constraint string, version *semver.Version) bool {
+	pinned, err := semver.StrictNewVersion(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(constraint), "=")))
+	return err == nil && version != nil && pinned.Equal(version)
+}
+
+// SelectModuleVersions returns the modules whose versions may be used by the application.
+// Module versions must satisfy the version constraints of the application.
+// Versions that the application already uses are kept, so that the application is not rolled forward when it is planned again,
+// while deprecated versions are not used for new deployments unless the application pins them.
+// Modules whose dependencies are not available after the selection are not used.
+func SelectModuleVersions(moduleMap map[string]*fapp.FybrikModule,
+	application *fapp.FybrikApplication) (map[string]*fapp.FybrikModule, error) {
+	versions := map[string][]*fapp.FybrikModule{}
+	for _, module := range moduleMap {
+		versions[module.GetModuleName()] = append(versions[module.GetModuleName()], module)
+	}
+	selected := map[string]*fapp.FybrikModule{}
+	for moduleName, modules := range versions {
+		constraintStr, constrained := application.Spec.ModuleVersions[moduleName]
+		var constraint *semver.Constraints
+		if constrained {
+			var err error
+			if constraint, err = semver.NewConstraint(constraintStr); err != nil {
+				return nil, errors.Wrapf(err, "invalid version constraint for module %s", moduleName)
+			}
+		}
+		allowed := []*fapp.FybrikModule{}
+		inUse := []*fapp.FybrikModule{}
+		for _, module := range modules {
+			version := moduleVersion(module)
+			if constrained && (version == nil || !constraint.Check(version)) {
+				continue
+			}
+			if usedVersion, used := application.Status.Modules[module.Name]; used && usedVersion == module.Spec.Version {
+				inUse = append(inUse, module)
+				continue
+			}
+			if module.Spec.Deprecated && !(constrained && isPinned(constraintStr, version)) {
+				continue
+			}
+			allowed = append(allowed, module)
+		}
+		if len(inUse) > 0 {
+			allowed = inUse
+		}
+		for _, module := range allowed {
+			selected[module.Name] = module
+		}
+	}
+	// removing a module may leave the modules that depend on it unresolved
+	for removed := true; removed; {
+		removed = false
+		for name, module := range selected {
+			if _, err := GetDependencies(module, selected); err != nil {
+				delete(selected, name)
+				removed = true
+			}
+		}
+	}
+	return selected, nil
+}
+
+// UsedModuleVersions returns the FybrikModules used by the plotter mapped to their versions
+func UsedModuleVersions(plotterSpec *fapp.PlotterSpec, moduleMap map[string]*fapp.FybrikModule) map[string]string {
+	used := map[string]string{}
+	for _, template := range plotterSpec.Templates {
+		for _, info := range template.Modules {
+			if module, found := moduleMap[info.Name]; found {
+				used[info.Name] = module.Spec.Version
+			}
+		}
+	}
+	return used
+}
+
+// preferLatestVersions removes the data paths that differ from another data path only by using older versions of the same modules.
+// The remaining data paths keep their order.
+func preferLatestVersions(solutions []datapath.Solution) []datapath.Solution {
+	preferred := []datapath.Solution{}
+	for i := range solutions {
+		superseded := false
+		for j := range solutions {
+			if i != j && supersedes(&solutions[j], &solutions[i]) {
+				superseded = true
+				break
+			}
+		}
+		if !superseded {
+			preferred = append(preferred, solutions[i])
+		}
+	}
+	return preferred
+}
+
+// supersedes checks whether the first data path uses the same modules and capabilities as the second one,
+// with newer versions for some of the modules and older versions for none of them
+func supersedes(first, second *datapath.Solution) bool {
+	if len(first.DataPath) != len(second.DataPath) {
+		return false
+	}
+	newer := false
+	for ind := range first.DataPath {
+		a := first.DataPath[ind]
+		b := second.DataPath[ind]
+		if a.Module.GetModuleName() != b.Module.GetModuleName() ||
+			a.Module.Spec.Capabilities[a.CapabilityIndex].Capability != b.Module.Spec.Capabilities[b.CapabilityIndex].Capability {
+			return false
+		}
+		switch compare := compareModuleVersions(a.Module, b.Module); {
+		case compare < 0:
+			return false
+		case compare > 0:
+			newer = true
+		}
+	}
+	return newer
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/datapath"
+)
+
+func newVersionedModule(moduleName, version string, dependencies ...fapp.Dependency) *fapp.FybrikModule {
+	module := newDependentModule(moduleName+"-"+version, dependencies...)
+	module.Spec.ModuleName = moduleName
+	module.Spec.Version = version
+	return module
+}
+
+func versionedModuleMap(modules ...*fapp.FybrikModule) map[string]*fapp.FybrikModule {
+	moduleMap := map[string]*fapp.FybrikModule{}
+	for _, module := range modules {
+		moduleMap[module.Name] = module
+	}
+	return moduleMap
+}
+
+func sortedKeys(moduleMap map[string]*fapp.FybrikModule) []string {
+	names := []string{}
+	for name := range moduleMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSelectModuleVersions(t *testing.T) {
+	g := gomega.NewWithT(t)
+	moduleMap := versionedModuleMap(
+		newVersionedModule("reader", "1.0.0"),
+		newVersionedModule("reader", "1.1.0"),
+		newVersionedModule("reader", "2.0.0"),
+		newVersionedModule("writer", "0.9.0"),
+		newVersionedModule("writer", "1.0.0"),
+		newDependentModule("legacy"),
+	)
+	moduleMap["writer-0.9.0"].Spec.Deprecated = true
+	application := &fapp.FybrikApplication{}
+
+	selected, err := SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(sortedKeys(selected)).To(gomega.Equal([]string{"legacy", "reader-1.0.0", "reader-1.1.0", "reader-2.0.0", "writer-1.0.0"}))
+
+	// constraints of the application
+	application.Spec.ModuleVersions = map[string]string{"reader": "~1.0", "legacy": ">= 1.0"}
+	selected, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(sortedKeys(selected)).To(gomega.Equal([]string{"reader-1.0.0", "writer-1.0.0"}))
+
+	// a deprecated version is selected if the application pins it
+	application.Spec.ModuleVersions = map[string]string{"writer": "0.9.0"}
+	selected, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(selected).To(gomega.HaveKey("writer-0.9.0"))
+	g.Expect(selected).ToNot(gomega.HaveKey("writer-1.0.0"))
+
+	// the versions in use are kept, even if they are deprecated
+	application.Spec.ModuleVersions = map[string]string{"reader": ">= 1.0"}
+	application.Status.Modules = map[string]string{"reader-1.0.0": "1.0.0", "writer-0.9.0": "0.9.0"}
+	selected, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(sortedKeys(selected)).To(gomega.Equal([]string{"legacy", "reader-1.0.0", "writer-0.9.0"}))
+
+	// the application is rolled forward by changing its constraints
+	application.Spec.ModuleVersions = map[string]string{"reader": ">= 2.0"}
+	selected, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(selected).To(gomega.HaveKey("reader-2.0.0"))
+	g.Expect(selected).ToNot(gomega.HaveKey("reader-1.0.0"))
+
+	application.Spec.ModuleVersions = map[string]string{"reader": "not a constraint"}
+	_, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).To(gomega.HaveOccurred())
+}
+
+func TestSelectModuleVersionsWithDependencies(t *testing.T) {
+	g := gomega.NewWithT(t)
+	moduleMap := versionedModuleMap(
+		newVersionedModule("reader", "1.0.0", moduleDependency("cache")),
+		newVersionedModule("cache", "1.0.0"),
+		newVersionedModule("cache", "2.0.0"),
+	)
+	// a dependency on a module name is resolved to its latest version
+	dependencies, err := GetDependencies(moduleMap["reader-1.0.0"], moduleMap)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(moduleNames(dependencies)).To(gomega.Equal([]string{"cache-2.0.0"}))
+	g.Expect(ModuleDependencies(moduleMap["reader-1.0.0"], moduleMap)).To(gomega.Equal([]string{"cache-2.0.0"}))
+
+	application := &fapp.FybrikApplication{Spec: fapp.FybrikApplicationSpec{ModuleVersions: map[string]string{"cache": "1.0.0"}}}
+	selected, err := SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ModuleDependencies(selected["reader-1.0.0"], selected)).To(gomega.Equal([]string{"cache-1.0.0"}))
+
+	// modules whose dependencies are not selected are not used
+	application.Spec.ModuleVersions = map[string]string{"cache": "3.0.0"}
+	selected, err = SelectModuleVersions(moduleMap, application)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(selected).To(gomega.BeEmpty())
+}
+
+func TestPreferLatestVersions(t *testing.T) {
+	g := gomega.NewWithT(t)
+	oldReader := newVersionedModule("reader", "1.0.0")
+	newReader := newVersionedModule("reader", "1.1.0")
+	copier := newVersionedModule("copier", "1.0.0")
+	copier.Spec.Capabilities[0].Capability = "copy"
+	path := func(modules ...*fapp.FybrikModule) datapath.Solution {
+		solution := datapath.Solution{}
+		for _, module := range modules {
+			solution.DataPath = append(solution.DataPath, &datapath.ResolvedEdge{Edge: datapath.Edge{Module: module}})
+		}
+		return solution
+	}
+	solutions := preferLatestVersions([]datapath.Solution{
+		path(copier, oldReader),
+		path(oldReader),
+		path(newReader),
+		path(copier, newReader),
+	})
+	g.Expect(solutions).To(gomega.HaveLen(2))
+	g.Expect(solutions[0].DataPath[0].Module).To(gomega.Equal(newReader))
+	g.Expect(solutions[1].DataPath[1].Module).To(gomega.Equal(newReader))
+}
+
+// This test checks that a deprecated module version used by applications is marked in the module status
+func TestFybrikModuleDeprecatedInUse(t *testing.T) {
+	g := gomega.NewWithT(t)
+	t.Setenv("ENABLE_WEBHOOKS", "true")
+	module := newVersionedModule("reader", "1.0.0")
+	module.Spec.Deprecated = true
+	application := &fapp.FybrikApplication{
+		ObjectMeta: meta.ObjectMeta{Name: "notebook", Namespace: "default"},
+		Status:     fapp.FybrikApplicationStatus{Modules: map[string]string{module.Name: "1.0.0"}},
+	}
+	s := managerUtils.NewScheme(g)
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(module, application).Build()
+	r := createTestFybrikModuleController(cl, s)
+	g.Expect(r.deprecatedModules(application)).To(gomega.Equal([]reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(module)}}))
+
+	conditionOf := func() fapp.Condition {
+		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		result := &fapp.FybrikModule{}
+		g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(module), result)).To(gomega.Succeed())
+		return result.Status.Conditions[ModuleDeprecationConditionIndex]
+	}
+	condition := conditionOf()
+	g.Expect(condition.Type).To(gomega.Equal(fapp.DeprecatedInUseCondition))
+	g.Expect(condition.Status).To(gomega.Equal(corev1.ConditionTrue))
+	g.Expect(condition.Message).To(gomega.Equal("deprecated version 1.0.0 is used by default/notebook"))
+
+	// the application is rolled forward
+	g.Expect(cl.Get(context.Background(), client.ObjectKeyFromObject(application), application)).To(gomega.Succeed())
+	application.Status.Modules = map[string]string{"reader-1.1.0": "1.1.0"}
+	g.Expect(cl.Update(context.Background(), application)).To(gomega.Succeed())
+	g.Expect(conditionOf().Status).To(gomega.Equal(corev1.ConditionFalse))
+}
@@ -160,9 +160,15 @@ func (r *BlueprintReconciler) createNPIngressRules(endpoint bool, ingresses []fa
 				log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("Ingress has empty release %v", ingress)
 			}
 		} else {
-			// TODO: multi-cluster support
+			// a module in another cluster connects through the egress gateways of its cluster
 			log.Debug().Str(logging.ACTION, logging.CREATE).Msgf("Cross-cluster ingress connectivity, ingress.Cluster %s, blueprint cluster %s",
 				ingress.Cluster, cluster)
+			peers := ipBlockPeers(ingress.IPBlocks, log)
+			if len(peers) == 0 {
+				log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("No gateway IP blocks are defined for cluster %s, ingress from %s is not allowed",
+					ingress.Cluster, ingress.Release)
+			}
+			from = append(from, peers...)
 		}
 	}
 	if len(from) == 0 {
@@ -292,9 +298,28 @@ func (r *BlueprintReconciler) createModuleEgressRules(egresses []fapp.ModuleDepl
 				egressRules = append(egressRules, netv1.NetworkPolicyEgressRule{To: []netv1.NetworkPolicyPeer{to}})
 			}
 		} else {
-			// TODO: multi-cluster support
+			// a module in another cluster is reached through the ingress gateways of its cluster
 			log.Debug().Str(logging.ACTION, logging.CREATE).Msgf("Cross-cluster egress connectivity, egress.Cluster %s, blueprint cluster %s",
 				egress.Cluster, cluster)
+			peers := ipBlockPeers(egress.IPBlocks, log)
+			if len(peers) == 0 {
+				log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("No gateway IP blocks are defined for cluster %s, egress to %s is not allowed",
+					egress.Cluster, egress.Release)
+				continue
+			}
+			npPorts := []netv1.NetworkPolicyPort{}
+			for _, urlString := range egress.URLs {
+				u, err := managerUtils.ParseRawURL(urlString)
+				if err != nil {
+					log.Err(err).Msgf(CannotParseURLError, urlString)
+					continue
+				}
+				npPorts = append(npPorts, policyPortFromURL(u, log))
+			}
+			if len(npPorts) == 0 {
+				npPorts = append(npPorts, netv1.NetworkPolicyPort{Protocol: &tcp})
+			}
+			egressRules = append(egressRules, netv1.NetworkPolicyEgressRule{To: peers, Ports: npPorts})
 		}
 	}
 
@@ -336,6 +361,24 @@ func policyPortFromString(portString string, log *zerolog.Logger) netv1.NetworkP
 	return netv1.NetworkPolicyPort{Protocol: &tcp, Port: &port}
 }
 
+// ipBlockPeers converts gateway CIDRs or IP addresses to network policy peers, invalid values are skipped
+func ipBlockPeers(blocks []string, log *zerolog.Logger) []netv1.NetworkPolicyPeer {
+	peers := []netv1.NetworkPolicyPeer{}
+	for _, block := range blocks {
+		ipBlock := netv1.IPBlock{CIDR: block}
+		if _, _, err := net.ParseCIDR(block); err != nil {
+			ip := net.ParseIP(block)
+			if ip == nil {
+				log.Warn().Str(logging.ACTION, logging.CREATE).Msgf("Invalid gateway IP block %s", block)
+				continue
+			}
+			ipBlock = ipToIPBlock(ip)
+		}
+		peers = append(peers, netv1.NetworkPolicyPeer{IPBlock: &ipBlock})
+	}
+	return peers
+}
+
 func ipToIPBlock(ip net.IP) netv1.IPBlock {
 	if ipv4 := ip.To4(); ipv4 != nil {
 		return netv1.IPBlock{CIDR: ip.String() + "/32"}
@@ -199,6 +199,45 @@ func TestCreateNPEgress4NextModule(t *testing.T) {
 	g.Expect(expectedRules).To(CompareNPEgressRules(rules))
 }
 
+// This test checks egress rules to modules in other clusters
+func TestCreateNPEgress4RemoteModule(t *testing.T) {
+	g := gomega.NewWithT(t)
+	s := managerUtils.NewScheme(g)
+	r := createTestFybrikBlueprintController(s)
+
+	egresses := []fapp.ModuleDeployment{
+		{Cluster: myCluster + "-remote", Release: "my-release-111", URLs: []string{"my-release-111.fybrik-blueprints:8080"},
+			IPBlocks: []string{"172.20.0.0/16", "10.1.2.3", "invalid"}},
+	}
+	ipBlock1 := netv1.IPBlock{CIDR: "172.20.0.0/16"}
+	ipBlock2 := netv1.IPBlock{CIDR: "10.1.2.3/32"}
+	p := intstr.FromInt(8080)
+	expectedRules := []netv1.NetworkPolicyEgressRule{dnsEgressRules,
+		{To: []netv1.NetworkPolicyPeer{{IPBlock: &ipBlock1}, {IPBlock: &ipBlock2}},
+			Ports: []netv1.NetworkPolicyPort{{Protocol: &tcp, Port: &p}}}}
+	rules := r.createNPEgressRules(context.Background(), egresses, nil, myCluster, modulesNamespace, &r.Log)
+	g.Expect(expectedRules).To(CompareNPEgressRules(rules))
+}
+
+// This test checks ingress rules from modules in other clusters
+func TestCreateNPIngress4RemoteModule(t *testing.T) {
+	g := gomega.NewWithT(t)
+	s := managerUtils.NewScheme(g)
+	r := createTestFybrikBlueprintController(s)
+
+	ingresses := []fapp.ModuleDeployment{
+		{Cluster: myCluster, Release: "my-release-111"},
+		{Cluster: myCluster + "-remote", Release: "my-release-222", IPBlocks: []string{"172.20.0.0/16"}},
+	}
+	selector := meta.LabelSelector{MatchLabels: map[string]string{managerUtils.KubernetesInstance: "my-release-111"}}
+	ipBlock := netv1.IPBlock{CIDR: "172.20.0.0/16"}
+	rules, err := r.createNPIngressRules(false, ingresses, nil, myCluster, &r.Log)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(rules).To(gomega.HaveLen(1))
+	g.Expect(rules[0].From).To(gomega.ConsistOf(netv1.NetworkPolicyPeer{PodSelector: &selector},
+		netv1.NetworkPolicyPeer{IPBlock: &ipBlock}))
+}
+
 // This test checks NP egresses to internal services
 func TestCreateNPEgress2InternalServices(t *testing.T) {
 	g := gomega.NewWithT(t)
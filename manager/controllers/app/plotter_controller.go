@@ -12,10 +12,13 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -29,6 +32,7 @@ import (
 	managerUtils "fybrik.io/fybrik/manager/controllers/utils"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/multicluster"
@@ -41,6 +45,12 @@ const (
 	PlotterFinalizerName string = "Plotter.finalizer"
 )
 
+// Reasons of the events emitted on FybrikApplication
+const (
+	ModuleReadyReason  string = "ModuleReady"
+	ModuleFailedReason string = "ModuleFailed"
+)
+
 // PlotterReconciler reconciles a Plotter object
 type PlotterReconciler struct {
 	client.Client
@@ -48,10 +58,12 @@ type PlotterReconciler struct {
 	Log            zerolog.Logger
 	Scheme         *runtime.Scheme
 	ClusterManager multicluster.ClusterManager
+	Recorder       record.EventRecorder
 }
 
 // Reconcile receives a Plotter CRD
-func (r *PlotterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PlotterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() { metrics.ObserveReconcile(PlotterKind, result, err) }()
 	sublog := r.Log.With().Str(logging.CONTROLLER, PlotterKind).Str(logging.PLOTTER, req.NamespacedName.String()).Logger()
 
 	plotter := fapp.Plotter{}
@@ -123,6 +135,10 @@ type PlotterModulesSpec struct {
 	Scope            fapp.CapabilityScope
 	Capability       taxonomy.Capability
 	ExternalServices []string
+	// DependsOn are the instance names of the modules that this module depends on
+	DependsOn []string
+	// CredentialKeys are the keys of the data credentials that the module uses
+	CredentialKeys []string
 }
 
 // ServiceInfo stores the service API and indicates whether it is exposed to the workload
@@ -131,6 +147,9 @@ type ServiceInfo struct {
 	Release    string
 	API        *datacatalog.ResourceDetails
 	IsEndpoint bool
+	// gateways of the service cluster, used for connections from other clusters
+	IngressIPBlocks []string
+	EgressIPBlocks  []string
 }
 
 // service info of the given release in the given cluster
@@ -168,8 +187,10 @@ func (r *PlotterReconciler) convertPlotterModuleToBlueprintModule(plotter *fapp.
 			Arguments: fapp.ModuleArguments{
 				Assets: []fapp.AssetContext{},
 			},
-			AssetIDs: []string{plotterModule.AssetID},
-			Network:  fapp.ModuleNetwork{URLs: plotterModule.ExternalServices},
+			AssetIDs:       []string{plotterModule.AssetID},
+			Network:        fapp.ModuleNetwork{URLs: plotterModule.ExternalServices},
+			DependsOn:      plotterModule.DependsOn,
+			CredentialKeys: plotterModule.CredentialKeys,
 		},
 		ClusterName: plotterModule.ClusterName,
 		Scope:       plotterModule.Scope,
@@ -245,9 +266,11 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 			for _, subFlowStep := range subFlow.Steps {
 				for seqStepInd, seqStep := range subFlowStep {
 					stepTemplate := plotter.Spec.Templates[seqStep.Template]
+					dependencies := templateDependencies(&stepTemplate)
 					for indx := range stepTemplate.Modules {
 						module := stepTemplate.Modules[indx]
 						moduleArgs := seqStep.Parameters
+						api := seqStep.Parameters.API
 
 						// If the module type is "plugin" then it is assumed
 						// that there is a primary module of type "config" or "service"
@@ -256,12 +279,26 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 						if module.Type == "plugin" {
 							moduleArgs = nil
 						}
+						// modules that other modules of the template depend on do not process the template assets
+						_, isDependency := dependencies[module.Name]
+						if isDependency {
+							moduleArgs = nil
+							api = nil
+						}
+						dependsOn := []string{}
+						for _, name := range module.Dependencies {
+							if dependency, found := dependencies[name]; found {
+								dependsOn = append(dependsOn, managerUtils.CreateStepName(name, flow.AssetID, dependency.Scope))
+							}
+						}
 						scope := module.Scope
 						clusterName := seqStep.Cluster
 						var authPath string
+						var clusterMetadata multicluster.ClusterMetadata
 						for _, cluster := range clusters {
 							if clusterName == cluster.Name {
 								authPath = vault.GetAuthPath(cluster.Metadata.VaultAuthPath)
+								clusterMetadata = cluster.Metadata
 								break
 							}
 						}
@@ -271,20 +308,25 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 							uuid, instanceName)
 
 						// last sequential step of the last sub-flow exposes the endpoint
-						isEndpoint := (subFlowInd == len(flow.SubFlows)-1) && (seqStepInd == len(subFlowStep)-1) &&
-							(seqStep.Parameters.API != nil)
+						isEndpoint := (subFlowInd == len(flow.SubFlows)-1) && (seqStepInd == len(subFlowStep)-1) && (api != nil)
 						// add service details
 						// the service can serve different assets, for some it may serve as virtual endpoint for the workload
 						// thus, serviceMap combines information for all relevant assets
 						key := UniqueReleaseName(clusterName, releaseName)
 						if service, ok := serviceMap[key]; ok {
 							isEndpoint = isEndpoint || service.IsEndpoint
+							if isDependency {
+								// keep the API of a module that also serves assets in other templates
+								api = service.API
+							}
 						}
 						serviceMap[key] = ServiceInfo{
-							Cluster:    clusterName,
-							Release:    releaseName,
-							API:        seqStep.Parameters.API,
-							IsEndpoint: isEndpoint,
+							Cluster:         clusterName,
+							Release:         releaseName,
+							API:             api,
+							IsEndpoint:      isEndpoint,
+							IngressIPBlocks: clusterMetadata.IngressIPBlocks,
+							EgressIPBlocks:  clusterMetadata.EgressIPBlocks,
 						}
 
 						plotterModule := &PlotterModulesSpec{
@@ -298,6 +340,8 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 							Capability:       module.Capability,
 							VaultAuthPath:    authPath,
 							ExternalServices: module.ExternalServices,
+							DependsOn:        dependsOn,
+							CredentialKeys:   module.CredentialKeys,
 						}
 
 						blueprintModule := r.convertPlotterModuleToBlueprintModule(plotter, plotterModule)
@@ -312,6 +356,23 @@ func (r *PlotterReconciler) getBlueprintsMap(plotter *fapp.Plotter) map[string]f
 	return blueprints
 }
 
+// templateDependencies returns the modules of the template that other modules of the template depend on
+func templateDependencies(template *fapp.Template) map[string]*fapp.ModuleInfo {
+	names := map[string]bool{}
+	for indx := range template.Modules {
+		for _, name := range template.Modules[indx].Dependencies {
+			names[name] = true
+		}
+	}
+	dependencies := map[string]*fapp.ModuleInfo{}
+	for indx := range template.Modules {
+		if names[template.Modules[indx].Name] {
+			dependencies[template.Modules[indx].Name] = &template.Modules[indx]
+		}
+	}
+	return dependencies
+}
+
 // updatePlotterAssetsState updates the status of the assets processed by the blueprint modules.
 func (r *PlotterReconciler) updatePlotterAssetsState(assetToStatusMap map[string]fapp.ObservedState, blueprint *fapp.Blueprint) {
 	for instanceName, moduleState := range blueprint.Status.ModulesState {
@@ -349,6 +410,94 @@ func (r *PlotterReconciler) setPlotterAssetsReadyStateToFalse(assetToStatusMap m
 	}
 }
 
+// updateFlowsState sets the state of each flow to the state of the flow asset and records the state transitions
+func (r *PlotterReconciler) updateFlowsState(plotter *fapp.Plotter) {
+	flows := make(map[string]fapp.FlowStatus)
+	now := metav1.Now()
+	for _, flow := range plotter.Spec.Flows {
+		state := plotter.Status.Assets[flow.AssetID]
+		flowStatus := fapp.FlowStatus{
+			ObservedState: state,
+			SubFlows:      make(map[string]fapp.ObservedState),
+		}
+		for _, subFlow := range flow.SubFlows {
+			flowStatus.SubFlows[subFlow.Name] = state
+		}
+		flowStatus.History, _ = fapp.RecordStateTransition(plotter.Status.Flows[flow.Name].History, &state, now)
+		flows[flow.Name] = flowStatus
+	}
+	plotter.Status.Flows = flows
+}
+
+// emitModuleEvents emits events on the owning FybrikApplication for modules that have become ready or failed
+// since the previously observed blueprint status
+func (r *PlotterReconciler) emitModuleEvents(plotter *fapp.Plotter, cluster string, observed, current *fapp.BlueprintStatus) {
+	if r.Recorder == nil {
+		return
+	}
+	var application *fapp.FybrikApplication
+	for instanceName, history := range current.ModulesHistory {
+		transitions := newTransitions(observed.ModulesHistory[instanceName], history)
+		for i := range transitions {
+			transition := &transitions[i]
+			if transition.To != fapp.ReadyState && transition.To != fapp.FailedState {
+				continue
+			}
+			if application == nil {
+				application = r.getOwningApplication(plotter)
+				if application == nil {
+					return
+				}
+			}
+			if transition.To == fapp.ReadyState {
+				r.Recorder.Eventf(application, corev1.EventTypeNormal, ModuleReadyReason,
+					"Module instance %s on cluster %s is ready", instanceName, cluster)
+			} else {
+				r.Recorder.Eventf(application, corev1.EventTypeWarning, ModuleFailedReason,
+					"Module instance %s on cluster %s failed: %s", instanceName, cluster, transition.Message)
+			}
+		}
+	}
+}
+
+// newTransitions returns the transitions of the current history that follow the last observed transition.
+// Timestamps are compared with a precision of seconds as they are serialized in the resource status.
+func newTransitions(observed, current []fapp.StateTransition) []fapp.StateTransition {
+	if len(observed) == 0 {
+		return current
+	}
+	last := &observed[len(observed)-1]
+	for i := len(current) - 1; i >= 0; i-- {
+		if current[i].To == last.To && current[i].Time.Unix() == last.Time.Unix() {
+			return current[i+1:]
+		}
+	}
+	// the last observed transition has been dropped from the history
+	for i := range current {
+		if current[i].Time.Unix() > last.Time.Unix() {
+			return current[i:]
+		}
+	}
+	return nil
+}
+
+// getOwningApplication returns the FybrikApplication that the plotter has been created for, or nil if it does not exist
+func (r *PlotterReconciler) getOwningApplication(plotter *fapp.Plotter) *fapp.FybrikApplication {
+	key := types.NamespacedName{
+		Namespace: managerUtils.GetApplicationNamespaceFromLabels(plotter.Labels),
+		Name:      managerUtils.GetApplicationNameFromLabels(plotter.Labels),
+	}
+	if key.Name == "" {
+		return nil
+	}
+	application := &fapp.FybrikApplication{}
+	if err := r.Get(context.Background(), key, application); err != nil {
+		r.Log.Debug().Err(err).Str(logging.PLOTTER, plotter.Name).Msg("Could not fetch the owning FybrikApplication")
+		return nil
+	}
+	return application
+}
+
 //nolint:funlen,gocyclo
 func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []error) {
 	uuid := managerUtils.GetFybrikApplicationUUIDfromAnnotations(plotter.GetAnnotations())
@@ -423,6 +572,7 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 
 			logging.LogStructure("Remote blueprint status", remoteBlueprint.Status, &log, zerolog.DebugLevel, false, false)
 
+			r.emitModuleEvents(plotter, cluster, &blueprint.Status, &remoteBlueprint.Status)
 			plotter.Status.Blueprints[cluster] = fapp.CreateMetaBlueprint(remoteBlueprint)
 
 			if !remoteBlueprint.Status.ObservedState.Ready {
@@ -494,6 +644,7 @@ func (r *PlotterReconciler) reconcile(plotter *fapp.Plotter) (ctrl.Result, []err
 	plotter.Status.ObservedGeneration = plotter.ObjectMeta.Generation
 	plotter.Status.ObservedState.Ready = isReady
 	plotter.Status.Assets = assetToStatusMap
+	r.updateFlowsState(plotter)
 	plotterReadyMsg := "Plotter is ready!"
 	if isReady {
 		if plotter.Status.ReadyTimestamp == nil {
@@ -548,6 +699,7 @@ func NewPlotterReconciler(mgr ctrl.Manager, name string, manager multicluster.Cl
 		Log:            logging.LogInit(logging.CONTROLLER, name),
 		Scheme:         mgr.GetScheme(),
 		ClusterManager: manager,
+		Recorder:       mgr.GetEventRecorderFor(name),
 	}
 }
 
@@ -8,10 +8,13 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -383,3 +386,100 @@ func TestPlotterMultipleAssets(t *testing.T) {
 		verifiedModules += 1
 	}
 }
+
+// TestPlotterModuleEvents checks that the flow history is recorded and that events are emitted
+// on the owning FybrikApplication when the modules become ready.
+func TestPlotterModuleEvents(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	// Set the logger to development mode for verbose logs.
+	logf.SetLogger(zap.New(zap.UseDevMode(true)))
+
+	var (
+		name      = "plotter"
+		namespace = environment.GetInternalCRsNamespace()
+	)
+
+	plotterYAML, err := os.ReadFile("../../testdata/plotter.yaml")
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter := &fapp.Plotter{}
+	err = yaml.Unmarshal(plotterYAML, plotter)
+	g.Expect(err).To(gomega.BeNil(), "Cannot read plotter file for test")
+	plotter.Namespace = namespace
+	application := &fapp.FybrikApplication{ObjectMeta: metav1.ObjectMeta{Name: "notebook", Namespace: "default"}}
+
+	// Objects to track in the fake client.
+	objs := []runtime.Object{
+		plotter,
+		application,
+	}
+
+	// Register operator types with the runtime scheme.
+	s := utils.NewScheme(g)
+	// Create a fake client to mock API calls.
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+	dummyManager := dummy.NewDummyClusterManager(
+		make(map[string]*fapp.Blueprint),
+		[]multicluster.Cluster{{
+			Name: "thegreendragon",
+			Metadata: multicluster.ClusterMetadata{
+				Region:        "theshire",
+				VaultAuthPath: "kubernetes",
+			}}})
+	recorder := record.NewFakeRecorder(10)
+
+	// Create a PlotterReconciler object with the scheme and fake client.
+	r := &PlotterReconciler{
+		Client:         cl,
+		Log:            logging.LogInit(logging.CONTROLLER, "test-controller"),
+		Scheme:         s,
+		ClusterManager: &dummyManager,
+		Recorder:       recorder,
+	}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(cl.Get(context.TODO(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	g.Expect(plotter.Status.Flows).To(gomega.HaveLen(len(plotter.Spec.Flows)))
+	for _, flow := range plotter.Status.Flows {
+		g.Expect(flow.History).To(gomega.HaveLen(1))
+		g.Expect(flow.History[0].To).To(gomega.Equal(fapp.PendingState))
+	}
+
+	// Simulate that the modules become ready
+	blueprint := dummyManager.DeployedBlueprints["thegreendragon"]
+	blueprint.Status.ObservedState.Ready = true
+	blueprint.Status.ModulesState = map[string]fapp.ObservedState{}
+	blueprint.Status.ModulesHistory = map[string][]fapp.StateTransition{}
+	deployed := metav1.NewTime(time.Now().Add(-time.Minute))
+	ready := metav1.Now()
+	for instanceName := range blueprint.Spec.Modules {
+		blueprint.Status.ModulesState[instanceName] = fapp.ObservedState{Ready: true}
+		blueprint.Status.ModulesHistory[instanceName] = []fapp.StateTransition{
+			{Time: deployed, To: fapp.PendingState},
+			{Time: ready, From: fapp.PendingState, To: fapp.ReadyState},
+		}
+	}
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(recorder.Events).To(gomega.HaveLen(len(blueprint.Spec.Modules)))
+	for range blueprint.Spec.Modules {
+		g.Expect(<-recorder.Events).To(gomega.HavePrefix("Normal " + ModuleReadyReason))
+	}
+	g.Expect(cl.Get(context.TODO(), req.NamespacedName, plotter)).To(gomega.Succeed())
+	g.Expect(plotter.Status.ObservedState.Ready).To(gomega.BeTrue())
+	for _, flow := range plotter.Status.Flows {
+		g.Expect(flow.History).To(gomega.HaveLen(2))
+		g.Expect(fapp.LastState(flow.History)).To(gomega.Equal(fapp.ReadyState))
+	}
+
+	// No new events are emitted if the module states do not change
+	_, err = r.Reconcile(context.Background(), req)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(recorder.Events).To(gomega.BeEmpty())
+}
@@ -5,6 +5,7 @@ package app
 
 import (
 	"bytes"
+	"context"
 	tmpl "text/template"
 
 	"emperror.dev/errors"
@@ -37,12 +38,16 @@ type NewAssetInfo struct {
 
 // PlotterGenerator constructs a plotter based on the requirements (governance actions, data location) and the existing set of FybrikModules
 type PlotterGenerator struct {
+	// Ctx is the context of the reconcile cycle
+	Ctx                context.Context
 	Client             client.Client
 	Log                *zerolog.Logger
 	UUID               string
 	Owner              types.NamespacedName
 	StorageManager     storage.StorageManagerInterface
 	ProvisionedStorage map[string]NewAssetInfo
+	// Modules are the available modules, used to resolve the dependencies of the selected modules
+	Modules map[string]*fappv1.FybrikModule
 }
 
 // Provision allocates storage based on the selected account and generates the destination data store for the plotter
@@ -60,7 +65,7 @@ func (p *PlotterGenerator) Provision(item *datapath.DataInfo, destinationInterfa
 			ConfigurationOpts: storagemanager.ConfigOptions{},
 		},
 	}
-	response, err := p.StorageManager.AllocateStorage(allocateRequest)
+	response, err := p.StorageManager.AllocateStorage(p.Ctx, allocateRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +93,9 @@ func (p *PlotterGenerator) Provision(item *datapath.DataInfo, destinationInterfa
 		Connection: *response.Connection,
 		Format:     destinationInterface.DataFormat,
 	}
+	if environment.IsCredentialBrokerEnabled() {
+		datastore.SecretRef = secretRef
+	}
 	assetInfo := NewAssetInfo{
 		StorageAccount: account,
 		Details:        datastore,
@@ -102,7 +110,23 @@ func (p *PlotterGenerator) getAssetDataStore(item *datapath.DataInfo) *fappv1.Da
 		Connection: item.DataDetails.Details.Connection,
 		Vault:      getDatasetCredentials(item),
 		Format:     item.DataDetails.Details.DataFormat,
+		SecretRef:  p.getDatasetSecretRef(item),
+	}
+}
+
+// getDatasetSecretRef returns the Kubernetes secret that holds the dataset credentials
+// if they are delivered to the modules by the credential broker
+func (p *PlotterGenerator) getDatasetSecretRef(item *datapath.DataInfo) *taxonomy.SecretRef {
+	if !environment.IsCredentialBrokerEnabled() || item.DataDetails.Credentials == "" {
+		return nil
+	}
+	name, namespace, err := vault.GetKubeSecretDetailsFromVaultPath(item.DataDetails.Credentials)
+	if err != nil {
+		p.Log.Warn().Err(err).Str(logging.DATASETID, item.Context.DataSetID).
+			Msg("The dataset credentials are not stored in a Kubernetes secret and can not be delivered to the modules")
+		return nil
 	}
+	return &taxonomy.SecretRef{Name: name, Namespace: namespace}
 }
 
 // store all available credentials in the plotter
@@ -129,7 +153,10 @@ func getDatasetCredentials(item *datapath.DataInfo) map[string]fappv1.Vault {
 	return vaultMap
 }
 
-func (p *PlotterGenerator) addTemplate(element *datapath.ResolvedEdge, plotterSpec *fappv1.PlotterSpec, templateName string) {
+// addTemplate adds a template whose first module is the selected module. It is followed by the modules that it depends on,
+// directly or indirectly, where every dependency comes after the dependencies of its own. The releases of the modules
+// are deployed in the order of their dependencies by the Blueprint controller, regardless of their order in the template.
+func (p *PlotterGenerator) addTemplate(element *datapath.ResolvedEdge, plotterSpec *fappv1.PlotterSpec, templateName string) error {
 	moduleCapability := element.Module.Spec.Capabilities[element.CapabilityIndex]
 	template := fappv1.Template{
 		Name: templateName,
@@ -140,9 +167,31 @@ func (p *PlotterGenerator) addTemplate(element *datapath.ResolvedEdge, plotterSp
 			Scope:            moduleCapability.Scope,
 			Capability:       moduleCapability.Capability,
 			ExternalServices: element.Module.Spec.ExternalServices,
+			Dependencies:     ModuleDependencies(element.Module, p.Modules),
+			CredentialKeys:   element.Module.Spec.CredentialKeys,
 		}},
 	}
+	dependencies, err := GetDependencies(element.Module, p.Modules)
+	if err != nil {
+		return err
+	}
+	for _, dependency := range dependencies {
+		info := fappv1.ModuleInfo{
+			Name:             dependency.Name,
+			Type:             dependency.Spec.Type,
+			Chart:            dependency.Spec.Chart,
+			ExternalServices: dependency.Spec.ExternalServices,
+			Dependencies:     ModuleDependencies(dependency, p.Modules),
+		}
+		// a dependency is instantiated according to its own capability, it does not process the template assets
+		if len(dependency.Spec.Capabilities) > 0 {
+			info.Scope = dependency.Spec.Capabilities[0].Scope
+			info.Capability = dependency.Spec.Capabilities[0].Capability
+		}
+		template.Modules = append(template.Modules, info)
+	}
 	plotterSpec.Templates[template.Name] = template
+	return nil
 }
 
 func (p *PlotterGenerator) addInMemoryStep(element *datapath.ResolvedEdge, datasetID string, api *datacatalog.ResourceDetails,
@@ -282,7 +331,9 @@ func (p *PlotterGenerator) AddFlowInfoForAsset(item *datapath.DataInfo, applicat
 		p.Log.Trace().Str(logging.DATASETID, item.Context.DataSetID).Msgf("Adding module %s for capability %s", element.Module.Name,
 			moduleCapability.Capability)
 		templateName := element.Module.Name + "-" + string(moduleCapability.Capability)
-		p.addTemplate(element, plotterSpec, templateName)
+		if err = p.addTemplate(element, plotterSpec, templateName); err != nil {
+			return err
+		}
 		var api *datacatalog.ResourceDetails
 		if moduleCapability.API != nil {
 			if api, err = moduleAPIToService(moduleCapability.API, moduleCapability.Scope,
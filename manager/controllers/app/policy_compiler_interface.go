@@ -5,6 +5,8 @@ package app
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 
 	"emperror.dev/errors"
 	"github.com/gdexlab/go-render/render"
@@ -72,8 +74,10 @@ func ValidatePolicyDecisionsResponse(response *policymanager.GetPolicyDecisionsR
 // - data flow and locations
 // Output:
 // - a list of governance actions (upon a successful response)
-// - a message from the connector (upon a successful response)
+// - a message from the connector and the policy managers that made the decisions (upon a successful response)
 // - an error from the connector or an error formulated by Fybrik in case of Deny
+//
+// The error of a Deny is caused by ReadAccessDenied or WriteNotAllowed, and names the policy managers that denied.
 func LookupPolicyDecisions(datasetID string, resourceMetadata *datacatalog.ResourceMetadata,
 	policyManager connectors.PolicyManager, appContext ApplicationContext,
 	op *policymanager.RequestAction) ([]taxonomy.Action, string, error) {
@@ -90,7 +94,7 @@ func LookupPolicyDecisions(datasetID string, resourceMetadata *datacatalog.Resou
 		creds = vault.PathForReadingKubeSecret(appContext.Application.Namespace, appContext.Application.Spec.SecretRef)
 	}
 
-	openapiResp, err := policyManager.GetPoliciesDecisions(openapiReq, creds)
+	openapiResp, err := policyManager.GetPoliciesDecisions(appContext.GetContext(), openapiReq, creds)
 	var actions []taxonomy.Action
 	if err != nil {
 		return actions, "", err
@@ -115,11 +119,44 @@ func LookupPolicyDecisions(datasetID string, resourceMetadata *datacatalog.Resou
 			case taxonomy.WriteFlow:
 				message = WriteNotAllowed
 			}
-			// access is denied - return the connector message that may help to understand the reason
-			return actions, openapiResp.Message, errors.New(message)
+			// access is denied - return the connector message that may help to understand the reason,
+			// and the policy managers that denied the access
+			denial := decisionSources(result[i : i+1])
+			err = errors.New(message)
+			if denial != "" {
+				// the message of the error is the cause, which classifies the denial
+				err = fmt.Errorf("%w (%s)", err, denial)
+			}
+			return actions, joinMessages(openapiResp.Message, denial), err
 		}
 		actions = append(actions, result[i].Action)
 	}
 	// return the action list and the connector message with additional information
-	return actions, openapiResp.Message, nil
+	return actions, joinMessages(openapiResp.Message, decisionSources(result)), nil
+}
+
+// decisionSources describes the policy managers that made the decisions of a composite policy manager,
+// e.g. "RedactAction by opa,custom", or returns an empty string if the sources of the decisions are not known
+func decisionSources(result []policymanager.ResultItem) string {
+	sources := []string{}
+	for i := range result {
+		if result[i].Source != "" {
+			sources = append(sources, string(result[i].Action.Name)+" by "+result[i].Source)
+		}
+	}
+	if len(sources) == 0 {
+		return ""
+	}
+	return "policy decisions: " + strings.Join(sources, ", ")
+}
+
+// joinMessages joins the non-empty messages with the separator of the messages in the application status
+func joinMessages(messages ...string) string {
+	nonEmpty := []string{}
+	for _, message := range messages {
+		if message != "" {
+			nonEmpty = append(nonEmpty, message)
+		}
+	}
+	return strings.Join(nonEmpty, Separator)
 }
@@ -0,0 +1,61 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package app
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	fappv1 "fybrik.io/fybrik/manager/apis/app/v1beta1"
+	connectors "fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// sourcedPolicyManager returns decisions that are combined from several policy managers
+type sourcedPolicyManager struct {
+	connectors.PolicyManager
+	result []policymanager.ResultItem
+}
+
+func (m *sourcedPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	return &policymanager.GetPolicyDecisionsResponse{DecisionID: "1", Message: "opa: evaluated", Result: m.result}, nil
+}
+
+// This test checks that the policy managers that made the decisions are reported
+func TestLookupPolicyDecisionsSources(t *testing.T) {
+	g := gomega.NewWithT(t)
+	log := zerolog.Nop()
+	appContext := ApplicationContext{Log: &log, Application: &fappv1.FybrikApplication{}}
+	operation := &policymanager.RequestAction{ActionType: taxonomy.ReadFlow}
+	manager := &sourcedPolicyManager{result: []policymanager.ResultItem{
+		{Policy: "redact PII", Action: taxonomy.Action{Name: "RedactAction"}, Source: "opa,custom"},
+	}}
+
+	actions, message, err := LookupPolicyDecisions("s3/allow-dataset", &datacatalog.ResourceMetadata{}, manager, appContext, operation)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(actions).To(gomega.HaveLen(1))
+	g.Expect(message).To(gomega.Equal("opa: evaluated" + Separator + "policy decisions: RedactAction by opa,custom"))
+
+	// the denial is classified by its cause, and names the policy manager that denied the access
+	manager.result = append(manager.result,
+		policymanager.ResultItem{Policy: "deny finance", Action: taxonomy.Action{Name: "Deny"}, Source: "custom"})
+	_, message, err = LookupPolicyDecisions("s3/deny-dataset", &datacatalog.ResourceMetadata{}, manager, appContext, operation)
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(errors.Cause(err).Error()).To(gomega.Equal(ReadAccessDenied))
+	g.Expect(err.Error()).To(gomega.Equal(ReadAccessDenied + " (policy decisions: Deny by custom)"))
+	g.Expect(message).To(gomega.Equal("opa: evaluated" + Separator + "policy decisions: Deny by custom"))
+
+	// the denial of a single policy manager is not changed
+	manager.result = []policymanager.ResultItem{{Policy: "deny finance", Action: taxonomy.Action{Name: "Deny"}}}
+	_, message, err = LookupPolicyDecisions("s3/deny-dataset", &datacatalog.ResourceMetadata{}, manager, appContext, operation)
+	g.Expect(err).To(gomega.MatchError(ReadAccessDenied))
+	g.Expect(message).To(gomega.Equal("opa: evaluated"))
+}
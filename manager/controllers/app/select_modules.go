@@ -10,7 +10,6 @@ import (
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
 
-	fapp "fybrik.io/fybrik/manager/apis/app/v1beta1"
 	"fybrik.io/fybrik/pkg/adminconfig"
 	"fybrik.io/fybrik/pkg/datapath"
 	"fybrik.io/fybrik/pkg/environment"
@@ -68,6 +67,8 @@ func (p *PathBuilder) FindPaths() []datapath.Solution {
 	}
 	// get valid solutions by extending data paths with transformations and selecting an appropriate cluster for each capability
 	solutions = p.validSolutions(solutions)
+	// among the versions of a module, prefer the latest one
+	solutions = preferLatestVersions(solutions)
 
 	return solutions
 }
@@ -276,42 +277,6 @@ func (p *PathBuilder) findPathsWithinLimit(source, sink *datapath.Node, n int) [
 
 // helper functions
 
-// CheckDependencies returns dependent modules
-func CheckDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.FybrikModule) ([]*fapp.FybrikModule, []string) {
-	var found []*fapp.FybrikModule
-	var missing []string
-	for _, dependency := range module.Spec.Dependencies {
-		if dependency.Type != fapp.Module {
-			continue
-		}
-		if moduleMap[dependency.Name] == nil {
-			missing = append(missing, dependency.Name)
-		} else {
-			found = append(found, moduleMap[dependency.Name])
-			additionalDependencies, notFound := CheckDependencies(moduleMap[dependency.Name], moduleMap)
-			found = append(found, additionalDependencies...)
-			missing = append(missing, notFound...)
-		}
-	}
-	return found, missing
-}
-
-// SupportsDependencies checks whether the module supports the dependency requirements
-func SupportsDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.FybrikModule) bool {
-	// check dependencies
-	_, missingModules := CheckDependencies(module, moduleMap)
-	return len(missingModules) == 0
-}
-
-// GetDependencies returns dependencies of a selected module
-func GetDependencies(module *fapp.FybrikModule, moduleMap map[string]*fapp.FybrikModule) ([]*fapp.FybrikModule, error) {
-	dependencies, missingModules := CheckDependencies(module, moduleMap)
-	if len(missingModules) > 0 {
-		return dependencies, errors.New("Module " + module.Name + " has missing dependencies")
-	}
-	return dependencies, nil
-}
-
 // supportsGovernanceAction checks whether the module supports the required governance action
 func supportsGovernanceAction(edge *datapath.Edge, action taxonomy.Action) bool {
 	// Loop over the data transforms (actions) performed by the module for this capability
@@ -4,7 +4,9 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/rs/zerolog"
@@ -13,6 +15,7 @@ import (
 	"fybrik.io/fybrik/pkg/datapath"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/optimizer"
 )
@@ -20,11 +23,12 @@ import (
 // find a solution for a data path
 // satisfying governance and admin policies
 // with respect to the optimization strategy
-func solveSingleDataset(env *datapath.Environment, dataset *datapath.DataInfo, log *zerolog.Logger) (datapath.Solution, error) {
+func solveSingleDataset(ctx context.Context, env *datapath.Environment, dataset *datapath.DataInfo,
+	log *zerolog.Logger) (datapath.Solution, error) {
 	cspPath := environment.GetCSPPath()
 	if environment.UseCSP() && cspPath != "" {
 		cspOptimizer := optimizer.NewOptimizer(env, dataset, cspPath, log)
-		solution, err := cspOptimizer.Solve()
+		solution, err := cspOptimizer.Solve(ctx)
 		if err == nil {
 			if len(solution.DataPath) > 0 { // solver found a solution
 				return solution, nil
@@ -47,14 +51,17 @@ func solveSingleDataset(env *datapath.Environment, dataset *datapath.DataInfo, l
 }
 
 // find a solution for all data paths at once
-func solve(env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog.Logger) ([]datapath.Solution, error) {
+func solve(ctx context.Context, env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog.Logger) ([]datapath.Solution, error) {
+	start := time.Now()
+	defer func() { metrics.SolveDuration.Observe(time.Since(start).Seconds()) }()
 	solutions := []datapath.Solution{}
 	if err := validateBasicConditions(env, datasets, log); err != nil {
 		return solutions, err
 	}
 	for i := range datasets {
-		solution, err := solveSingleDataset(env, &datasets[i], log)
+		solution, err := solveSingleDataset(ctx, env, &datasets[i], log)
 		if err != nil {
+			metrics.SolveFailures.WithLabelValues(metrics.NoDataPathReason).Inc()
 			return solutions, err
 		}
 		solutions = append(solutions, solution)
@@ -66,6 +73,7 @@ func solve(env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog
 func validateBasicConditions(env *datapath.Environment, datasets []datapath.DataInfo, log *zerolog.Logger) error {
 	if len(env.Modules) == 0 {
 		log.Error().Msg(NoDeployedModules)
+		metrics.SolveFailures.WithLabelValues(metrics.NoDeployedModulesReason).Inc()
 		return errors.New(NoDeployedModules)
 	}
 	for i := range datasets {
@@ -73,15 +81,20 @@ func validateBasicConditions(env *datapath.Environment, datasets []datapath.Data
 		if dataset.Context.Flow == "" || dataset.Context.Flow == taxonomy.ReadFlow {
 			if err := validateApplicationProtocol(env, dataset); err != nil {
 				log.Error().Err(err).Send()
+				metrics.SolveFailures.WithLabelValues(metrics.UnsupportedInterfaceReason).Inc()
 				return err
 			}
+		}
+		if dataset.Context.Flow == "" || dataset.Context.Flow == taxonomy.ReadFlow || dataset.Context.Flow == taxonomy.DeleteFlow {
 			if err := validateAssetProtocol(env, dataset); err != nil {
 				log.Error().Err(err).Send()
+				metrics.SolveFailures.WithLabelValues(metrics.UnsupportedInterfaceReason).Inc()
 				return err
 			}
 		}
 		if err := validateRequiredCapabilities(env, dataset); err != nil {
 			log.Error().Err(err).Send()
+			metrics.SolveFailures.WithLabelValues(metrics.MissingCapabilityReason).Inc()
 			return err
 		}
 	}
@@ -134,8 +147,12 @@ func validateAssetProtocol(env *datapath.Environment, dataset *datapath.DataInfo
 			}
 		}
 	}
-	message := fmt.Sprintf("The asset '%s' (%s) can't be read by the deployed modules",
-		dataset.Context.DataSetID, createInterfaceString(assetInterfacePtr))
+	operation := "read"
+	if dataset.Context.Flow == taxonomy.DeleteFlow {
+		operation = "deleted"
+	}
+	message := fmt.Sprintf("The asset '%s' (%s) can't be %s by the deployed modules",
+		dataset.Context.DataSetID, createInterfaceString(assetInterfacePtr), operation)
 	return errors.New(message)
 }
 
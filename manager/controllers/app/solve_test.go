@@ -4,6 +4,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -199,7 +200,7 @@ func TestEmptyEnvironment(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	env := newEnvironment()
 	asset := createReadRequest()
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	g.Expect(err.Error()).To(gomega.BeIdenticalTo(NoDeployedModules))
 }
@@ -220,13 +221,13 @@ func TestReadWithTransforms(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: string(account.Spec.Geography)}})
 	asset := createReadRequest()
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	// only read is not enough
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, copyModule)
 	addStorageAccount(env, account)
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -247,10 +248,10 @@ func TestReadModuleSource(t *testing.T) {
 	asset := createReadRequest()
 	asset.DataDetails.Details.Connection.Name = mockup.JdbcDB2
 	asset.DataDetails.Details.DataFormat = ""
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, readModuleDB2)
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	logging.LogStructure("TestReadModuleSource", &solution, &testLog, zerolog.InfoLevel, false, false)
@@ -277,12 +278,12 @@ func TestReadAndCopyWithTransforms(t *testing.T) {
 	addModule(env, copyModule)
 	addStorageAccount(env, account)
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	_, err = solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err = solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 }
 
@@ -302,7 +303,7 @@ func TestReadAndTransformModules(t *testing.T) {
 	asset.DataDetails.Details.Connection.Name = mockup.S3
 	asset.DataDetails.Details.DataFormat = mockup.Parquet
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -329,7 +330,7 @@ func TestReadAfterRead(t *testing.T) {
 	asset.DataDetails.Details.Connection.Name = mockup.S3
 	asset.DataDetails.Details.DataFormat = mockup.Parquet
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -376,11 +377,11 @@ func TestTransformInDataLocation(t *testing.T) {
 	}
 	asset.StorageRequirements[account.Spec.Geography] = []taxonomy.Action{}
 	asset.StorageRequirements[taxonomy.ProcessingLocation(remoteGeo)] = []taxonomy.Action{}
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	// remove restriction on copy
 	asset.Configuration.ConfigDecisions["copy"] = adminconfig.Decision{Deploy: adminconfig.StatusUnknown}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -413,10 +414,10 @@ func TestCopyFlow(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: string(account1.Spec.Geography)}})
 
 	asset := createCopyRequest()
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	asset.StorageRequirements[account2.Spec.Geography] = []taxonomy.Action{}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -469,7 +470,7 @@ func TestStorageCostRestrictictions(t *testing.T) {
 		Object:     taxonomy.StorageAccount,
 		Instance:   account2.Name,
 	})
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 	// change the restriction to fit one of the accounts
 	asset.Configuration.ConfigDecisions["copy"] = adminconfig.Decision{
@@ -477,7 +478,7 @@ func TestStorageCostRestrictictions(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "storage-cost", Range: &taxonomy.RangeType{Max: 15}}}},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -512,7 +513,7 @@ func TestWriteNewAsset(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "geography", Values: adminconfig.StringList{string(account2.Spec.Geography)}}}},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -543,7 +544,7 @@ func TestStorageAndModuleMismatch(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "geography", Values: adminconfig.StringList{string(account.Spec.Geography)}}}},
 	}
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).To(gomega.HaveOccurred())
 }
 
@@ -577,7 +578,7 @@ func TestStorageTypeRestriction(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			StorageAccounts: []adminconfig.Restriction{{Property: "type", Values: adminconfig.StringList{string(accountMySQL.Spec.Type)}}}},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -609,7 +610,7 @@ func TestWriteExistingAsset(t *testing.T) {
 	asset := createUpdateRequest()
 	asset.StorageRequirements[account1.Spec.Geography] = []taxonomy.Action{}
 	asset.StorageRequirements[account2.Spec.Geography] = []taxonomy.Action{}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -632,7 +633,7 @@ func TestWriteAndTransformModules(t *testing.T) {
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "xyz"}})
 	asset := createUpdateRequest()
 	asset.Actions = []taxonomy.Action{{Name: "RedactAction"}}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -658,7 +659,7 @@ func TestDeleteFlow(t *testing.T) {
 	addModule(env, transformModule)
 	addCluster(env, multicluster.Cluster{Metadata: multicluster.ClusterMetadata{Region: "xyz"}})
 	asset := createDeleteRequest()
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -684,11 +685,11 @@ func TestModuleSelection(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{Modules: []adminconfig.Restriction{{
 			Property: "capabilities.scope",
 			Values:   adminconfig.StringList{"workload"}}}}}
-	_, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	_, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	// wrong scope
 	g.Expect(err).To(gomega.HaveOccurred())
 	addModule(env, workloadLevelModule)
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -747,7 +748,7 @@ func TestOptimalStorage(t *testing.T) {
 		})
 		cost += 5
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -822,7 +823,7 @@ func TestOptimalStorageAndClusterCost(t *testing.T) {
 		Object:     taxonomy.Cluster,
 		Instance:   "Expensive",
 	})
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -873,7 +874,7 @@ func TestGoalConflict(t *testing.T) {
 			Weight:    "0.8",
 		},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -932,7 +933,7 @@ func TestMinMultipleGoals(t *testing.T) {
 			Weight:    "0.1",
 		},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -991,7 +992,7 @@ func TestMinMaxGoals(t *testing.T) {
 			Weight:    "0.4",
 		},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(1))
@@ -1058,7 +1059,7 @@ func TestMinDistance(t *testing.T) {
 		Object:     taxonomy.InterRegion,
 		Arguments:  []string{"theshire", "theshire"},
 	})
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath).To(gomega.HaveLen(2))
@@ -1102,7 +1103,7 @@ func TestClusterAttribute(t *testing.T) {
 		DeploymentRestrictions: adminconfig.Restrictions{
 			Clusters: []adminconfig.Restriction{{Property: "compliance", Values: adminconfig.StringList{"true"}}}},
 	}
-	solutions, err := solve(env, []datapath.DataInfo{*asset}, &testLog)
+	solutions, err := solve(context.Background(), env, []datapath.DataInfo{*asset}, &testLog)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
 	solution := solutions[0]
 	g.Expect(solution.DataPath[0].Cluster).To(gomega.Equal(allowedCluster.Name))
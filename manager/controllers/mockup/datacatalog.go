@@ -4,6 +4,7 @@
 package mockup
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,9 +19,11 @@ import (
 
 type DataCatalogDummy struct {
 	dataDetails map[string]datacatalog.GetAssetResponse
+	deleted     []string
 }
 
-func (d *DataCatalogDummy) GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error) {
+func (d *DataCatalogDummy) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
 	datasetID := string(in.AssetID)
 	log.Printf("MockDataCatalog.GetDatasetInfo called with DataSetID " + datasetID)
 
@@ -45,19 +48,38 @@ func (d *DataCatalogDummy) GetAssetInfo(in *datacatalog.GetAssetRequest, creds s
 	return nil, errors.New(dc.AssetIDNotFound)
 }
 
-func (d *DataCatalogDummy) CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error) {
+func (d *DataCatalogDummy) CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
 	// TODO: will be provided a proper implementation once the implementation of CreateAsset in katalog-connector
 	// is completed in a future PR. Till then a dummy implementation is provided.
 	return &datacatalog.CreateAssetResponse{AssetID: "testAssetID"}, nil
 }
 
-func (d *DataCatalogDummy) DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error) {
-	// TODO: will be provided a proper implementation once the implementation of DeleteAsset in katalog-connector
-	// is completed in a future PR. Till then a dummy implementation is provided.
-	return &datacatalog.DeleteAssetResponse{Status: "DeleteAssetInfo not implemented in DataCatalogDummy"}, nil
+func (d *DataCatalogDummy) DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
+	datasetID := string(in.AssetID)
+	log.Printf("MockDataCatalog.DeleteAsset called with DataSetID " + datasetID)
+
+	splittedID := strings.SplitN(datasetID, "/", 2)
+	if len(splittedID) != 2 {
+		errorMessage := fmt.Sprintf("Invalid dataset ID for mock: %s", datasetID)
+		log.Print(errorMessage)
+		return nil, errors.New(errorMessage)
+	}
+	if _, found := d.dataDetails[splittedID[0]]; !found {
+		return nil, errors.New(dc.AssetIDNotFound)
+	}
+	d.deleted = append(d.deleted, datasetID)
+	return &datacatalog.DeleteAssetResponse{Status: "Deletion Successful"}, nil
+}
+
+// DeletedAssets returns the identifiers of the assets deleted through DeleteAsset
+func (d *DataCatalogDummy) DeletedAssets() []string {
+	return d.deleted
 }
 
-func (m *DataCatalogDummy) UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error) {
+func (m *DataCatalogDummy) UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
 	// TODO: will be provided a proper implementation once the implementation of UpdateAsset in katalog-connector
 	// is completed in a future PR. Till then a dummy implementation is provided.
 	return &datacatalog.UpdateAssetResponse{Status: "UpdateAsset not implemented in DataCatalogDummy"}, nil
@@ -4,6 +4,7 @@
 package mockup
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -42,7 +43,7 @@ func deserializeToTaxonomyAction(action map[string]interface{}, taxAction *taxon
 // GetPoliciesDecisions implements the PolicyCompiler interface
 //
 //nolint:funlen
-func (m *MockPolicyManager) GetPoliciesDecisions(input *policymanager.GetPolicyDecisionsRequest,
+func (m *MockPolicyManager) GetPoliciesDecisions(ctx context.Context, input *policymanager.GetPolicyDecisionsRequest,
 	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
 	log.Printf("Received OpenAPI request in mockup GetPoliciesDecisions: ")
 	log.Printf("ProcessingGeography: %s", input.Action.ProcessingLocation)
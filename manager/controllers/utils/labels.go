@@ -17,6 +17,7 @@ const (
 	BlueprintNamespaceLabel   = FybrikPrefix + "/blueprint-namespace"
 	BlueprintNameLabel        = FybrikPrefix + "/blueprint-name"
 	FybrikAppUUID             = FybrikPrefix + "/app-uuid"
+	CredentialsReleaseLabel   = FybrikPrefix + "/credentials-release"
 	KubernetesInstance        = "app.kubernetes.io/instance"
 	KubernetesNamespaceName   = "kubernetes.io/metadata.name"
 	KubernetesAppName         = "app.kubernetes.io/name"
@@ -5,15 +5,18 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/fsnotify/fsnotify"
 	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -40,7 +43,9 @@ import (
 	"fybrik.io/fybrik/pkg/multicluster"
 	"fybrik.io/fybrik/pkg/multicluster/local"
 	"fybrik.io/fybrik/pkg/multicluster/razee"
+	"fybrik.io/fybrik/pkg/tracing"
 	"fybrik.io/fybrik/pkg/utils"
+	"fybrik.io/fybrik/pkg/vault"
 )
 
 const certSubDir = "/k8s-webhook-server"
@@ -58,6 +63,7 @@ func init() {
 	_ = corev1.AddToScheme(scheme)
 	_ = netv1.AddToScheme(scheme)
 	_ = coordinationv1.AddToScheme(scheme)
+	_ = rbacv1.AddToScheme(scheme)
 }
 
 //nolint:funlen,gocyclo
@@ -66,6 +72,17 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 	setupLog.Info().Msg("creating manager. based on: gitTag=" + gitTag + ", latest gitCommit=" + gitCommit)
 	environment.LogEnvVariables(&setupLog)
 
+	shutdownTracing, err := tracing.Init(context.Background(), "fybrik-manager")
+	if err != nil {
+		setupLog.Error().Err(err).Msg("unable to initialize tracing")
+		return 1
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error().Err(err).Msg("unable to flush the trace spans")
+		}
+	}()
+
 	var applicationNamespaceSelector fields.Selector
 	applicationNamespace := environment.GetApplicationNamespace()
 	if len(applicationNamespace) > 0 {
@@ -167,7 +184,7 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 			}
 		}()
 
-		evaluator, err := adminconfig.NewRegoPolicyEvaluator()
+		evaluator, err := adminconfig.NewEvaluator()
 		if err != nil {
 			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to compile configuration policies")
 			return 1
@@ -200,6 +217,10 @@ func run(namespace, metricsAddr, healthProbeAddr string, enableLeaderElection bo
 			evaluator,
 			infrastructureManager,
 		)
+		if applicationController.Vault, err = newVaultConnection(); err != nil {
+			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to connect to vault")
+			return 1
+		}
 		if err = applicationController.SetupWithManager(mgr); err != nil {
 			setupLog.Error().Err(err).Str(logging.CONTROLLER, "FybrikApplication").Msg("unable to create controller")
 			return 1
@@ -340,10 +361,52 @@ func newPolicyManager() (pmclient.PolicyManager, error) {
 	setupLog.Info().Str(logging.CONNECTOR, mainPolicyManagerName).Str("URL", mainPolicyManagerURL).
 		Msg("setting main policy manager client")
 
-	return pmclient.NewOpenAPIPolicyManager(
+	mainPolicyManager, err := pmclient.NewPolicyManager(
 		mainPolicyManagerName,
 		mainPolicyManagerURL,
 	)
+	if err != nil {
+		return nil, err
+	}
+	additionalPolicyManagers, err := environment.GetAdditionalPolicyManagers()
+	if err != nil || len(additionalPolicyManagers) == 0 {
+		return mainPolicyManager, err
+	}
+	// the decisions of the main policy manager are combined with the decisions of the additional ones
+	sources := []pmclient.PolicySource{{Name: mainPolicyManagerName, PolicyManager: mainPolicyManager}}
+	names := make([]string, 0, len(additionalPolicyManagers))
+	for name := range additionalPolicyManagers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		setupLog.Info().Str(logging.CONNECTOR, name).Str("URL", additionalPolicyManagers[name]).
+			Msg("setting additional policy manager client")
+		var policyManager pmclient.PolicyManager
+		if policyManager, err = pmclient.NewPolicyManager(name, additionalPolicyManagers[name]); err != nil {
+			return nil, err
+		}
+		sources = append(sources, pmclient.PolicySource{Name: name, PolicyManager: policyManager})
+	}
+	return pmclient.NewCompositePolicyManager(sources)
+}
+
+// newVaultConnection returns a connection to vault for leasing dynamic credentials,
+// or nil if no dynamic secrets engines are configured
+func newVaultConnection() (vault.Interface, error) {
+	if !environment.IsVaultEnabled() || len(environment.GetVaultDynamicSecretsPaths()) == 0 {
+		return nil, nil
+	}
+	setupLog.Info().Str("URL", environment.GetVaultAddress()).Msg("setting vault client for dynamic secrets")
+	connection, err := vault.InitConnection(environment.GetVaultAddress(), environment.GetVaultToken())
+	if err != nil {
+		return nil, err
+	}
+	// mount the key-value secrets engine that holds the leased credentials, if it has not been mounted yet
+	if err = connection.Mount("/v1/sys/mounts/" + environment.GetVaultLeasedSecretsPath()); err != nil {
+		return nil, err
+	}
+	return connection, nil
 }
 
 // newClusterManager decides based on the environment variables that are set which
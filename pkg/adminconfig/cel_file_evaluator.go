@@ -0,0 +1,244 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/monitor"
+	fybrikUtils "fybrik.io/fybrik/pkg/utils"
+)
+
+// CELPolicyExtension is the extension of files that define admin config policies using CEL conditions
+const CELPolicyExtension = ".cel.yaml"
+
+// CELPolicyDirectory is a directory containing CEL rule files that
+// define admin config policies
+var CELPolicyDirectory = RegoPolicyDirectory
+
+// CELConfigRule makes a decision for a capability if the condition holds.
+// The condition is a CEL expression over the evaluator input, e.g. input.request.usage == "read".
+// An empty condition always holds.
+type CELConfigRule struct {
+	Capability taxonomy.Capability `json:"capability"`
+	Condition  string              `json:"condition,omitempty"`
+	Decision   Decision            `json:"decision"`
+}
+
+// CELOptimizeRule selects an optimization strategy if the condition holds
+type CELOptimizeRule struct {
+	Condition string                  `json:"condition,omitempty"`
+	Strategy  []AttributeOptimization `json:"strategy"`
+	Policy    DecisionPolicy          `json:"policy"`
+}
+
+// CELPolicyFile is the structure of a file with CEL admin config policies
+type CELPolicyFile struct {
+	Config   []CELConfigRule   `json:"config,omitempty"`
+	Optimize []CELOptimizeRule `json:"optimize,omitempty"`
+}
+
+// CELPolicies holds the compiled rules from all policy files
+type CELPolicies struct {
+	Config   []CELConfigRule
+	Optimize []CELOptimizeRule
+	// compiled conditions in the order of the rules
+	configPrograms   []cel.Program
+	optimizePrograms []cel.Program
+}
+
+// CELPolicyEvaluator implements EvaluatorInterface
+type CELPolicyEvaluator struct {
+	Log      zerolog.Logger
+	Policies *CELPolicies
+	Mux      *sync.RWMutex
+}
+
+// NewCELPolicyEvaluator constructs a new CELPolicyEvaluator object
+func NewCELPolicyEvaluator() (*CELPolicyEvaluator, error) {
+	logger := logging.LogInit(logging.CONTROLLER, "ConfigPolicyEvaluator")
+	// pre-compiling config policy files
+	policies, err := PrepareCELPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CELPolicyEvaluator{
+		Log:      logger,
+		Policies: policies,
+		Mux:      &sync.RWMutex{},
+	}, nil
+}
+
+// NewCELPolicyEvaluatorWithPolicies constructs a new CELPolicyEvaluator object from the given policy files content
+func NewCELPolicyEvaluatorWithPolicies(files ...[]byte) (*CELPolicyEvaluator, error) {
+	policies, err := CompileCELPolicies(files...)
+	if err != nil {
+		return nil, err
+	}
+	return &CELPolicyEvaluator{
+		Log:      logging.LogInit(logging.CONTROLLER, "ConfigPolicyEvaluator"),
+		Policies: policies,
+		Mux:      &sync.RWMutex{},
+	}, nil
+}
+
+// PrepareCELPolicies reads and compiles the CEL policy files in CELPolicyDirectory
+func PrepareCELPolicies() (*CELPolicies, error) {
+	files, err := os.ReadDir(CELPolicyDirectory)
+	if err != nil {
+		return nil, err
+	}
+	contents := [][]byte{}
+	for _, info := range files {
+		name := info.Name()
+		if !strings.HasSuffix(name, CELPolicyExtension) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Clean(filepath.Join(CELPolicyDirectory, name)))
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, content)
+	}
+	return CompileCELPolicies(contents...)
+}
+
+// CompileCELPolicies parses the policy files and compiles the rule conditions
+func CompileCELPolicies(files ...[]byte) (*CELPolicies, error) {
+	env, err := cel.NewEnv(cel.Variable("input", cel.DynType))
+	if err != nil {
+		return nil, errors.Wrap(err, "couldn't create a CEL environment")
+	}
+	policies := &CELPolicies{}
+	for _, content := range files {
+		policyFile := CELPolicyFile{}
+		if err := yaml.Unmarshal(content, &policyFile); err != nil {
+			return nil, errors.Wrap(err, "couldn't parse a CEL policy file")
+		}
+		for _, rule := range policyFile.Config {
+			program, err := compileCondition(env, rule.Condition)
+			if err != nil {
+				return nil, errors.Wrapf(err, "couldn't compile a condition of policy %s", rule.Decision.Policy.ID)
+			}
+			policies.Config = append(policies.Config, rule)
+			policies.configPrograms = append(policies.configPrograms, program)
+		}
+		for _, rule := range policyFile.Optimize {
+			program, err := compileCondition(env, rule.Condition)
+			if err != nil {
+				return nil, errors.Wrapf(err, "couldn't compile a condition of policy %s", rule.Policy.ID)
+			}
+			policies.Optimize = append(policies.Optimize, rule)
+			policies.optimizePrograms = append(policies.optimizePrograms, program)
+		}
+	}
+	return policies, nil
+}
+
+// compileCondition compiles a CEL condition, an empty condition always holds
+func compileCondition(env *cel.Env, condition string) (cel.Program, error) {
+	if strings.TrimSpace(condition) == "" {
+		condition = "true"
+	}
+	ast, issues := env.Compile(condition)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, errors.Errorf("condition %s should evaluate to bool, got %v", condition, ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+func (r *CELPolicyEvaluator) OnError(err error) {
+	r.Log.Error().Err(err).Msg("Error compiling the policies")
+}
+
+// Options for file monitor including the monitored directory and the relevant file extension
+func (r *CELPolicyEvaluator) GetOptions() monitor.FileMonitorOptions {
+	return monitor.FileMonitorOptions{Path: CELPolicyDirectory, Extension: CELPolicyExtension}
+}
+
+// notification event: policy files have been changed
+func (r *CELPolicyEvaluator) OnNotify() {
+	policies, err := PrepareCELPolicies()
+	if err != nil {
+		r.OnError(err)
+		return
+	}
+	r.Mux.Lock()
+	r.Policies = policies
+	r.Mux.Unlock()
+}
+
+// Evaluate method evaluates the CEL conditions based on the dynamic input object
+func (r *CELPolicyEvaluator) Evaluate(in *EvaluatorInput) (EvaluatorOutput, error) {
+	logger := r.Log.With().Str(utils.FybrikAppUUID, in.Workload.UUID).Logger()
+	logging.LogStructure("Evaluator Input", in, &logger, zerolog.DebugLevel, false, false)
+	input, err := fybrikUtils.StructToMap(in)
+	if err != nil {
+		return EvaluatorOutput{Valid: false}, errors.Wrap(err, "failed to prepare an input for CEL")
+	}
+	activation := map[string]interface{}{"input": input}
+
+	evalStruct := EvaluationOutputStructure{Config: RuleDecisionList{}}
+	r.Mux.RLock()
+	policies := r.Policies
+	r.Mux.RUnlock()
+	for ind := range policies.Config {
+		rule := &policies.Config[ind]
+		if r.holds(&logger, policies.configPrograms[ind], activation, rule.Decision.Policy.ID) {
+			evalStruct.Config = append(evalStruct.Config,
+				DecisionPerCapability{Capability: rule.Capability, Decision: rule.Decision})
+		}
+	}
+	for ind := range policies.Optimize {
+		rule := &policies.Optimize[ind]
+		if r.holds(&logger, policies.optimizePrograms[ind], activation, rule.Policy.ID) {
+			evalStruct.Optimize = append(evalStruct.Optimize,
+				OptimizationStrategy{Strategy: rule.Strategy, Policy: rule.Policy})
+		}
+	}
+	logging.LogStructure("Admin policy evaluation", &evalStruct, &logger, zerolog.DebugLevel, false, true)
+	// merge decisions and build an output object for the manager
+	out := EvaluatorOutput{
+		DatasetID:       in.Request.DatasetID,
+		PolicySetID:     in.Workload.PolicySetID,
+		UUID:            in.Workload.UUID,
+		ConfigDecisions: DecisionPerCapabilityMap{},
+		Policies:        []DecisionPolicy{},
+	}
+	if !processConfigDecisions(&r.Log, &evalStruct, in, &out) {
+		return out, nil
+	}
+	processOptimizeDecisions(&evalStruct, &out)
+	out.Valid = true
+	return out, nil
+}
+
+// holds returns true if the condition evaluates to true.
+// Similar to undefined values in rego, a condition that can not be evaluated on the given input,
+// e.g. due to a missing field, does not hold.
+func (r *CELPolicyEvaluator) holds(log *zerolog.Logger, program cel.Program, activation map[string]interface{},
+	policyID string) bool {
+	val, _, err := program.Eval(activation)
+	if err != nil {
+		log.Debug().Err(err).Msgf("condition of policy %s can not be evaluated", policyID)
+		return false
+	}
+	result, ok := val.Value().(bool)
+	return ok && result
+}
@@ -0,0 +1,178 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package adminconfig_test
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/adminconfig"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/multicluster"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+func BaseCELEvaluator() *adminconfig.CELPolicyEvaluator {
+	policies := `
+config:
+  # read scenario, same location
+  - capability: copy
+    condition: >
+      input.workload.properties.stage == "PROD" &&
+      input.workload.properties.severity != "critical" &&
+      input.request.dataset.geography == input.workload.cluster.metadata.region
+    decision:
+      policy: {policySetID: "1", ID: test-1}
+      deploy: "False"
+  # read scenario, different locations
+  - capability: copy
+    condition: >
+      input.workload.properties.stage == "PROD" &&
+      input.request.dataset.geography != input.workload.cluster.metadata.region
+    decision:
+      policy: {policySetID: "1", ID: test-2}
+      deploy: "True"
+      restrictions:
+        clusters: [{property: name, values: [clusterB, clusterD, clusterC]}]
+        modules: [{property: scope, values: [asset]}]
+  # copy scenario
+  - capability: copy
+    condition: input.workload.properties.severity == "critical"
+    decision:
+      policy: {policySetID: "1", ID: test-3}
+      deploy: "True"
+      restrictions:
+        clusters: [{property: name, values: [clusterB, clusterA, clusterC]}]
+        modules: [{property: type, values: [service, plugin, config]}]
+  # write scenario
+  - capability: copy
+    condition: input.workload.properties.priority == "high"
+    decision:
+      policy: {policySetID: "2", ID: test-4}
+      deploy: "False"
+  # default scenario
+  - capability: copy
+    decision:
+      policy: {policySetID: "1", ID: default}
+optimize:
+  - condition: input.request.usage == "copy"
+    policy: {ID: save-cost, description: "Save storage costs", version: "0.1"}
+    strategy: [{attribute: storage-cost, directive: min}]
+`
+	evaluator, err := adminconfig.NewCELPolicyEvaluatorWithPolicies([]byte(policies))
+	Expect(err).ToNot(HaveOccurred())
+	return evaluator
+}
+
+func celEvaluatorInput(region, priority, severity string) *adminconfig.EvaluatorInput {
+	return &adminconfig.EvaluatorInput{Request: adminconfig.DataRequest{
+		Metadata: &datacatalog.ResourceMetadata{Geography: "theshire"}},
+		Workload: adminconfig.WorkloadInfo{
+			Cluster: multicluster.Cluster{
+				Name:     region + "-cluster",
+				Metadata: multicluster.ClusterMetadata{Region: region},
+			},
+			Properties: taxonomy.AppInfo{
+				Properties: serde.Properties{
+					Items: map[string]interface{}{
+						"stage":    "PROD",
+						"priority": priority,
+						"severity": severity,
+					},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Evaluate a CEL policy", func() {
+	evaluator := BaseCELEvaluator()
+
+	It("Conflict", func() {
+		out, err := evaluator.Evaluate(celEvaluatorInput("neverland", "high", "critical"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(false))
+	})
+
+	It("ValidSolution", func() {
+		out, err := evaluator.Evaluate(celEvaluatorInput("theshire", "medium", "low"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(true))
+		Expect(out.ConfigDecisions["copy"].Deploy).To(Equal(adminconfig.StatusFalse))
+	})
+
+	It("Merge", func() {
+		out, err := evaluator.Evaluate(celEvaluatorInput("neverland", "normal", "critical"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(true))
+		Expect(out.ConfigDecisions["copy"].Deploy).To(Equal(adminconfig.StatusTrue))
+		Expect(out.ConfigDecisions["copy"].DeploymentRestrictions.Clusters).To(HaveLen(2))
+		Expect(out.ConfigDecisions["copy"].DeploymentRestrictions.Modules).To(HaveLen(2))
+	})
+
+	It("No conflict for policy set 2", func() {
+		in := celEvaluatorInput("neverland", "high", "critical")
+		in.Workload.PolicySetID = "2"
+		out, err := evaluator.Evaluate(in)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(true))
+		Expect(out.ConfigDecisions["copy"].Deploy).To(Equal(adminconfig.StatusFalse))
+	})
+
+	It("Missing fields do not satisfy a condition", func() {
+		in := celEvaluatorInput("theshire", "medium", "low")
+		in.Request.Metadata = nil
+		out, err := evaluator.Evaluate(in)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(true))
+		Expect(out.ConfigDecisions["copy"].Deploy).To(Equal(adminconfig.StatusUnknown))
+	})
+
+	It("Optimization strategy", func() {
+		in := celEvaluatorInput("theshire", "medium", "low")
+		in.Request.Usage = taxonomy.CopyFlow
+		out, err := evaluator.Evaluate(in)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.OptimizationStrategy).To(HaveLen(1))
+		Expect(out.OptimizationStrategy[0].Attribute).To(Equal("storage-cost"))
+	})
+})
+
+var _ = Describe("Compile CEL policies", func() {
+	It("Invalid condition", func() {
+		_, err := adminconfig.NewCELPolicyEvaluatorWithPolicies([]byte(`
+config:
+  - capability: read
+    condition: input.request.usage ==
+    decision: {deploy: "True"}
+`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Non boolean condition", func() {
+		_, err := adminconfig.NewCELPolicyEvaluatorWithPolicies([]byte(`
+config:
+  - capability: read
+    condition: "'read'"
+    decision: {deploy: "True"}
+`))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Default policies", func() {
+		content, err := os.ReadFile("../../charts/fybrik/files/adminconfig/default_policies.cel.yaml")
+		Expect(err).ToNot(HaveOccurred())
+		evaluator, err := adminconfig.NewCELPolicyEvaluatorWithPolicies(content)
+		Expect(err).ToNot(HaveOccurred())
+		out, err := evaluator.Evaluate(&adminconfig.EvaluatorInput{Request: adminconfig.DataRequest{Usage: taxonomy.DeleteFlow}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(out.Valid).To(Equal(true))
+		Expect(out.ConfigDecisions["delete"].Deploy).To(Equal(adminconfig.StatusTrue))
+		Expect(out.ConfigDecisions["read"].Deploy).To(Equal(adminconfig.StatusFalse))
+		Expect(out.ConfigDecisions["copy"].Deploy).To(Equal(adminconfig.StatusFalse))
+	})
+})
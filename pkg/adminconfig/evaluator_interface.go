@@ -3,7 +3,44 @@
 
 package adminconfig
 
+import (
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/monitor"
+)
+
+// Supported languages of config policies
+const (
+	RegoLanguage string = "rego"
+	CELLanguage  string = "cel"
+)
+
 // EvaluatorInterface is an interface for config policies' evaluator
 type EvaluatorInterface interface {
 	Evaluate(in *EvaluatorInput) (EvaluatorOutput, error)
 }
+
+// MonitoredEvaluator is a config policies' evaluator that reloads the policies upon changes in policy files
+type MonitoredEvaluator interface {
+	EvaluatorInterface
+	monitor.Subscriber
+}
+
+// NewEvaluator constructs an evaluator for the config policy language set in the environment
+func NewEvaluator() (MonitoredEvaluator, error) {
+	var evaluator MonitoredEvaluator
+	var err error
+	switch language := environment.GetConfigPolicyLanguage(); language {
+	case RegoLanguage:
+		evaluator, err = NewRegoPolicyEvaluator()
+	case CELLanguage:
+		evaluator, err = NewCELPolicyEvaluator()
+	default:
+		return nil, errors.Errorf("unsupported config policy language %s", language)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return evaluator, nil
+}
@@ -8,6 +8,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+
 	"fybrik.io/fybrik/pkg/infrastructure"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 	"fybrik.io/fybrik/pkg/utils"
@@ -31,6 +33,8 @@ type Restriction struct {
 	Property string              `json:"property"`
 	Values   StringList          `json:"values,omitempty"`
 	Range    *taxonomy.RangeType `json:"range,omitempty"`
+	// Constraint is a semantic version constraint on the property value, e.g. "~1.2" or ">= 1.0, < 2.0"
+	Constraint string `json:"constraint,omitempty"`
 }
 
 // DecisionPolicy is a justification for a policy that consists of a unique id, id of a policy set and a human readable description
@@ -146,10 +150,30 @@ func (restrict Restriction) SatisfiedByResource(attrManager *infrastructure.Attr
 			return false
 		}
 	}
+	if restrict.Constraint != "" {
+		return satisfiesVersionConstraint(value, restrict.Constraint)
+	}
 
 	return true
 }
 
+// satisfiesVersionConstraint checks that the value is a semantic version that satisfies the given constraint
+func satisfiesVersionConstraint(value interface{}, constraint string) bool {
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+	version, err := semver.NewVersion(str)
+	if err != nil {
+		return false
+	}
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+	return constraints.Check(version)
+}
+
 func NestedFieldNoCopy(obj map[string]interface{}, fields ...string) (interface{}, bool, error) {
 	var val interface{} = obj
 
@@ -125,86 +125,12 @@ func (r *RegoPolicyEvaluator) getOPADecisions(in *EvaluatorInput, rs rego.Result
 			if err = yaml.Unmarshal(bytes, &evalStruct); err != nil {
 				return errors.Wrap(err, "Unexpected OPA response structure")
 			}
-			if !r.processConfigDecisions(&evalStruct, in, out) {
+			if !processConfigDecisions(&r.Log, &evalStruct, in, out) {
 				return nil
 			}
-			r.processOptimizeDecisions(&evalStruct, out)
+			processOptimizeDecisions(&evalStruct, out)
 		}
 	}
 	out.Valid = true
 	return nil
 }
-
-// merge config decisions
-// return true if there is no conflict
-func (r *RegoPolicyEvaluator) processConfigDecisions(evalStruct *EvaluationOutputStructure, in *EvaluatorInput, out *EvaluatorOutput) bool {
-	log := r.Log.With().Str(utils.FybrikAppUUID, in.Workload.UUID).Logger()
-	for ind := range evalStruct.Config {
-		rule := &evalStruct.Config[ind]
-		capability := rule.Capability
-		newDecision := rule.Decision
-		// filter by policySetID
-		if newDecision.Policy.PolicySetID != "" && in.Workload.PolicySetID != "" && newDecision.Policy.PolicySetID != in.Workload.PolicySetID {
-			continue
-		}
-		// apply defaults for undefined fields
-		if newDecision.Deploy == "" {
-			newDecision.Deploy = StatusUnknown
-		}
-		// a single decision should be made for a capability
-		decision, exists := out.ConfigDecisions[capability]
-		out.Policies = append(out.Policies, newDecision.Policy)
-		if !exists {
-			out.ConfigDecisions[capability] = newDecision
-		} else {
-			valid, mergedDecision := r.merge(&newDecision, &decision)
-			if !valid {
-				log.Error().Msg("Conflict while merging OPA decisions")
-				logging.LogStructure("Conflicting decisions", out, &log, zerolog.ErrorLevel, true, true)
-				return false
-			}
-			out.ConfigDecisions[capability] = mergedDecision
-		}
-	}
-	return true
-}
-
-func (r *RegoPolicyEvaluator) processOptimizeDecisions(evalStruct *EvaluationOutputStructure, out *EvaluatorOutput) {
-	out.OptimizationStrategy = []AttributeOptimization{}
-	if len(evalStruct.Optimize) > 0 {
-		// choose the first optimization strategy
-		// TODO(shlomitk1): add priorities to optimization strategies
-		rule := evalStruct.Optimize[0]
-		out.OptimizationStrategy = append(out.OptimizationStrategy, rule.Strategy...)
-		out.Policies = append(out.Policies, rule.Policy)
-	}
-}
-
-// This function merges two decisions for the same capability using the following logic:
-// deploy: true/false take precedence over undefined, true and false result in a conflict.
-// restrictions: new pairs <key, value> are added, if both exist - compatibility is checked.
-// policy: concatenation of IDs and descriptions.
-func (r *RegoPolicyEvaluator) merge(newDecision, oldDecision *Decision) (bool, Decision) {
-	mergedDecision := Decision{}
-	// merge deployment decisions
-	deploy := oldDecision.Deploy
-	if deploy == StatusUnknown {
-		deploy = newDecision.Deploy
-	} else if newDecision.Deploy != StatusUnknown {
-		if newDecision.Deploy != deploy {
-			return false, mergedDecision
-		}
-	}
-	mergedDecision.Deploy = deploy
-	// merge restrictions
-	mergedDecision.DeploymentRestrictions = oldDecision.DeploymentRestrictions
-	mergedDecision.DeploymentRestrictions.Clusters = append(mergedDecision.DeploymentRestrictions.Clusters,
-		newDecision.DeploymentRestrictions.Clusters...)
-	mergedDecision.DeploymentRestrictions.Modules = append(mergedDecision.DeploymentRestrictions.Modules,
-		newDecision.DeploymentRestrictions.Modules...)
-	mergedDecision.DeploymentRestrictions.StorageAccounts = append(mergedDecision.DeploymentRestrictions.StorageAccounts,
-		newDecision.DeploymentRestrictions.StorageAccounts...)
-	// policies are appended to the output, no need to merge
-	mergedDecision.Policy = DecisionPolicy{}
-	return true, mergedDecision
-}
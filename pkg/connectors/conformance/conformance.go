@@ -0,0 +1,311 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/pkg/validate"
+)
+
+// Connector types that can be tested
+const (
+	DataCatalog    = "datacatalog"
+	PolicyManager  = "policymanager"
+	StorageManager = "storagemanager"
+)
+
+// Types lists the connector types that can be tested
+var Types = []string{DataCatalog, PolicyManager, StorageManager}
+
+const (
+	// malformedBody is sent to check that a connector rejects a request body that is not valid JSON
+	malformedBody = `{"`
+	// maxBodyLength is the length of a response body that is shown in a report
+	maxBodyLength = 200
+)
+
+// Config of a conformance test of a connector
+type Config struct {
+	// URL of the connector
+	URL string
+	// TaxonomyDir is the directory of the taxonomy schemas that the responses are validated against
+	TaxonomyDir string
+	// Credentials are sent in the credentials header of the requests
+	Credentials string
+	// CatalogID is the catalog in which the data catalog test creates an asset
+	CatalogID string
+	// AssetID is the asset in the requests of the policy manager test
+	AssetID string
+	// HTTPClient sends the requests to the connector
+	HTTPClient *http.Client
+}
+
+// Status of a check
+type Status string
+
+const (
+	Pass Status = "PASS"
+	Fail Status = "FAIL"
+	Skip Status = "SKIP"
+)
+
+// Result of a single check of an operation
+type Result struct {
+	Operation   string
+	Description string
+	Status      Status
+	// Message explains why the check failed or was skipped
+	Message string
+}
+
+// Report holds the results of the checks of a connector
+type Report struct {
+	Type    string
+	URL     string
+	Results []Result
+}
+
+// Count returns the number of checks with the given status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for i := range r.Results {
+		if r.Results[i].Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Passed returns true if none of the checks failed
+func (r *Report) Passed() bool {
+	return r.Count(Fail) == 0
+}
+
+// Print writes the report as a table followed by a summary line
+func (r *Report) Print(out io.Writer) error {
+	if _, err := fmt.Fprintf(out, "Conformance of %s connector %s\n\n", r.Type, r.URL); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, result := range r.Results {
+		line := fmt.Sprintf("%s\t%s\t%s", result.Status, result.Operation, result.Description)
+		if result.Message != "" {
+			line += "\t" + result.Message
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\n%d passed, %d failed, %d skipped\n", r.Count(Pass), r.Count(Fail), r.Count(Skip))
+	return err
+}
+
+// Run runs the checks of the operations of the given connector type, as defined in connectors/api/<type>.spec.yaml.
+// Every operation is called with valid and invalid payloads; the status codes and the shape of the error bodies are
+// checked, and the responses are validated against the taxonomy schemas that the manager uses.
+func Run(ctx context.Context, connectorType string, config *Config) (*Report, error) {
+	if config.URL == "" {
+		return nil, errors.New("the url of the connector is not set")
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	t := &tester{
+		ctx:    ctx,
+		config: config,
+		report: &Report{Type: connectorType, URL: config.URL},
+	}
+	switch connectorType {
+	case DataCatalog:
+		t.testDataCatalog()
+	case PolicyManager:
+		t.testPolicyManager()
+	case StorageManager:
+		t.testStorageManager()
+	default:
+		return nil, errors.Errorf("unknown connector type %s (must be one of %s)", connectorType, strings.Join(Types, ", "))
+	}
+	return t.report, nil
+}
+
+type tester struct {
+	ctx    context.Context
+	config *Config
+	report *Report
+}
+
+// call describes a request to an operation of the connector
+type call struct {
+	operation   string
+	method      string
+	credsHeader string
+}
+
+func (t *tester) record(c *call, description string, status Status, message string) {
+	t.report.Results = append(t.report.Results, Result{
+		Operation:   c.operation,
+		Description: description,
+		Status:      status,
+		Message:     message,
+	})
+}
+
+func (t *tester) skip(c *call, description, reason string) {
+	t.record(c, description, Skip, reason)
+}
+
+// send sends the body to the operation and returns the status code and the body of the response
+func (t *tester) send(c *call, body []byte) (int, []byte, error) {
+	url := strings.TrimSuffix(t.config.URL, "/") + "/" + c.operation
+	req, err := http.NewRequestWithContext(t.ctx, c.method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.credsHeader != "" {
+		req.Header.Set(c.credsHeader, t.config.Credentials)
+	}
+	resp, err := t.config.HTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, respBody, err
+}
+
+// expectation of the response to a valid request
+type expectation struct {
+	statuses []int
+	// schema is the taxonomy file and definition that the response is validated against, e.g.,
+	// datacatalog.json#/definitions/GetAssetResponse
+	schema string
+	// verify checks the content of the response
+	verify func(body []byte) error
+}
+
+// expectSuccess sends a valid request, checks that the response has one of the expected status codes,
+// validates the response body against the schema, and verifies its content.
+// It returns the response body if the check passed and nil otherwise.
+func (t *tester) expectSuccess(c *call, description string, request interface{}, expected *expectation) []byte {
+	var body []byte
+	if request != nil {
+		var err error
+		if body, err = json.Marshal(request); err != nil {
+			t.record(c, description, Fail, err.Error())
+			return nil
+		}
+	}
+	status, respBody, err := t.send(c, body)
+	if err != nil {
+		t.record(c, description, Fail, err.Error())
+		return nil
+	}
+	if !hasStatus(status, expected.statuses) {
+		t.record(c, description, Fail, unexpectedStatus(status, expected.statuses, respBody))
+		return nil
+	}
+	if expected.schema != "" {
+		if err = t.validateResponse(respBody, expected.schema); err != nil {
+			t.record(c, description, Fail, err.Error())
+			return nil
+		}
+	}
+	if expected.verify != nil {
+		if err = expected.verify(respBody); err != nil {
+			t.record(c, description, Fail, err.Error())
+			return nil
+		}
+	}
+	t.record(c, description, Pass, "")
+	return respBody
+}
+
+// expectError sends a request that the connector must reject, checks that the response has one of the
+// given status codes, and that the body is a JSON object with an "error" message.
+func (t *tester) expectError(c *call, description string, body []byte, statuses ...int) {
+	status, respBody, err := t.send(c, body)
+	if err != nil {
+		t.record(c, description, Fail, err.Error())
+		return
+	}
+	if !hasStatus(status, statuses) {
+		t.record(c, description, Fail, unexpectedStatus(status, statuses, respBody))
+		return
+	}
+	if err = checkErrorBody(respBody); err != nil {
+		t.record(c, description, Fail, err.Error())
+		return
+	}
+	t.record(c, description, Pass, "")
+}
+
+// validateResponse validates the response body against a schema in the taxonomy directory
+func (t *tester) validateResponse(body []byte, schema string) error {
+	allErrs, err := validate.TaxonomyCheck(body, filepath.Join(t.config.TaxonomyDir, schema))
+	if err != nil {
+		return errors.Wrap(err, "response is not valid JSON: "+truncate(body))
+	}
+	if len(allErrs) > 0 {
+		messages := make([]string, len(allErrs))
+		for i, fieldErr := range allErrs {
+			messages[i] = fieldErr.Error()
+		}
+		return errors.Errorf("response does not match %s: %s", schema, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// checkErrorBody checks that the body of an error response is a JSON object with an "error" message,
+// as returned by the connectors of Fybrik
+func checkErrorBody(body []byte) error {
+	var errorBody map[string]interface{}
+	if err := json.Unmarshal(body, &errorBody); err != nil {
+		return errors.New("error body is not a JSON object: " + truncate(body))
+	}
+	if message, ok := errorBody["error"].(string); !ok || message == "" {
+		return errors.New(`error body has no "error" message: ` + truncate(body))
+	}
+	return nil
+}
+
+func hasStatus(status int, statuses []int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func unexpectedStatus(status int, statuses []int, body []byte) string {
+	expected := make([]string, len(statuses))
+	for i, s := range statuses {
+		expected[i] = fmt.Sprint(s)
+	}
+	return fmt.Sprintf("expected status %s, got %d: %s", strings.Join(expected, " or "), status, truncate(body))
+}
+
+func truncate(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) > maxBodyLength {
+		return s[:maxBodyLength] + "..."
+	}
+	return s
+}
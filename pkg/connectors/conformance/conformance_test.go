@@ -0,0 +1,99 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/connectors/filecatalog/pkg/connector"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+)
+
+const taxonomyDir = "../../../charts/fybrik/files/taxonomy"
+
+func TestDataCatalog(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	store, err := connector.NewStore(t.TempDir(), false)
+	g.Expect(err).ToNot(HaveOccurred())
+	server := httptest.NewServer(connector.NewRouter(connector.NewHandler(store)))
+	defer server.Close()
+
+	report, err := Run(context.Background(), DataCatalog, &Config{URL: server.URL, TaxonomyDir: taxonomyDir, CatalogID: "finance"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Results).To(HaveLen(17))
+	g.Expect(report.Count(Pass)).To(Equal(len(report.Results)), printReport(g, report))
+}
+
+// newPolicyManager returns a policy manager that allows every request, and that responds to invalid requests with
+// the given status code and error body
+func newPolicyManager(errorCode int, errorBody gin.H) *httptest.Server {
+	router := gin.New()
+	router.POST("/getPoliciesDecisions", func(c *gin.Context) {
+		var request policymanager.GetPolicyDecisionsRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(errorCode, errorBody)
+			return
+		}
+		c.JSON(http.StatusOK, &policymanager.GetPolicyDecisionsResponse{DecisionID: "1", Result: []policymanager.ResultItem{}})
+	})
+	return httptest.NewServer(router)
+}
+
+func TestPolicyManager(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	server := newPolicyManager(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	defer server.Close()
+
+	report, err := Run(context.Background(), PolicyManager, &Config{URL: server.URL, TaxonomyDir: taxonomyDir, AssetID: "a"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Results).To(HaveLen(4))
+	g.Expect(report.Passed()).To(BeTrue(), printReport(g, report))
+}
+
+func TestPolicyManagerErrors(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	server := newPolicyManager(http.StatusInternalServerError, gin.H{"Error": "invalid request"})
+	defer server.Close()
+
+	report, err := Run(context.Background(), PolicyManager, &Config{URL: server.URL, TaxonomyDir: taxonomyDir, AssetID: "a"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(report.Passed()).To(BeFalse())
+	g.Expect(report.Count(Fail)).To(Equal(2), printReport(g, report))
+	g.Expect(report.Results[2].Message).To(HavePrefix("expected status 400, got 500"))
+}
+
+func TestErrorBody(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	g.Expect(checkErrorBody([]byte(`{"error": "asset not found"}`))).To(Succeed())
+	g.Expect(checkErrorBody([]byte(`{"Error": "asset not found"}`))).ToNot(Succeed())
+	g.Expect(checkErrorBody([]byte(`{"error": ""}`))).ToNot(Succeed())
+	g.Expect(checkErrorBody([]byte(`asset not found`))).ToNot(Succeed())
+}
+
+func TestUnknownType(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+	_, err := Run(context.Background(), "catalog", &Config{URL: "http://localhost"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func printReport(g *WithT, report *Report) string {
+	var out bytes.Buffer
+	g.Expect(report.Print(&out)).To(Succeed())
+	return out.String()
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"net/http"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// GetPolicyDecisionsResponseSchema is the schema of the policy manager response, as validated by the manager
+const GetPolicyDecisionsResponseSchema = "policymanager.json#/definitions/GetPolicyDecisionsResponse"
+
+var getPoliciesDecisionsCall = &call{operation: "getPoliciesDecisions", method: http.MethodPost, credsHeader: "X-Request-Cred"}
+
+// testPolicyManager requests the decisions for reading and writing an asset, and calls the policy manager
+// with invalid payloads
+func (t *tester) testPolicyManager() {
+	resource := policymanager.Resource{
+		ID: taxonomy.AssetID(t.config.AssetID),
+		Metadata: &datacatalog.ResourceMetadata{
+			Name:      t.config.AssetID,
+			Geography: "theshire",
+			Tags:      &taxonomy.Tags{Properties: serde.Properties{Items: map[string]interface{}{"finance": true}}},
+			Columns:   []datacatalog.ResourceColumn{{Name: "id"}},
+		},
+	}
+	requestContext := taxonomy.PolicyManagerRequestContext{Properties: serde.Properties{Items: map[string]interface{}{
+		"intent": "Fraud Detection",
+	}}}
+	expected := &expectation{statuses: []int{http.StatusOK}, schema: GetPolicyDecisionsResponseSchema}
+
+	readRequest := &policymanager.GetPolicyDecisionsRequest{
+		Context:  requestContext,
+		Action:   policymanager.RequestAction{ActionType: taxonomy.ReadFlow, ProcessingLocation: "theshire"},
+		Resource: resource,
+	}
+	t.expectSuccess(getPoliciesDecisionsCall, "returns the decisions for reading an asset", readRequest, expected)
+
+	writeRequest := &policymanager.GetPolicyDecisionsRequest{
+		Context:  requestContext,
+		Action:   policymanager.RequestAction{ActionType: taxonomy.WriteFlow, Destination: "neverland"},
+		Resource: resource,
+	}
+	t.expectSuccess(getPoliciesDecisionsCall, "returns the decisions for writing an asset", writeRequest, expected)
+
+	t.expectError(getPoliciesDecisionsCall, "rejects a malformed body", []byte(malformedBody), http.StatusBadRequest)
+	t.expectError(getPoliciesDecisionsCall, "rejects a field of the wrong type", []byte(`{"action": "read"}`),
+		http.StatusBadRequest)
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package conformance
+
+import (
+	"net/http"
+
+	"fybrik.io/fybrik/pkg/model/storagemanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// GetSupportedStorageTypesResponseSchema is the schema of the supported storage types, as validated by the manager
+const GetSupportedStorageTypesResponseSchema = "storagemanager.json#/definitions/GetSupportedStorageTypesResponse"
+
+// unsupportedStorageType is a connection type that no storage manager supports
+const unsupportedStorageType taxonomy.ConnectionType = "fybrik-conformance-unsupported"
+
+var (
+	allocateStorageCall          = &call{operation: "allocateStorage", method: http.MethodPost}
+	deleteStorageCall            = &call{operation: "deleteStorage", method: http.MethodDelete}
+	getSupportedStorageTypesCall = &call{operation: "getSupportedStorageTypes", method: http.MethodPost}
+)
+
+// testStorageManager checks the operations of the storage manager that do not require a storage account:
+// the allocation and deletion of storage are called with invalid payloads and with an unsupported storage type.
+func (t *tester) testStorageManager() {
+	t.expectSuccess(getSupportedStorageTypesCall, "returns the supported storage types", nil,
+		&expectation{statuses: []int{http.StatusOK}, schema: GetSupportedStorageTypesResponseSchema})
+
+	allocateRequest := &storagemanager.AllocateStorageRequest{AccountType: unsupportedStorageType}
+	t.expectError(allocateStorageCall, "rejects an unsupported storage type", mustMarshal(allocateRequest),
+		http.StatusNotImplemented)
+	t.expectError(allocateStorageCall, "rejects a malformed body", []byte(malformedBody), http.StatusBadRequest)
+	t.expectError(allocateStorageCall, "rejects a field of the wrong type", []byte(`{"accountType": 42}`),
+		http.StatusBadRequest)
+
+	deleteRequest := &storagemanager.DeleteStorageRequest{Connection: taxonomy.Connection{Name: unsupportedStorageType}}
+	t.expectError(deleteStorageCall, "rejects an unsupported storage type", mustMarshal(deleteRequest),
+		http.StatusNotImplemented)
+	t.expectError(deleteStorageCall, "rejects a malformed body", []byte(malformedBody), http.StatusBadRequest)
+	t.expectError(deleteStorageCall, "rejects a field of the wrong type", []byte(`{"connection": 42}`),
+		http.StatusBadRequest)
+}
@@ -4,20 +4,43 @@
 package clients
 
 import (
+	"context"
 	"io"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 )
 
 // DataCatalog is an interface of a facade to a data catalog.
 type DataCatalog interface {
-	GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error)
-	CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error)
-	DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error)
-	UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error)
+	GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error)
+	CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error)
+	DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error)
+	UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error)
 	io.Closer
 }
 
+// NewDataCatalog creates a DataCatalog facade to the default data catalog connector. If a catalog registry is
+// configured, the requests for the catalogs and the assets of the registry are routed to their connectors.
+// The default connector is called with gRPC if its address has a grpc:// or grpcs:// scheme, and with REST otherwise.
 func NewDataCatalog(catalogProviderName, catalogConnectorAddress string) (DataCatalog, error) {
-	return NewOpenAPIDataCatalog(catalogProviderName, catalogConnectorAddress), nil
+	var catalog DataCatalog
+	if rpc.IsGRPCURL(catalogConnectorAddress) {
+		var err error
+		if catalog, err = NewGRPCDataCatalog(catalogProviderName, catalogConnectorAddress); err != nil {
+			return nil, err
+		}
+	} else {
+		catalog = NewOpenAPIDataCatalog(catalogProviderName, catalogConnectorAddress)
+	}
+	registryFile := environment.GetCatalogRegistryFile()
+	if registryFile == "" {
+		return catalog, nil
+	}
+	registry, err := ReadCatalogRegistry(registryFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewRoutedDataCatalog(catalog, registry)
 }
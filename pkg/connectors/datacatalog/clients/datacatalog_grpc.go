@@ -0,0 +1,172 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"fybrik.io/fybrik/pkg/connectors/protos"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+var (
+	_ DataCatalog     = (*grpcDataCatalog)(nil)
+	_ BulkDataCatalog = (*grpcDataCatalog)(nil)
+)
+
+// BulkDataCatalog is implemented by the data catalog facades that can get the information of several assets in a
+// single call
+type BulkDataCatalog interface {
+	BulkGetAssetInfo(ctx context.Context, in []*datacatalog.GetAssetRequest, creds string) ([]*rpc.AssetInfoResult, error)
+}
+
+type grpcDataCatalog struct {
+	name   string
+	conn   *grpc.ClientConn
+	client protos.DataCatalogClient
+}
+
+// NewGRPCDataCatalog creates a DataCatalog facade that connects to a gRPC service at a grpc:// or grpcs:// URL
+func NewGRPCDataCatalog(name, connectionURL string) (DataCatalog, error) {
+	log := logging.LogInit(logging.SETUP, "datacatalog client")
+	conn, err := rpc.Dial(connectionURL, &log)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcDataCatalog{
+		name:   name,
+		conn:   conn,
+		client: protos.NewDataCatalogClient(conn),
+	}, nil
+}
+
+// getDetailedGRPCError generates an error from the gRPC status in the same format as getDetailedError,
+// with the status code of the REST API, e.g., "404: asset does not exist"
+func getDetailedGRPCError(err error, printErr string) error {
+	if _, ok := status.FromError(err); !ok {
+		return errors.Wrap(err, printErr)
+	}
+	code, message := rpc.HTTPStatus(err)
+	if message == "" {
+		switch code {
+		case http.StatusForbidden:
+			message = AccessForbidden
+		case http.StatusNotFound:
+			message = AssetIDNotFound
+		default:
+			message = printErr
+		}
+	}
+	return errors.Wrapf(errors.New(message), "%d", code)
+}
+
+func (m *grpcDataCatalog) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (response *datacatalog.GetAssetResponse, err error) {
+	defer func(start time.Time) { observe("getAssetInfo", start, err) }(time.Now())
+	response = &datacatalog.GetAssetResponse{}
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.DataCatalogCredsKey, creds)
+	if err = rpc.Invoke(ctx, m.client.GetAssetInfo, in, &protos.GetAssetRequest{}, response); err != nil {
+		return nil, getDetailedGRPCError(err, fmt.Sprintf("get asset info from %s failed", m.name))
+	}
+	return response, nil
+}
+
+func (m *grpcDataCatalog) CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest,
+	creds string) (response *datacatalog.CreateAssetResponse, err error) {
+	defer func(start time.Time) { observe("createAsset", start, err) }(time.Now())
+	response = &datacatalog.CreateAssetResponse{}
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.DataCatalogWriteCredsKey, creds)
+	if err = rpc.Invoke(ctx, m.client.CreateAsset, in, &protos.CreateAssetRequest{}, response); err != nil {
+		return nil, getDetailedGRPCError(err, fmt.Sprintf("create asset info from %s failed", m.name))
+	}
+	return response, nil
+}
+
+func (m *grpcDataCatalog) DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest,
+	creds string) (response *datacatalog.DeleteAssetResponse, err error) {
+	defer func(start time.Time) { observe("deleteAsset", start, err) }(time.Now())
+	response = &datacatalog.DeleteAssetResponse{}
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.DataCatalogCredsKey, creds)
+	if err = rpc.Invoke(ctx, m.client.DeleteAsset, in, &protos.DeleteAssetRequest{}, response); err != nil {
+		return nil, getDetailedGRPCError(err, fmt.Sprintf("delete asset info from %s failed", m.name))
+	}
+	return response, nil
+}
+
+func (m *grpcDataCatalog) UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest,
+	creds string) (response *datacatalog.UpdateAssetResponse, err error) {
+	defer func(start time.Time) { observe("updateAsset", start, err) }(time.Now())
+	response = &datacatalog.UpdateAssetResponse{}
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.DataCatalogUpdateCredsKey, creds)
+	if err = rpc.Invoke(ctx, m.client.UpdateAsset, in, &protos.UpdateAssetRequest{}, response); err != nil {
+		return nil, getDetailedGRPCError(err, fmt.Sprintf("update asset info from %s failed", m.name))
+	}
+	return response, nil
+}
+
+// BulkGetAssetInfo gets the information of several assets in a single stream. The results are in the order of the
+// requests, and a failed request does not fail the others.
+func (m *grpcDataCatalog) BulkGetAssetInfo(ctx context.Context, in []*datacatalog.GetAssetRequest,
+	creds string) ([]*rpc.AssetInfoResult, error) {
+	printErr := fmt.Sprintf("get asset info from %s failed", m.name)
+	// the stream is canceled when a result cannot be received, which stops sending the requests
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, rpc.DataCatalogCredsKey, creds))
+	defer cancel()
+	requests := make([]*protos.GetAssetRequest, len(in))
+	for i, request := range in {
+		requests[i] = &protos.GetAssetRequest{}
+		if err := rpc.ToMessage(request, requests[i]); err != nil {
+			return nil, errors.Wrap(err, printErr)
+		}
+	}
+	stream, err := m.client.BulkGetAssetInfo(ctx)
+	if err != nil {
+		return nil, getDetailedGRPCError(err, printErr)
+	}
+	// the requests are sent while the results are received, so that neither side blocks on a full stream
+	sendErr := make(chan error, 1)
+	go func() {
+		for _, request := range requests {
+			if errSend := stream.Send(request); errSend != nil {
+				sendErr <- errSend
+				return
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+	results := make([]*rpc.AssetInfoResult, len(in))
+	for i := range results {
+		var msg *protos.GetAssetInfoResult
+		if msg, err = stream.Recv(); err != nil {
+			return nil, getDetailedGRPCError(err, printErr)
+		}
+		results[i] = &rpc.AssetInfoResult{}
+		if msg.Error != nil {
+			results[i].Error = &rpc.ErrorDetails{Code: int(msg.Error.Code), Message: msg.Error.Message}
+			continue
+		}
+		results[i].Response = &datacatalog.GetAssetResponse{}
+		if err = rpc.FromMessage(msg.Response, results[i].Response); err != nil {
+			return nil, getDetailedGRPCError(err, printErr)
+		}
+	}
+	if err = <-sendErr; err != nil {
+		return nil, getDetailedGRPCError(err, printErr)
+	}
+	return results, nil
+}
+
+func (m *grpcDataCatalog) Close() error {
+	return m.conn.Close()
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+)
+
+// fakeCatalogServer serves the assets of the "ns" namespace and records the credentials and the deadline of the
+// last request
+type fakeCatalogServer struct {
+	creds    string
+	deadline time.Time
+}
+
+func (s *fakeCatalogServer) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
+	s.creds = creds
+	s.deadline, _ = ctx.Deadline()
+	if !strings.HasPrefix(string(in.AssetID), "ns/") {
+		return nil, rpc.NewError(http.StatusNotFound, "asset "+string(in.AssetID)+" does not exist")
+	}
+	return &datacatalog.GetAssetResponse{ResourceMetadata: datacatalog.ResourceMetadata{Name: string(in.AssetID)}}, nil
+}
+
+func (s *fakeCatalogServer) CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
+	s.creds = creds
+	return &datacatalog.CreateAssetResponse{AssetID: string(in.DestinationCatalogID) + "/asset"}, nil
+}
+
+func (s *fakeCatalogServer) DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
+	return nil, rpc.NewError(http.StatusForbidden, "")
+}
+
+func (s *fakeCatalogServer) UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
+	s.creds = creds
+	return &datacatalog.UpdateAssetResponse{Status: "updated"}, nil
+}
+
+func TestGRPCDataCatalog(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	server := grpc.NewServer()
+	fake := &fakeCatalogServer{}
+	rpc.RegisterDataCatalogServer(server, fake)
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	catalog, err := NewDataCatalog("katalog", "grpc://"+listener.Addr().String())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer catalog.Close()
+
+	// the deadline of the caller is propagated to the connector
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	asset, err := catalog.GetAssetInfo(ctx, &datacatalog.GetAssetRequest{AssetID: "ns/data"}, "read-creds")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(asset.ResourceMetadata.Name).To(gomega.Equal("ns/data"))
+	g.Expect(fake.creds).To(gomega.Equal("read-creds"))
+	g.Expect(fake.deadline).To(gomega.BeTemporally("~", deadline, time.Second))
+
+	// errors have the status code of the REST API as a prefix, as expected by the manager
+	_, err = catalog.GetAssetInfo(ctx, &datacatalog.GetAssetRequest{AssetID: "other/data"}, "")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.HavePrefix("404: asset other/data does not exist"))
+	_, err = catalog.DeleteAsset(ctx, &datacatalog.DeleteAssetRequest{AssetID: "ns/data"}, "")
+	g.Expect(err).To(gomega.HaveOccurred())
+	g.Expect(err.Error()).To(gomega.Equal("403: " + AccessForbidden))
+
+	created, err := catalog.CreateAsset(ctx, &datacatalog.CreateAssetRequest{DestinationCatalogID: "ns"}, "write-creds")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(created.AssetID).To(gomega.Equal("ns/asset"))
+	g.Expect(fake.creds).To(gomega.Equal("write-creds"))
+
+	updated, err := catalog.UpdateAsset(ctx, &datacatalog.UpdateAssetRequest{AssetID: "ns/data"}, "update-creds")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(updated.Status).To(gomega.Equal("updated"))
+	g.Expect(fake.creds).To(gomega.Equal("update-creds"))
+
+	// the results of a bulk request are in the order of the requests, and a failed request does not fail the others
+	bulk, ok := catalog.(BulkDataCatalog)
+	g.Expect(ok).To(gomega.BeTrue())
+	requests := []*datacatalog.GetAssetRequest{{AssetID: "ns/a"}, {AssetID: "other/b"}, {AssetID: "ns/c"}}
+	results, err := bulk.BulkGetAssetInfo(ctx, requests, "read-creds")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(results).To(gomega.HaveLen(3))
+	g.Expect(results[0].Response.ResourceMetadata.Name).To(gomega.Equal("ns/a"))
+	g.Expect(results[1].Error).To(gomega.Equal(&rpc.ErrorDetails{Code: http.StatusNotFound, Message: "asset other/b does not exist"}))
+	g.Expect(results[2].Response.ResourceMetadata.Name).To(gomega.Equal("ns/c"))
+}
@@ -8,13 +8,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"emperror.dev/errors"
 
 	"fybrik.io/fybrik/pkg/connectors/datacatalog/openapiclient"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/datacatalog"
 	"fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 // ErrorMessages that are reported to the user
@@ -34,6 +37,11 @@ type openAPIDataCatalog struct {
 
 func NewOpenAPIDataCatalog(name, connectionURL string) DataCatalog {
 	log := logging.LogInit(logging.SETUP, "datacatalog client")
+	return newOpenAPIDataCatalog(name, connectionURL, tls.GetHTTPClient(&log).StandardClient())
+}
+
+// newOpenAPIDataCatalog creates a DataCatalog facade that connects to a openApi service with the given http client
+func newOpenAPIDataCatalog(name, connectionURL string, httpClient *http.Client) DataCatalog {
 	configuration := &openapiclient.Configuration{
 		DefaultHeader: make(map[string]string),
 		UserAgent:     "OpenAPI-Generator/1.0.0/go",
@@ -45,7 +53,7 @@ func NewOpenAPIDataCatalog(name, connectionURL string) DataCatalog {
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       tracing.WrapClient(httpClient),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
@@ -72,10 +80,12 @@ func getDetailedError(httpResponse *http.Response, defaultErr error) error {
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error) {
+func (m *openAPIDataCatalog) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (response *datacatalog.GetAssetResponse, err error) {
+	defer func(start time.Time) { observe("getAssetInfo", start, err) }(time.Now())
 	printErr := func() string { return fmt.Sprintf("get asset info from %s failed", m.name) }
 	resp, httpResponse, err :=
-		m.client.DefaultApi.GetAssetInfo(context.Background()).XRequestDatacatalogCred(creds).GetAssetRequest(*in).Execute()
+		m.client.DefaultApi.GetAssetInfo(ctx).XRequestDatacatalogCred(creds).GetAssetRequest(*in).Execute()
 
 	if httpResponse == nil {
 		if err != nil {
@@ -91,9 +101,11 @@ func (m *openAPIDataCatalog) GetAssetInfo(in *datacatalog.GetAssetRequest, creds
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error) {
+func (m *openAPIDataCatalog) CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest,
+	creds string) (response *datacatalog.CreateAssetResponse, err error) {
+	defer func(start time.Time) { observe("createAsset", start, err) }(time.Now())
 	printErr := func() string { return fmt.Sprintf("create asset info from %s failed", m.name) }
-	resp, httpResponse, err := m.client.DefaultApi.CreateAsset(context.Background()).
+	resp, httpResponse, err := m.client.DefaultApi.CreateAsset(ctx).
 		XRequestDatacatalogWriteCred(creds).CreateAssetRequest(*in).Execute()
 	if httpResponse == nil {
 		if err != nil {
@@ -110,10 +122,12 @@ func (m *openAPIDataCatalog) CreateAsset(in *datacatalog.CreateAssetRequest, cre
 }
 
 //nolint:dupl
-func (m *openAPIDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error) {
+func (m *openAPIDataCatalog) DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest,
+	creds string) (response *datacatalog.DeleteAssetResponse, err error) {
+	defer func(start time.Time) { observe("deleteAsset", start, err) }(time.Now())
 	printErr := func() string { return fmt.Sprintf("delete asset info from %s failed", m.name) }
 	resp, httpResponse, err :=
-		m.client.DefaultApi.DeleteAsset(context.Background()).XRequestDatacatalogCred(creds).DeleteAssetRequest(*in).Execute()
+		m.client.DefaultApi.DeleteAsset(ctx).XRequestDatacatalogCred(creds).DeleteAssetRequest(*in).Execute()
 	if httpResponse == nil {
 		if err != nil {
 			return nil, errors.Wrap(err, printErr())
@@ -127,9 +141,11 @@ func (m *openAPIDataCatalog) DeleteAsset(in *datacatalog.DeleteAssetRequest, cre
 	return &resp, nil
 }
 
-func (m *openAPIDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error) {
+func (m *openAPIDataCatalog) UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest,
+	creds string) (response *datacatalog.UpdateAssetResponse, err error) {
+	defer func(start time.Time) { observe("updateAsset", start, err) }(time.Now())
 	resp, httpResponse, err := m.client.DefaultApi.UpdateAsset(
-		context.Background()).XRequestDatacatalogUpdateCred(creds).UpdateAssetRequest(*in).Execute()
+		ctx).XRequestDatacatalogUpdateCred(creds).UpdateAssetRequest(*in).Execute()
 	printErr := func() string { return fmt.Sprintf("update asset info from %s failed", m.name) }
 	if httpResponse == nil {
 		if err != nil {
@@ -147,3 +163,8 @@ func (m *openAPIDataCatalog) UpdateAsset(in *datacatalog.UpdateAssetRequest, cre
 func (m *openAPIDataCatalog) Close() error {
 	return nil
 }
+
+// observe records the latency and the outcome of a data catalog request
+func observe(operation string, start time.Time, err error) {
+	metrics.ObserveConnectorRequest(metrics.DataCatalogConnector, operation, start, err)
+}
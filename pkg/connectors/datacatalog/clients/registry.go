@@ -0,0 +1,211 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/go-retryablehttp"
+	"sigs.k8s.io/yaml"
+
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/tls"
+)
+
+// CatalogConnectorTLS defines the certificates that are used to connect to a data catalog connector
+type CatalogConnectorTLS struct {
+	// CACertFile is a file holding the CA certificate of the connector
+	CACertFile string `json:"caCertFile,omitempty"`
+	// CertFile is a file holding the client certificate of the manager
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is a file holding the private key of the client certificate
+	KeyFile string `json:"keyFile,omitempty"`
+}
+
+// CatalogConnector defines a data catalog connector in the catalog registry
+type CatalogConnector struct {
+	// Name of the connector
+	Name string `json:"name"`
+	// URL of the connector
+	URL string `json:"url"`
+	// CatalogIDs are the catalogs in which the connector registers new assets
+	CatalogIDs []string `json:"catalogIDs,omitempty"`
+	// AssetIDPrefixes are the prefixes of the identifiers of the assets that the connector serves
+	AssetIDPrefixes []string `json:"assetIDPrefixes,omitempty"`
+	// TLS defines the certificates that are used to connect to the connector.
+	// The certificates of the manager are used if not set.
+	TLS *CatalogConnectorTLS `json:"tls,omitempty"`
+	// TokenFile is a file holding a bearer token that is sent to the connector
+	TokenFile string `json:"tokenFile,omitempty"`
+}
+
+// CatalogRegistry is the configuration of the data catalog connectors in addition to the default one
+type CatalogRegistry struct {
+	Connectors []CatalogConnector `json:"connectors"`
+}
+
+// ReadCatalogRegistry reads the catalog registry from a yaml file
+func ReadCatalogRegistry(file string) (*CatalogRegistry, error) {
+	content, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the catalog registry")
+	}
+	registry := &CatalogRegistry{}
+	if err = yaml.Unmarshal(content, registry); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the catalog registry")
+	}
+	return registry, nil
+}
+
+var _ DataCatalog = (*routedDataCatalog)(nil)
+
+// assetIDRoute routes the assets with identifiers that start with the prefix to the catalog
+type assetIDRoute struct {
+	prefix  string
+	catalog DataCatalog
+}
+
+// routedDataCatalog is a DataCatalog facade that routes the requests to the data catalog connectors of the registry.
+// Existing assets are routed by the longest prefix of their identifier, and new assets by their destination catalog.
+// The requests that match no connector of the registry are sent to the default connector.
+type routedDataCatalog struct {
+	defaultCatalog DataCatalog
+	catalogs       map[string]DataCatalog
+	routes         []assetIDRoute
+	connectors     []DataCatalog
+}
+
+// NewRoutedDataCatalog creates a DataCatalog facade that routes the requests to the connectors of the registry,
+// or to the default catalog if no connector of the registry serves the request
+func NewRoutedDataCatalog(defaultCatalog DataCatalog, registry *CatalogRegistry) (DataCatalog, error) {
+	log := logging.LogInit(logging.SETUP, "datacatalog client")
+	r := &routedDataCatalog{defaultCatalog: defaultCatalog, catalogs: map[string]DataCatalog{}}
+	for i := range registry.Connectors {
+		connector := &registry.Connectors[i]
+		if connector.Name == "" || connector.URL == "" {
+			return nil, errors.Errorf("catalog connector %d must have a name and a url", i)
+		}
+		if rpc.IsGRPCURL(connector.URL) {
+			return nil, errors.Errorf("catalog connector %s must have an http or https url", connector.Name)
+		}
+		httpClient, err := newConnectorHTTPClient(connector)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to configure the http client of catalog connector "+connector.Name)
+		}
+		catalog := newOpenAPIDataCatalog(connector.Name, connector.URL, httpClient)
+		r.connectors = append(r.connectors, catalog)
+		for _, id := range connector.CatalogIDs {
+			if _, found := r.catalogs[id]; found {
+				return nil, errors.Errorf("catalog %s is served by more than one connector", id)
+			}
+			r.catalogs[id] = catalog
+		}
+		for _, prefix := range connector.AssetIDPrefixes {
+			for _, route := range r.routes {
+				if route.prefix == prefix {
+					return nil, errors.Errorf("asset ID prefix %s is served by more than one connector", prefix)
+				}
+			}
+			r.routes = append(r.routes, assetIDRoute{prefix: prefix, catalog: catalog})
+		}
+		log.Info().Str(logging.CONNECTOR, connector.Name).Str("URL", connector.URL).Strs("catalogIDs", connector.CatalogIDs).
+			Strs("assetIDPrefixes", connector.AssetIDPrefixes).Msg("registered data catalog connector")
+	}
+	// the longest prefix is matched first
+	sort.SliceStable(r.routes, func(i, j int) bool { return len(r.routes[i].prefix) > len(r.routes[j].prefix) })
+	return r, nil
+}
+
+// newConnectorHTTPClient returns an http client that uses the certificates and the token of the connector
+func newConnectorHTTPClient(connector *CatalogConnector) (*http.Client, error) {
+	log := logging.LogInit(logging.SETUP, "datacatalog client")
+	retryClient := retryablehttp.NewClient()
+	retryClient.Logger = &log
+	if connector.TLS == nil {
+		config, err := tls.GetClientTLSConfig(&log)
+		if err != nil {
+			return nil, err
+		}
+		retryClient.HTTPClient.Transport = &http.Transport{TLSClientConfig: config}
+	} else {
+		config, err := tls.GetClientTLSConfigFromFiles(&log, connector.TLS.CACertFile, connector.TLS.CertFile, connector.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		retryClient.HTTPClient.Transport = &http.Transport{TLSClientConfig: config}
+	}
+	if connector.TokenFile != "" {
+		retryClient.HTTPClient.Transport = &bearerTokenTransport{
+			base:      retryClient.HTTPClient.Transport,
+			tokenFile: connector.TokenFile,
+		}
+	}
+	return retryClient.StandardClient(), nil
+}
+
+// bearerTokenTransport sets the token that is read from a file in the authorization header of the requests.
+// The file is read on every request so that rotated tokens are used without a restart.
+type bearerTokenTransport struct {
+	base      http.RoundTripper
+	tokenFile string
+}
+
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := os.ReadFile(filepath.Clean(t.tokenFile))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the token of the catalog connector")
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return t.base.RoundTrip(req)
+}
+
+// catalogOfAsset returns the connector that serves the asset
+func (r *routedDataCatalog) catalogOfAsset(assetID string) DataCatalog {
+	for _, route := range r.routes {
+		if strings.HasPrefix(assetID, route.prefix) {
+			return route.catalog
+		}
+	}
+	return r.defaultCatalog
+}
+
+func (r *routedDataCatalog) GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest,
+	creds string) (*datacatalog.GetAssetResponse, error) {
+	return r.catalogOfAsset(string(in.AssetID)).GetAssetInfo(ctx, in, creds)
+}
+
+func (r *routedDataCatalog) CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest,
+	creds string) (*datacatalog.CreateAssetResponse, error) {
+	if catalog, found := r.catalogs[in.DestinationCatalogID]; found {
+		return catalog.CreateAsset(ctx, in, creds)
+	}
+	return r.defaultCatalog.CreateAsset(ctx, in, creds)
+}
+
+func (r *routedDataCatalog) DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest,
+	creds string) (*datacatalog.DeleteAssetResponse, error) {
+	return r.catalogOfAsset(string(in.AssetID)).DeleteAsset(ctx, in, creds)
+}
+
+func (r *routedDataCatalog) UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest,
+	creds string) (*datacatalog.UpdateAssetResponse, error) {
+	return r.catalogOfAsset(string(in.AssetID)).UpdateAsset(ctx, in, creds)
+}
+
+func (r *routedDataCatalog) Close() error {
+	var err error
+	for _, catalog := range r.connectors {
+		err = errors.Append(err, catalog.Close())
+	}
+	return errors.Append(err, r.defaultCatalog.Close())
+}
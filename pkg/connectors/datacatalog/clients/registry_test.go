@@ -0,0 +1,103 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+)
+
+// newCatalogServer returns a catalog connector that names the assets after the connector,
+// and records the authorization header of the last request
+func newCatalogServer(name string, authorization *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*authorization = req.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		var response interface{}
+		switch req.URL.Path {
+		case "/getAssetInfo":
+			response = datacatalog.GetAssetResponse{ResourceMetadata: datacatalog.ResourceMetadata{Name: name}}
+		case "/createAsset":
+			response = datacatalog.CreateAssetResponse{AssetID: name + "/asset"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestRoutedDataCatalog(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var defaultAuthorization, teamAuthorization string
+	defaultServer := newCatalogServer("default", &defaultAuthorization)
+	defer defaultServer.Close()
+	teamServer := newCatalogServer("team", &teamAuthorization)
+	defer teamServer.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenFile, []byte("team-token\n"), 0600)).To(gomega.Succeed())
+	registryFile := filepath.Join(t.TempDir(), "catalogs.yaml")
+	registry := "connectors:\n" +
+		"  - name: team\n" +
+		"    url: " + teamServer.URL + "\n" +
+		"    catalogIDs: [team-data]\n" +
+		"    assetIDPrefixes: [team-data/, team-data/archive/]\n" +
+		"    tokenFile: " + tokenFile + "\n"
+	g.Expect(os.WriteFile(registryFile, []byte(registry), 0600)).To(gomega.Succeed())
+
+	config, err := ReadCatalogRegistry(registryFile)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	catalog, err := NewRoutedDataCatalog(NewOpenAPIDataCatalog("default", defaultServer.URL), config)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer catalog.Close()
+
+	getAsset := func(assetID string) string {
+		response, getErr := catalog.GetAssetInfo(context.Background(),
+			&datacatalog.GetAssetRequest{AssetID: taxonomy.AssetID(assetID), OperationType: datacatalog.READ}, "")
+		g.Expect(getErr).ToNot(gomega.HaveOccurred())
+		return response.ResourceMetadata.Name
+	}
+	g.Expect(getAsset("team-data/asset")).To(gomega.Equal("team"))
+	g.Expect(teamAuthorization).To(gomega.Equal("Bearer team-token"))
+	g.Expect(getAsset("team-data/archive/asset")).To(gomega.Equal("team"))
+	g.Expect(getAsset("enterprise/asset")).To(gomega.Equal("default"))
+	g.Expect(defaultAuthorization).To(gomega.BeEmpty())
+
+	createAsset := func(catalogID string) string {
+		response, createErr := catalog.CreateAsset(context.Background(),
+			&datacatalog.CreateAssetRequest{DestinationCatalogID: catalogID}, "")
+		g.Expect(createErr).ToNot(gomega.HaveOccurred())
+		return response.AssetID
+	}
+	g.Expect(createAsset("team-data")).To(gomega.Equal("team/asset"))
+	g.Expect(createAsset("enterprise")).To(gomega.Equal("default/asset"))
+}
+
+func TestRoutedDataCatalogConflicts(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defaultCatalog := NewOpenAPIDataCatalog("default", "http://localhost:8080")
+	_, err := NewRoutedDataCatalog(defaultCatalog, &CatalogRegistry{Connectors: []CatalogConnector{
+		{Name: "a", URL: "http://a:8080", CatalogIDs: []string{"data"}},
+		{Name: "b", URL: "http://b:8080", CatalogIDs: []string{"data"}},
+	}})
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = NewRoutedDataCatalog(defaultCatalog, &CatalogRegistry{Connectors: []CatalogConnector{
+		{Name: "a", URL: "http://a:8080", AssetIDPrefixes: []string{"data/"}},
+		{Name: "b", URL: "http://b:8080", AssetIDPrefixes: []string{"data/"}},
+	}})
+	g.Expect(err).To(gomega.HaveOccurred())
+	_, err = NewRoutedDataCatalog(defaultCatalog, &CatalogRegistry{Connectors: []CatalogConnector{{Name: "a"}}})
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -4,13 +4,25 @@
 package clients
 
 import (
+	"context"
 	"io"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 )
 
 // PolicyManager is an interface of a facade to connect to a policy manager.
 type PolicyManager interface {
-	GetPoliciesDecisions(in *policymanager.GetPolicyDecisionsRequest, creds string) (*policymanager.GetPolicyDecisionsResponse, error)
+	GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+		creds string) (*policymanager.GetPolicyDecisionsResponse, error)
 	io.Closer
 }
+
+// NewPolicyManager creates a PolicyManager facade to a policy manager connector, which is called with gRPC if its
+// URL has a grpc:// or grpcs:// scheme, and with REST otherwise
+func NewPolicyManager(name, connectionURL string) (PolicyManager, error) {
+	if rpc.IsGRPCURL(connectionURL) {
+		return NewGRPCPolicyManager(name, connectionURL)
+	}
+	return NewOpenAPIPolicyManager(name, connectionURL)
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+
+	"fybrik.io/fybrik/manager/controllers/utils"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+)
+
+// PolicySource is a policy manager whose decisions are combined with the decisions of other policy managers
+type PolicySource struct {
+	// Name of the policy manager, reported as the source of its decisions
+	Name          string
+	PolicyManager PolicyManager
+}
+
+var _ PolicyManager = (*compositePolicyManager)(nil)
+
+// compositePolicyManager is a PolicyManager facade that combines the decisions of several policy managers
+type compositePolicyManager struct {
+	sources []PolicySource
+}
+
+// NewCompositePolicyManager creates a PolicyManager facade that queries the given policy managers concurrently
+// and combines their decisions:
+// - a Deny of any policy manager denies the access, and only the Deny actions are returned
+// - actions with the same name and properties are returned once, with the names of all the policy managers that returned them
+// - the messages of the policy managers are concatenated
+// An error of any policy manager fails the request.
+func NewCompositePolicyManager(sources []PolicySource) (PolicyManager, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("a composite policy manager requires at least one policy manager")
+	}
+	names := map[string]bool{}
+	for _, source := range sources {
+		if source.Name == "" || names[source.Name] {
+			return nil, errors.Errorf("policy manager names must be unique and not empty: %q", source.Name)
+		}
+		names[source.Name] = true
+	}
+	return &compositePolicyManager{sources: sources}, nil
+}
+
+func (m *compositePolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	responses := make([]*policymanager.GetPolicyDecisionsResponse, len(m.sources))
+	errs := make([]error, len(m.sources))
+	var wg sync.WaitGroup
+	for i := range m.sources {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			responses[i], errs[i] = m.sources[i].PolicyManager.GetPoliciesDecisions(ctx, in.DeepCopy(), creds)
+		}(i)
+	}
+	wg.Wait()
+
+	var err error
+	for i := range m.sources {
+		if errs[i] != nil {
+			err = errors.Append(err, errors.WithMessage(errs[i], "policy manager "+m.sources[i].Name))
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.combine(responses)
+}
+
+// combine merges the responses of the policy managers, which are given in the order of the policy managers
+func (m *compositePolicyManager) combine(responses []*policymanager.GetPolicyDecisionsResponse) (
+	*policymanager.GetPolicyDecisionsResponse, error) {
+	combined := &policymanager.GetPolicyDecisionsResponse{Result: []policymanager.ResultItem{}}
+	var decisionIDs, messages []string
+	// indexes of the combined results by the action name and properties
+	indexes := map[string]int{}
+	denied := false
+	for i, response := range responses {
+		if response == nil {
+			continue
+		}
+		source := m.sources[i].Name
+		if response.DecisionID != "" {
+			decisionIDs = append(decisionIDs, source+":"+response.DecisionID)
+		}
+		if response.Message != "" {
+			messages = append(messages, source+": "+response.Message)
+		}
+		for _, item := range response.Result {
+			isDeny := utils.IsDenied(item.Action.Name)
+			if denied && !isDeny {
+				continue
+			}
+			if isDeny && !denied {
+				// a Deny overrides all other actions
+				denied = true
+				combined.Result = []policymanager.ResultItem{}
+				indexes = map[string]int{}
+			}
+			key, err := json.Marshal(item.Action)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to compare the actions of the policy managers")
+			}
+			if index, found := indexes[string(key)]; found {
+				if !strings.Contains(","+combined.Result[index].Source+",", ","+source+",") {
+					combined.Result[index].Source += "," + source
+				}
+				continue
+			}
+			item = *item.DeepCopy()
+			item.Source = source
+			indexes[string(key)] = len(combined.Result)
+			combined.Result = append(combined.Result, item)
+		}
+	}
+	combined.DecisionID = strings.Join(decisionIDs, ",")
+	combined.Message = strings.Join(messages, "; ")
+	return combined, nil
+}
+
+func (m *compositePolicyManager) Close() error {
+	var err error
+	for _, source := range m.sources {
+		err = errors.Append(err, source.PolicyManager.Close())
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors/policymanager/clients"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+// staticPolicyManager returns the same response to all requests
+type staticPolicyManager struct {
+	response *policymanager.GetPolicyDecisionsResponse
+	err      error
+}
+
+func (m *staticPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+	return m.response, m.err
+}
+
+func (m *staticPolicyManager) Close() error {
+	return nil
+}
+
+func action(name string, properties map[string]interface{}) taxonomy.Action {
+	return taxonomy.Action{Name: taxonomy.ActionName(name), AdditionalProperties: serde.Properties{Items: properties}}
+}
+
+func decisions(message string, actions ...taxonomy.Action) *staticPolicyManager {
+	response := &policymanager.GetPolicyDecisionsResponse{DecisionID: "id", Message: message}
+	for _, a := range actions {
+		response.Result = append(response.Result, policymanager.ResultItem{Policy: "policy", Action: a})
+	}
+	return &staticPolicyManager{response: response}
+}
+
+var _ = Describe("Composite policy manager", func() {
+	request := &policymanager.GetPolicyDecisionsRequest{Action: policymanager.RequestAction{ActionType: taxonomy.ReadFlow}}
+	redactSSN := action("RedactAction", map[string]interface{}{"columns": []interface{}{"ssn"}})
+	redactName := action("RedactAction", map[string]interface{}{"columns": []interface{}{"name"}})
+
+	getDecisions := func(sources ...clients.PolicySource) (*policymanager.GetPolicyDecisionsResponse, error) {
+		policyManager, err := clients.NewCompositePolicyManager(sources)
+		Expect(err).ToNot(HaveOccurred())
+		defer policyManager.Close()
+		return policyManager.GetPoliciesDecisions(context.Background(), request, "")
+	}
+
+	It("merges the actions of all the policy managers", func() {
+		response, err := getDecisions(
+			clients.PolicySource{Name: "enterprise", PolicyManager: decisions("redact ssn", redactSSN)},
+			clients.PolicySource{Name: "team", PolicyManager: decisions("", redactSSN, redactName)})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Result).To(HaveLen(2))
+		Expect(response.Result[0].Action).To(Equal(redactSSN))
+		Expect(response.Result[0].Source).To(Equal("enterprise,team"))
+		Expect(response.Result[1].Action).To(Equal(redactName))
+		Expect(response.Result[1].Source).To(Equal("team"))
+		Expect(response.Message).To(Equal("enterprise: redact ssn"))
+		Expect(response.DecisionID).To(Equal("enterprise:id,team:id"))
+	})
+
+	It("denies the access if any policy manager denies it", func() {
+		response, err := getDecisions(
+			clients.PolicySource{Name: "enterprise", PolicyManager: decisions("redact ssn", redactSSN)},
+			clients.PolicySource{Name: "team", PolicyManager: decisions("project data", action("Deny", nil))})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Result).To(HaveLen(1))
+		Expect(string(response.Result[0].Action.Name)).To(Equal("Deny"))
+		Expect(response.Result[0].Source).To(Equal("team"))
+		Expect(response.Message).To(Equal("enterprise: redact ssn; team: project data"))
+	})
+
+	It("fails if any policy manager fails", func() {
+		_, err := getDecisions(
+			clients.PolicySource{Name: "enterprise", PolicyManager: decisions("", redactSSN)},
+			clients.PolicySource{Name: "team", PolicyManager: &staticPolicyManager{err: errors.New("unavailable")}})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("team"))
+	})
+
+	It("requires unique policy manager names", func() {
+		_, err := clients.NewCompositePolicyManager([]clients.PolicySource{
+			{Name: "team", PolicyManager: decisions("")}, {Name: "team", PolicyManager: decisions("")}})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,61 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"fybrik.io/fybrik/pkg/connectors/protos"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+)
+
+var _ PolicyManager = (*grpcPolicyManager)(nil)
+
+type grpcPolicyManager struct {
+	name   string
+	conn   *grpc.ClientConn
+	client protos.PolicyManagerClient
+}
+
+// NewGRPCPolicyManager creates a PolicyManager facade that connects to a gRPC service at a grpc:// or grpcs:// URL
+func NewGRPCPolicyManager(name, connectionURL string) (PolicyManager, error) {
+	log := logging.LogInit(logging.SETUP, "policymanager client")
+	conn, err := rpc.Dial(connectionURL, &log)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcPolicyManager{
+		name:   name,
+		conn:   conn,
+		client: protos.NewPolicyManagerClient(conn),
+	}, nil
+}
+
+func (m *grpcPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (response *policymanager.GetPolicyDecisionsResponse, err error) {
+	defer func(start time.Time) { observe("getPoliciesDecisions", start, err) }(time.Now())
+	response = &policymanager.GetPolicyDecisionsResponse{}
+	ctx = metadata.AppendToOutgoingContext(ctx, rpc.PolicyManagerCredsKey, creds)
+	if err = rpc.Invoke(ctx, m.client.GetPoliciesDecisions, in, &protos.GetPolicyDecisionsRequest{}, response); err != nil {
+		// the message of the connector is returned as is, like the response body of the REST API
+		if s, ok := status.FromError(err); ok && s.Message() != "" {
+			return nil, errors.New(s.Message())
+		}
+		return nil, errors.Wrap(err, fmt.Sprintf("get policies decisions from %s failed", m.name))
+	}
+	return response, nil
+}
+
+func (m *grpcPolicyManager) Close() error {
+	return m.conn.Close()
+}
@@ -8,13 +8,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"emperror.dev/errors"
 
 	"fybrik.io/fybrik/pkg/connectors/policymanager/openapiclient"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/policymanager"
 	"fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 var _ PolicyManager = (*openAPIPolicyManager)(nil)
@@ -39,7 +42,7 @@ func NewOpenAPIPolicyManager(name, connectionURL string) (PolicyManager, error)
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       tracing.WrapClient(tls.GetHTTPClient(&log).StandardClient()),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
@@ -58,10 +61,11 @@ func getDetailedError(httpResponse *http.Response, baseError error, defaultMsg s
 	return errors.Wrap(baseError, defaultMsg)
 }
 
-func (m *openAPIPolicyManager) GetPoliciesDecisions(in *policymanager.GetPolicyDecisionsRequest,
-	creds string) (*policymanager.GetPolicyDecisionsResponse, error) {
+func (m *openAPIPolicyManager) GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+	creds string) (response *policymanager.GetPolicyDecisionsResponse, err error) {
+	defer func(start time.Time) { observe("getPoliciesDecisions", start, err) }(time.Now())
 	printErr := func() string { return fmt.Sprintf("get policies decisions from %s failed", m.name) }
-	resp, httpResponse, err := m.client.DefaultApi.GetPoliciesDecisions(context.Background()).XRequestCred(creds).
+	resp, httpResponse, err := m.client.DefaultApi.GetPoliciesDecisions(ctx).XRequestCred(creds).
 		GetPolicyDecisionsRequest(*in).Execute()
 
 	if httpResponse == nil {
@@ -80,3 +84,8 @@ func (m *openAPIPolicyManager) GetPoliciesDecisions(in *policymanager.GetPolicyD
 func (m *openAPIPolicyManager) Close() error {
 	return nil
 }
+
+// observe records the latency and the outcome of a policy manager request
+func observe(operation string, start time.Time, err error) {
+	metrics.ObserveConnectorRequest(metrics.PolicyManagerConnector, operation, start, err)
+}
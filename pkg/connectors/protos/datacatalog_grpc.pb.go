@@ -0,0 +1,309 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.22.2
+// source: datacatalog.proto
+
+package protos
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DataCatalog_BulkGetAssetInfo_FullMethodName = "/fybrik.connectors.DataCatalog/BulkGetAssetInfo"
+	DataCatalog_CreateAsset_FullMethodName      = "/fybrik.connectors.DataCatalog/CreateAsset"
+	DataCatalog_DeleteAsset_FullMethodName      = "/fybrik.connectors.DataCatalog/DeleteAsset"
+	DataCatalog_GetAssetInfo_FullMethodName     = "/fybrik.connectors.DataCatalog/GetAssetInfo"
+	DataCatalog_UpdateAsset_FullMethodName      = "/fybrik.connectors.DataCatalog/UpdateAsset"
+)
+
+// DataCatalogClient is the client API for DataCatalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DataCatalogClient interface {
+	// Responds to each GetAssetInfo request in the stream with its result, in the order of the requests
+	BulkGetAssetInfo(ctx context.Context, opts ...grpc.CallOption) (DataCatalog_BulkGetAssetInfoClient, error)
+	// This REST API writes data asset information to the data catalog configured in fybrik. The credentials are sent in
+	// the x-request-datacatalog-write-cred metadata
+	CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error)
+	// This REST API deletes data asset. The credentials are sent in the x-request-datacatalog-cred metadata
+	DeleteAsset(ctx context.Context, in *DeleteAssetRequest, opts ...grpc.CallOption) (*DeleteAssetResponse, error)
+	// This REST API gets data asset information from the data catalog configured in fybrik for the data sets indicated in
+	// FybrikApplication yaml. The credentials are sent in the x-request-datacatalog-cred metadata
+	GetAssetInfo(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*GetAssetResponse, error)
+	// This REST API updates data asset information in the data catalog configured in fybrik. The credentials are sent in
+	// the x-request-datacatalog-update-cred metadata
+	UpdateAsset(ctx context.Context, in *UpdateAssetRequest, opts ...grpc.CallOption) (*UpdateAssetResponse, error)
+}
+
+type dataCatalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataCatalogClient(cc grpc.ClientConnInterface) DataCatalogClient {
+	return &dataCatalogClient{cc}
+}
+
+func (c *dataCatalogClient) BulkGetAssetInfo(ctx context.Context, opts ...grpc.CallOption) (DataCatalog_BulkGetAssetInfoClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataCatalog_ServiceDesc.Streams[0], DataCatalog_BulkGetAssetInfo_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataCatalogBulkGetAssetInfoClient{stream}
+	return x, nil
+}
+
+type DataCatalog_BulkGetAssetInfoClient interface {
+	Send(*GetAssetRequest) error
+	Recv() (*GetAssetInfoResult, error)
+	grpc.ClientStream
+}
+
+type dataCatalogBulkGetAssetInfoClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataCatalogBulkGetAssetInfoClient) Send(m *GetAssetRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataCatalogBulkGetAssetInfoClient) Recv() (*GetAssetInfoResult, error) {
+	m := new(GetAssetInfoResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataCatalogClient) CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error) {
+	out := new(CreateAssetResponse)
+	err := c.cc.Invoke(ctx, DataCatalog_CreateAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataCatalogClient) DeleteAsset(ctx context.Context, in *DeleteAssetRequest, opts ...grpc.CallOption) (*DeleteAssetResponse, error) {
+	out := new(DeleteAssetResponse)
+	err := c.cc.Invoke(ctx, DataCatalog_DeleteAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataCatalogClient) GetAssetInfo(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*GetAssetResponse, error) {
+	out := new(GetAssetResponse)
+	err := c.cc.Invoke(ctx, DataCatalog_GetAssetInfo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataCatalogClient) UpdateAsset(ctx context.Context, in *UpdateAssetRequest, opts ...grpc.CallOption) (*UpdateAssetResponse, error) {
+	out := new(UpdateAssetResponse)
+	err := c.cc.Invoke(ctx, DataCatalog_UpdateAsset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DataCatalogServer is the server API for DataCatalog service.
+// All implementations must embed UnimplementedDataCatalogServer
+// for forward compatibility
+type DataCatalogServer interface {
+	// Responds to each GetAssetInfo request in the stream with its result, in the order of the requests
+	BulkGetAssetInfo(DataCatalog_BulkGetAssetInfoServer) error
+	// This REST API writes data asset information to the data catalog configured in fybrik. The credentials are sent in
+	// the x-request-datacatalog-write-cred metadata
+	CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error)
+	// This REST API deletes data asset. The credentials are sent in the x-request-datacatalog-cred metadata
+	DeleteAsset(context.Context, *DeleteAssetRequest) (*DeleteAssetResponse, error)
+	// This REST API gets data asset information from the data catalog configured in fybrik for the data sets indicated in
+	// FybrikApplication yaml. The credentials are sent in the x-request-datacatalog-cred metadata
+	GetAssetInfo(context.Context, *GetAssetRequest) (*GetAssetResponse, error)
+	// This REST API updates data asset information in the data catalog configured in fybrik. The credentials are sent in
+	// the x-request-datacatalog-update-cred metadata
+	UpdateAsset(context.Context, *UpdateAssetRequest) (*UpdateAssetResponse, error)
+	mustEmbedUnimplementedDataCatalogServer()
+}
+
+// UnimplementedDataCatalogServer must be embedded to have forward compatible implementations.
+type UnimplementedDataCatalogServer struct {
+}
+
+func (UnimplementedDataCatalogServer) BulkGetAssetInfo(DataCatalog_BulkGetAssetInfoServer) error {
+	return status.Errorf(codes.Unimplemented, "method BulkGetAssetInfo not implemented")
+}
+func (UnimplementedDataCatalogServer) CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAsset not implemented")
+}
+func (UnimplementedDataCatalogServer) DeleteAsset(context.Context, *DeleteAssetRequest) (*DeleteAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAsset not implemented")
+}
+func (UnimplementedDataCatalogServer) GetAssetInfo(context.Context, *GetAssetRequest) (*GetAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssetInfo not implemented")
+}
+func (UnimplementedDataCatalogServer) UpdateAsset(context.Context, *UpdateAssetRequest) (*UpdateAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAsset not implemented")
+}
+func (UnimplementedDataCatalogServer) mustEmbedUnimplementedDataCatalogServer() {}
+
+// UnsafeDataCatalogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataCatalogServer will
+// result in compilation errors.
+type UnsafeDataCatalogServer interface {
+	mustEmbedUnimplementedDataCatalogServer()
+}
+
+func RegisterDataCatalogServer(s grpc.ServiceRegistrar, srv DataCatalogServer) {
+	s.RegisterService(&DataCatalog_ServiceDesc, srv)
+}
+
+func _DataCatalog_BulkGetAssetInfo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataCatalogServer).BulkGetAssetInfo(&dataCatalogBulkGetAssetInfoServer{stream})
+}
+
+type DataCatalog_BulkGetAssetInfoServer interface {
+	Send(*GetAssetInfoResult) error
+	Recv() (*GetAssetRequest, error)
+	grpc.ServerStream
+}
+
+type dataCatalogBulkGetAssetInfoServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataCatalogBulkGetAssetInfoServer) Send(m *GetAssetInfoResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *dataCatalogBulkGetAssetInfoServer) Recv() (*GetAssetRequest, error) {
+	m := new(GetAssetRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _DataCatalog_CreateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataCatalogServer).CreateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataCatalog_CreateAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataCatalogServer).CreateAsset(ctx, req.(*CreateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataCatalog_DeleteAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataCatalogServer).DeleteAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataCatalog_DeleteAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataCatalogServer).DeleteAsset(ctx, req.(*DeleteAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataCatalog_GetAssetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataCatalogServer).GetAssetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataCatalog_GetAssetInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataCatalogServer).GetAssetInfo(ctx, req.(*GetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataCatalog_UpdateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataCatalogServer).UpdateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataCatalog_UpdateAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataCatalogServer).UpdateAsset(ctx, req.(*UpdateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DataCatalog_ServiceDesc is the grpc.ServiceDesc for DataCatalog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataCatalog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fybrik.connectors.DataCatalog",
+	HandlerType: (*DataCatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAsset",
+			Handler:    _DataCatalog_CreateAsset_Handler,
+		},
+		{
+			MethodName: "DeleteAsset",
+			Handler:    _DataCatalog_DeleteAsset_Handler,
+		},
+		{
+			MethodName: "GetAssetInfo",
+			Handler:    _DataCatalog_GetAssetInfo_Handler,
+		},
+		{
+			MethodName: "UpdateAsset",
+			Handler:    _DataCatalog_UpdateAsset_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BulkGetAssetInfo",
+			Handler:       _DataCatalog_BulkGetAssetInfo_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "datacatalog.proto",
+}
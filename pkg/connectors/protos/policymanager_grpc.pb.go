@@ -0,0 +1,116 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.22.2
+// source: policymanager.proto
+
+package protos
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PolicyManager_GetPoliciesDecisions_FullMethodName = "/fybrik.connectors.PolicyManager/GetPoliciesDecisions"
+)
+
+// PolicyManagerClient is the client API for PolicyManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PolicyManagerClient interface {
+	// This REST API gets data governance decisions for the data sets indicated in FybrikApplication yaml based on the
+	// context indicated. The credentials are sent in the x-request-cred metadata
+	GetPoliciesDecisions(ctx context.Context, in *GetPolicyDecisionsRequest, opts ...grpc.CallOption) (*GetPolicyDecisionsResponse, error)
+}
+
+type policyManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPolicyManagerClient(cc grpc.ClientConnInterface) PolicyManagerClient {
+	return &policyManagerClient{cc}
+}
+
+func (c *policyManagerClient) GetPoliciesDecisions(ctx context.Context, in *GetPolicyDecisionsRequest, opts ...grpc.CallOption) (*GetPolicyDecisionsResponse, error) {
+	out := new(GetPolicyDecisionsResponse)
+	err := c.cc.Invoke(ctx, PolicyManager_GetPoliciesDecisions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PolicyManagerServer is the server API for PolicyManager service.
+// All implementations must embed UnimplementedPolicyManagerServer
+// for forward compatibility
+type PolicyManagerServer interface {
+	// This REST API gets data governance decisions for the data sets indicated in FybrikApplication yaml based on the
+	// context indicated. The credentials are sent in the x-request-cred metadata
+	GetPoliciesDecisions(context.Context, *GetPolicyDecisionsRequest) (*GetPolicyDecisionsResponse, error)
+	mustEmbedUnimplementedPolicyManagerServer()
+}
+
+// UnimplementedPolicyManagerServer must be embedded to have forward compatible implementations.
+type UnimplementedPolicyManagerServer struct {
+}
+
+func (UnimplementedPolicyManagerServer) GetPoliciesDecisions(context.Context, *GetPolicyDecisionsRequest) (*GetPolicyDecisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPoliciesDecisions not implemented")
+}
+func (UnimplementedPolicyManagerServer) mustEmbedUnimplementedPolicyManagerServer() {}
+
+// UnsafePolicyManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PolicyManagerServer will
+// result in compilation errors.
+type UnsafePolicyManagerServer interface {
+	mustEmbedUnimplementedPolicyManagerServer()
+}
+
+func RegisterPolicyManagerServer(s grpc.ServiceRegistrar, srv PolicyManagerServer) {
+	s.RegisterService(&PolicyManager_ServiceDesc, srv)
+}
+
+func _PolicyManager_GetPoliciesDecisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPolicyDecisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyManagerServer).GetPoliciesDecisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PolicyManager_GetPoliciesDecisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyManagerServer).GetPoliciesDecisions(ctx, req.(*GetPolicyDecisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PolicyManager_ServiceDesc is the grpc.ServiceDesc for PolicyManager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PolicyManager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fybrik.connectors.PolicyManager",
+	HandlerType: (*PolicyManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPoliciesDecisions",
+			Handler:    _PolicyManager_GetPoliciesDecisions_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "policymanager.proto",
+}
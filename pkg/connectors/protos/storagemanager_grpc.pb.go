@@ -0,0 +1,193 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.22.2
+// source: storagemanager.proto
+
+package protos
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	StorageManager_AllocateStorage_FullMethodName          = "/fybrik.connectors.StorageManager/AllocateStorage"
+	StorageManager_DeleteStorage_FullMethodName            = "/fybrik.connectors.StorageManager/DeleteStorage"
+	StorageManager_GetSupportedStorageTypes_FullMethodName = "/fybrik.connectors.StorageManager/GetSupportedStorageTypes"
+)
+
+// StorageManagerClient is the client API for StorageManager service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type StorageManagerClient interface {
+	// This REST API allocates storage based on the storage account selected by Fybrik
+	AllocateStorage(ctx context.Context, in *AllocateStorageRequest, opts ...grpc.CallOption) (*AllocateStorageResponse, error)
+	// This REST API deletes allocated storage
+	DeleteStorage(ctx context.Context, in *DeleteStorageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// This REST API returns a list of supported storage types
+	GetSupportedStorageTypes(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetSupportedStorageTypesResponse, error)
+}
+
+type storageManagerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageManagerClient(cc grpc.ClientConnInterface) StorageManagerClient {
+	return &storageManagerClient{cc}
+}
+
+func (c *storageManagerClient) AllocateStorage(ctx context.Context, in *AllocateStorageRequest, opts ...grpc.CallOption) (*AllocateStorageResponse, error) {
+	out := new(AllocateStorageResponse)
+	err := c.cc.Invoke(ctx, StorageManager_AllocateStorage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageManagerClient) DeleteStorage(ctx context.Context, in *DeleteStorageRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, StorageManager_DeleteStorage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageManagerClient) GetSupportedStorageTypes(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*GetSupportedStorageTypesResponse, error) {
+	out := new(GetSupportedStorageTypesResponse)
+	err := c.cc.Invoke(ctx, StorageManager_GetSupportedStorageTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageManagerServer is the server API for StorageManager service.
+// All implementations must embed UnimplementedStorageManagerServer
+// for forward compatibility
+type StorageManagerServer interface {
+	// This REST API allocates storage based on the storage account selected by Fybrik
+	AllocateStorage(context.Context, *AllocateStorageRequest) (*AllocateStorageResponse, error)
+	// This REST API deletes allocated storage
+	DeleteStorage(context.Context, *DeleteStorageRequest) (*emptypb.Empty, error)
+	// This REST API returns a list of supported storage types
+	GetSupportedStorageTypes(context.Context, *emptypb.Empty) (*GetSupportedStorageTypesResponse, error)
+	mustEmbedUnimplementedStorageManagerServer()
+}
+
+// UnimplementedStorageManagerServer must be embedded to have forward compatible implementations.
+type UnimplementedStorageManagerServer struct {
+}
+
+func (UnimplementedStorageManagerServer) AllocateStorage(context.Context, *AllocateStorageRequest) (*AllocateStorageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AllocateStorage not implemented")
+}
+func (UnimplementedStorageManagerServer) DeleteStorage(context.Context, *DeleteStorageRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStorage not implemented")
+}
+func (UnimplementedStorageManagerServer) GetSupportedStorageTypes(context.Context, *emptypb.Empty) (*GetSupportedStorageTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupportedStorageTypes not implemented")
+}
+func (UnimplementedStorageManagerServer) mustEmbedUnimplementedStorageManagerServer() {}
+
+// UnsafeStorageManagerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageManagerServer will
+// result in compilation errors.
+type UnsafeStorageManagerServer interface {
+	mustEmbedUnimplementedStorageManagerServer()
+}
+
+func RegisterStorageManagerServer(s grpc.ServiceRegistrar, srv StorageManagerServer) {
+	s.RegisterService(&StorageManager_ServiceDesc, srv)
+}
+
+func _StorageManager_AllocateStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllocateStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageManagerServer).AllocateStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageManager_AllocateStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageManagerServer).AllocateStorage(ctx, req.(*AllocateStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageManager_DeleteStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStorageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageManagerServer).DeleteStorage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageManager_DeleteStorage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageManagerServer).DeleteStorage(ctx, req.(*DeleteStorageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageManager_GetSupportedStorageTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageManagerServer).GetSupportedStorageTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageManager_GetSupportedStorageTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageManagerServer).GetSupportedStorageTypes(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StorageManager_ServiceDesc is the grpc.ServiceDesc for StorageManager service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StorageManager_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "fybrik.connectors.StorageManager",
+	HandlerType: (*StorageManagerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AllocateStorage",
+			Handler:    _StorageManager_AllocateStorage_Handler,
+		},
+		{
+			MethodName: "DeleteStorage",
+			Handler:    _StorageManager_DeleteStorage_Handler,
+		},
+		{
+			MethodName: "GetSupportedStorageTypes",
+			Handler:    _StorageManager_GetSupportedStorageTypes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "storagemanager.proto",
+}
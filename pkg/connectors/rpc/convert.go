@@ -0,0 +1,185 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// additionalPropertiesField is the field of a message with the properties of a taxonomy object that are defined in
+// the taxonomy layers, which is a google.protobuf.Struct
+const additionalPropertiesField = "additionalProperties"
+
+// isAdditionalProperties returns true if a field holds the additional properties of a taxonomy object
+func isAdditionalProperties(fd protoreflect.FieldDescriptor) bool {
+	return fd.JSONName() == additionalPropertiesField && fd.Message() != nil &&
+		fd.Message().FullName() == (&structpb.Struct{}).ProtoReflect().Descriptor().FullName()
+}
+
+// ToMessage converts a model of pkg/model to the protobuf message of the same taxonomy object.
+// The JSON names of the message fields are the property names of the taxonomy, and the properties of the model that
+// are not fields of the message are moved to its additionalProperties field.
+func ToMessage(model interface{}, msg proto.Message) error {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(nest(value, msg.ProtoReflect().Descriptor())); err != nil {
+		return err
+	}
+	return errors.Wrapf(protojson.Unmarshal(data, msg), "failed to convert to %s", msg.ProtoReflect().Descriptor().FullName())
+}
+
+// FromMessage converts a protobuf message to the model of pkg/model of the same taxonomy object, with the properties
+// of the additionalProperties fields as properties of the model
+func FromMessage(msg proto.Message, model interface{}) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err = json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(flatten(value, msg.ProtoReflect().Descriptor())); err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, model), "failed to convert from %s", msg.ProtoReflect().Descriptor().FullName())
+}
+
+// nest moves the properties of a JSON object that are not fields of the message to its additionalProperties field,
+// in the object and in the objects of its message fields
+func nest(value interface{}, md protoreflect.MessageDescriptor) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	fields := md.Fields()
+	additional := map[string]interface{}{}
+	for key, v := range object {
+		fd := fields.ByJSONName(key)
+		switch {
+		case fd == nil:
+			// unknown properties are left for protojson to reject if the message has no additional properties
+			if extra := fields.ByJSONName(additionalPropertiesField); extra != nil && isAdditionalProperties(extra) {
+				additional[key] = v
+				delete(object, key)
+			}
+		case fd.Message() != nil && !isAdditionalProperties(fd):
+			object[key] = mapValues(v, func(element interface{}) interface{} { return nest(element, fd.Message()) })
+		}
+	}
+	if len(additional) > 0 {
+		object[additionalPropertiesField] = additional
+	}
+	return object
+}
+
+// flatten moves the properties in the additionalProperties fields of a JSON object of a message to the object,
+// and converts the 64-bit integers that protojson encodes as strings to numbers.
+// The scalar fields with default values, which protojson omits, are set as in the JSON of the models, where the
+// required properties are always present.
+func flatten(value interface{}, md protoreflect.MessageDescriptor) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if _, exists := object[fd.JSONName()]; !exists && fd.Message() == nil && !fd.IsList() && !fd.IsMap() {
+			object[fd.JSONName()] = fd.Default().Interface()
+		}
+	}
+	var additional map[string]interface{}
+	for key, v := range object {
+		fd := fields.ByJSONName(key)
+		switch {
+		case fd == nil:
+		case isAdditionalProperties(fd):
+			additional, _ = v.(map[string]interface{})
+			delete(object, key)
+		case fd.Message() != nil:
+			object[key] = mapValues(v, func(element interface{}) interface{} { return flatten(element, fd.Message()) })
+		case fd.Kind() == protoreflect.Int64Kind || fd.Kind() == protoreflect.Uint64Kind ||
+			fd.Kind() == protoreflect.Sint64Kind || fd.Kind() == protoreflect.Fixed64Kind ||
+			fd.Kind() == protoreflect.Sfixed64Kind:
+			object[key] = mapValues(v, func(element interface{}) interface{} {
+				if s, isString := element.(string); isString {
+					return json.Number(s)
+				}
+				return element
+			})
+		}
+	}
+	for key, v := range additional {
+		if _, exists := object[key]; !exists {
+			object[key] = v
+		}
+	}
+	return object
+}
+
+// mapValues applies f to a value, or to its elements if it is a list
+func mapValues(value interface{}, f func(interface{}) interface{}) interface{} {
+	list, ok := value.([]interface{})
+	if !ok {
+		return f(value)
+	}
+	for i := range list {
+		list[i] = f(list[i])
+	}
+	return list
+}
+
+// Invoke calls a unary method of a client of the generated services with the message of a request model, and
+// converts the response message to the response model if out is not nil.
+// The errors of the call are returned as is, with their gRPC status.
+func Invoke[Request, Response proto.Message](ctx context.Context,
+	method func(context.Context, Request, ...grpc.CallOption) (Response, error),
+	in interface{}, request Request, out interface{}) error {
+	if err := ToMessage(in, request); err != nil {
+		return err
+	}
+	response, err := method(ctx, request)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return FromMessage(response, out)
+}
+
+// serve converts the message of a request to its model, calls the connector with it, and converts the response model
+// to the response message. The errors of the connector are returned as is.
+func serve[In, Out any, Response proto.Message](request proto.Message, response Response,
+	call func(in *In) (*Out, error)) (Response, error) {
+	var none Response
+	in := new(In)
+	if err := FromMessage(request, in); err != nil {
+		return none, NewError(http.StatusBadRequest, err.Error())
+	}
+	out, err := call(in)
+	if err != nil {
+		return none, err
+	}
+	if err = ToMessage(out, response); err != nil {
+		return none, NewError(http.StatusInternalServerError, err.Error())
+	}
+	return response, nil
+}
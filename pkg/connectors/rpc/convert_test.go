@@ -0,0 +1,92 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"fybrik.io/fybrik/pkg/connectors/protos"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/taxonomy"
+	"fybrik.io/fybrik/pkg/serde"
+)
+
+func TestAssetConversion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	asset := &datacatalog.GetAssetResponse{
+		ResourceMetadata: datacatalog.ResourceMetadata{
+			Name: "ns/asset",
+			Tags: &taxonomy.Tags{Properties: serde.Properties{Items: map[string]interface{}{"finance": true}}},
+			Columns: []datacatalog.ResourceColumn{
+				{Name: "nameOrig", Tags: &taxonomy.Tags{Properties: serde.Properties{Items: map[string]interface{}{"PII": true}}}},
+				{Name: "amount"},
+			},
+		},
+		Details: datacatalog.ResourceDetails{
+			Connection: taxonomy.Connection{
+				Name: "s3",
+				AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+					"s3": map[string]interface{}{"bucket": "fybrik-test", "endpoint": "http://s3.eu.cloud-object-storage", "port": 443.0},
+				}},
+			},
+			DataFormat: "csv",
+		},
+		Credentials: "/v1/kubernetes-secrets/asset-creds?namespace=ns",
+	}
+	msg := &protos.GetAssetResponse{}
+	g.Expect(ToMessage(asset, msg)).To(gomega.Succeed())
+	// the properties of the taxonomy layers are in the additional properties of the messages
+	g.Expect(msg.Details.Connection.Name).To(gomega.Equal("s3"))
+	s3 := msg.Details.Connection.AdditionalProperties.Fields["s3"].GetStructValue()
+	g.Expect(s3.Fields["bucket"].GetStringValue()).To(gomega.Equal("fybrik-test"))
+	g.Expect(msg.ResourceMetadata.Columns[0].Tags.AdditionalProperties.Fields["PII"].GetBoolValue()).To(gomega.BeTrue())
+
+	converted := &datacatalog.GetAssetResponse{}
+	g.Expect(FromMessage(msg, converted)).To(gomega.Succeed())
+	g.Expect(converted).To(gomega.Equal(asset))
+}
+
+func TestDecisionsConversion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	decisions := &policymanager.GetPolicyDecisionsResponse{
+		DecisionID: "1",
+		Result: []policymanager.ResultItem{
+			{
+				Policy: "mask the PII columns",
+				Action: taxonomy.Action{
+					Name: "RedactAction",
+					AdditionalProperties: serde.Properties{Items: map[string]interface{}{
+						"columns": []interface{}{"nameOrig"},
+					}},
+				},
+				Source: "opa",
+			},
+			{Policy: "allow", Action: taxonomy.Action{Name: "Allow"}},
+		},
+	}
+	msg := &protos.GetPolicyDecisionsResponse{}
+	g.Expect(ToMessage(decisions, msg)).To(gomega.Succeed())
+	g.Expect(msg.Result[0].Action.AdditionalProperties.Fields["columns"].GetListValue().Values).To(gomega.HaveLen(1))
+	g.Expect(msg.Result[1].Action.AdditionalProperties).To(gomega.BeNil())
+
+	converted := &policymanager.GetPolicyDecisionsResponse{}
+	g.Expect(FromMessage(msg, converted)).To(gomega.Succeed())
+	g.Expect(converted).To(gomega.Equal(decisions))
+}
+
+func TestUnknownPropertyConversion(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	// the request has no additional properties, so an unknown property cannot be kept
+	request := map[string]interface{}{"assetID": "ns/asset", "force": true}
+	g.Expect(ToMessage(request, &protos.DeleteAssetRequest{})).NotTo(gomega.Succeed())
+}
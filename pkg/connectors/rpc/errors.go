@@ -0,0 +1,97 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpCodes maps the status codes of the REST API of the connectors to gRPC codes
+var httpCodes = map[int]codes.Code{
+	http.StatusOK:                  codes.OK,
+	http.StatusCreated:             codes.OK,
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusNotImplemented:      codes.Unimplemented,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// grpcCodes maps gRPC codes to the status codes of the REST API of the connectors
+var grpcCodes = map[codes.Code]int{
+	codes.OK:                http.StatusOK,
+	codes.InvalidArgument:   http.StatusBadRequest,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.NotFound:          http.StatusNotFound,
+	codes.AlreadyExists:     http.StatusConflict,
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Internal:          http.StatusInternalServerError,
+	codes.Unimplemented:     http.StatusNotImplemented,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+	codes.Canceled:          http.StatusGatewayTimeout,
+}
+
+// Error is the error of a connector operation, with the status code of the REST API.
+// The code is kept as is for the REST API, and only the gRPC transport translates it to a gRPC code.
+type Error struct {
+	HTTPCode int
+	Message  string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// GRPCStatus returns the gRPC status of the error. The status codes of the REST API without a matching gRPC code
+// are translated to codes.Unknown.
+func (e *Error) GRPCStatus() *status.Status {
+	code, ok := httpCodes[e.HTTPCode]
+	if !ok {
+		code = codes.Unknown
+	}
+	return status.New(code, e.Message)
+}
+
+// NewError returns the error of a connector operation, for the status code of the REST API
+func NewError(httpCode int, message string) error {
+	return &Error{HTTPCode: httpCode, Message: message}
+}
+
+// grpcError returns the gRPC status error of an error of a connector operation, which may be wrapped
+func grpcError(err error) error {
+	var connectorErr *Error
+	if errors.As(err, &connectorErr) {
+		return connectorErr.GRPCStatus().Err()
+	}
+	return err
+}
+
+// HTTPStatus returns the status code of the REST API and the message of an error of a connector operation,
+// or of the gRPC status error of a call to a connector
+func HTTPStatus(err error) (int, string) {
+	var connectorErr *Error
+	if errors.As(err, &connectorErr) {
+		return connectorErr.HTTPCode, connectorErr.Message
+	}
+	s, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError, err.Error()
+	}
+	httpCode, ok := grpcCodes[s.Code()]
+	if !ok {
+		httpCode = http.StatusInternalServerError
+	}
+	return httpCode, s.Message()
+}
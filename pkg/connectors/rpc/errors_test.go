@@ -0,0 +1,33 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"net/http"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorStatus(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewWithT(t)
+
+	// the REST API gets the original status code, also if it has no gRPC code
+	err := errors.Wrap(NewError(http.StatusUnprocessableEntity, "invalid input"), "evaluation failed")
+	code, message := HTTPStatus(err)
+	g.Expect(code).To(gomega.Equal(http.StatusUnprocessableEntity))
+	g.Expect(message).To(gomega.Equal("invalid input"))
+	g.Expect(status.Code(grpcError(err))).To(gomega.Equal(codes.Unknown))
+
+	// the gRPC transport translates the status code, and its clients translate it back
+	err = grpcError(NewError(http.StatusNotFound, "asset does not exist"))
+	g.Expect(status.Code(err)).To(gomega.Equal(codes.NotFound))
+	code, message = HTTPStatus(err)
+	g.Expect(code).To(gomega.Equal(http.StatusNotFound))
+	g.Expect(message).To(gomega.Equal("asset does not exist"))
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"fybrik.io/fybrik/pkg/connectors/protos"
+	"fybrik.io/fybrik/pkg/model/datacatalog"
+	"fybrik.io/fybrik/pkg/model/policymanager"
+	"fybrik.io/fybrik/pkg/model/storagemanager"
+)
+
+// The credentials are passed in gRPC metadata with the same names as the headers of the REST API
+const (
+	DataCatalogCredsKey       = "x-request-datacatalog-cred"
+	DataCatalogWriteCredsKey  = "x-request-datacatalog-write-cred"
+	DataCatalogUpdateCredsKey = "x-request-datacatalog-update-cred"
+	PolicyManagerCredsKey     = "x-request-cred"
+)
+
+// DataCatalogServer is implemented by data catalog connectors that serve gRPC
+type DataCatalogServer interface {
+	GetAssetInfo(ctx context.Context, in *datacatalog.GetAssetRequest, creds string) (*datacatalog.GetAssetResponse, error)
+	CreateAsset(ctx context.Context, in *datacatalog.CreateAssetRequest, creds string) (*datacatalog.CreateAssetResponse, error)
+	DeleteAsset(ctx context.Context, in *datacatalog.DeleteAssetRequest, creds string) (*datacatalog.DeleteAssetResponse, error)
+	UpdateAsset(ctx context.Context, in *datacatalog.UpdateAssetRequest, creds string) (*datacatalog.UpdateAssetResponse, error)
+}
+
+// PolicyManagerServer is implemented by policy manager connectors that serve gRPC
+type PolicyManagerServer interface {
+	GetPoliciesDecisions(ctx context.Context, in *policymanager.GetPolicyDecisionsRequest,
+		creds string) (*policymanager.GetPolicyDecisionsResponse, error)
+}
+
+// StorageManagerServer is implemented by storage managers that serve gRPC
+type StorageManagerServer interface {
+	AllocateStorage(ctx context.Context, request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error)
+	DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) error
+	GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error)
+}
+
+// AssetInfoResult is the result of a request in the stream of BulkGetAssetInfo, with either the response to the
+// request at the same position in the request stream or its error
+type AssetInfoResult struct {
+	Response *datacatalog.GetAssetResponse
+	Error    *ErrorDetails
+}
+
+// ErrorDetails describes the error of a request in a stream, with the status code of the REST API
+type ErrorDetails struct {
+	Code    int
+	Message string
+}
+
+// incomingCreds returns the credentials in the metadata of the incoming request
+func incomingCreds(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// dataCatalogService serves the generated DataCatalog service with a data catalog connector
+type dataCatalogService struct {
+	protos.UnimplementedDataCatalogServer
+	server DataCatalogServer
+}
+
+func (s *dataCatalogService) GetAssetInfo(ctx context.Context, in *protos.GetAssetRequest) (*protos.GetAssetResponse, error) {
+	return grpcResult(s.getAssetInfo(ctx, in))
+}
+
+// getAssetInfo returns the errors of the connector with the status code of the REST API
+func (s *dataCatalogService) getAssetInfo(ctx context.Context, in *protos.GetAssetRequest) (*protos.GetAssetResponse, error) {
+	return serve(in, &protos.GetAssetResponse{},
+		func(request *datacatalog.GetAssetRequest) (*datacatalog.GetAssetResponse, error) {
+			return s.server.GetAssetInfo(ctx, request, incomingCreds(ctx, DataCatalogCredsKey))
+		})
+}
+
+func (s *dataCatalogService) CreateAsset(ctx context.Context, in *protos.CreateAssetRequest) (*protos.CreateAssetResponse, error) {
+	return grpcResult(serve(in, &protos.CreateAssetResponse{},
+		func(request *datacatalog.CreateAssetRequest) (*datacatalog.CreateAssetResponse, error) {
+			return s.server.CreateAsset(ctx, request, incomingCreds(ctx, DataCatalogWriteCredsKey))
+		}))
+}
+
+func (s *dataCatalogService) DeleteAsset(ctx context.Context, in *protos.DeleteAssetRequest) (*protos.DeleteAssetResponse, error) {
+	return grpcResult(serve(in, &protos.DeleteAssetResponse{},
+		func(request *datacatalog.DeleteAssetRequest) (*datacatalog.DeleteAssetResponse, error) {
+			return s.server.DeleteAsset(ctx, request, incomingCreds(ctx, DataCatalogCredsKey))
+		}))
+}
+
+func (s *dataCatalogService) UpdateAsset(ctx context.Context, in *protos.UpdateAssetRequest) (*protos.UpdateAssetResponse, error) {
+	return grpcResult(serve(in, &protos.UpdateAssetResponse{},
+		func(request *datacatalog.UpdateAssetRequest) (*datacatalog.UpdateAssetResponse, error) {
+			return s.server.UpdateAsset(ctx, request, incomingCreds(ctx, DataCatalogUpdateCredsKey))
+		}))
+}
+
+// BulkGetAssetInfo responds to each request in the stream with the result of GetAssetInfo, in the same order.
+// The error of a request has the status code of the REST API.
+func (s *dataCatalogService) BulkGetAssetInfo(stream protos.DataCatalog_BulkGetAssetInfoServer) error {
+	ctx := stream.Context()
+	for {
+		in, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		result := &protos.GetAssetInfoResult{}
+		if result.Response, err = s.getAssetInfo(ctx, in); err != nil {
+			code, message := HTTPStatus(err)
+			result.Error = &protos.ErrorDetails{Code: int32(code), Message: message}
+		}
+		if err = stream.Send(result); err != nil {
+			return err
+		}
+	}
+}
+
+// grpcResult returns the result of a connector operation with the gRPC status error of its error
+func grpcResult[Response any](response Response, err error) (Response, error) {
+	return response, grpcError(err)
+}
+
+// policyManagerService serves the generated PolicyManager service with a policy manager connector
+type policyManagerService struct {
+	protos.UnimplementedPolicyManagerServer
+	server PolicyManagerServer
+}
+
+func (s *policyManagerService) GetPoliciesDecisions(ctx context.Context,
+	in *protos.GetPolicyDecisionsRequest) (*protos.GetPolicyDecisionsResponse, error) {
+	return grpcResult(serve(in, &protos.GetPolicyDecisionsResponse{},
+		func(request *policymanager.GetPolicyDecisionsRequest) (*policymanager.GetPolicyDecisionsResponse, error) {
+			return s.server.GetPoliciesDecisions(ctx, request, incomingCreds(ctx, PolicyManagerCredsKey))
+		}))
+}
+
+// storageManagerService serves the generated StorageManager service with a storage manager
+type storageManagerService struct {
+	protos.UnimplementedStorageManagerServer
+	server StorageManagerServer
+}
+
+func (s *storageManagerService) AllocateStorage(ctx context.Context,
+	in *protos.AllocateStorageRequest) (*protos.AllocateStorageResponse, error) {
+	return grpcResult(serve(in, &protos.AllocateStorageResponse{},
+		func(request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error) {
+			return s.server.AllocateStorage(ctx, request)
+		}))
+}
+
+func (s *storageManagerService) DeleteStorage(ctx context.Context, in *protos.DeleteStorageRequest) (*emptypb.Empty, error) {
+	return grpcResult(serve(in, &emptypb.Empty{},
+		func(request *storagemanager.DeleteStorageRequest) (*struct{}, error) {
+			return &struct{}{}, s.server.DeleteStorage(ctx, request)
+		}))
+}
+
+func (s *storageManagerService) GetSupportedStorageTypes(ctx context.Context,
+	in *emptypb.Empty) (*protos.GetSupportedStorageTypesResponse, error) {
+	return grpcResult(serve(in, &protos.GetSupportedStorageTypesResponse{},
+		func(*struct{}) (*storagemanager.GetSupportedStorageTypesResponse, error) {
+			return s.server.GetSupportedStorageTypes(ctx)
+		}))
+}
+
+// RegisterDataCatalogServer registers the data catalog service of a connector
+func RegisterDataCatalogServer(s *grpc.Server, server DataCatalogServer) {
+	protos.RegisterDataCatalogServer(s, &dataCatalogService{server: server})
+}
+
+// RegisterPolicyManagerServer registers the policy manager service of a connector
+func RegisterPolicyManagerServer(s *grpc.Server, server PolicyManagerServer) {
+	protos.RegisterPolicyManagerServer(s, &policyManagerService{server: server})
+}
+
+// RegisterStorageManagerServer registers the storage manager service
+func RegisterStorageManagerServer(s *grpc.Server, server StorageManagerServer) {
+	protos.RegisterStorageManagerServer(s, &storageManagerService{server: server})
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+
+	"emperror.dev/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"fybrik.io/fybrik/pkg/environment"
+	fybrikTLS "fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
+)
+
+// URL schemes of connectors that are served with gRPC, without and with TLS
+const (
+	Scheme    = "grpc"
+	TLSScheme = "grpcs"
+)
+
+// IsGRPCURL returns true if the URL of a connector selects the gRPC transport
+func IsGRPCURL(connectorURL string) bool {
+	u, err := url.Parse(connectorURL)
+	return err == nil && (u.Scheme == Scheme || u.Scheme == TLSScheme)
+}
+
+// Dial returns a connection to the connector at a grpc://host:port or grpcs://host:port URL.
+// A grpcs connection uses the client TLS configuration of the fybrik components, which includes the client
+// certificate for mutual TLS if one is provided.
+// The deadline of the context of a call is propagated to the connector.
+func Dial(connectorURL string, log *zerolog.Logger) (*grpc.ClientConn, error) {
+	u, err := url.Parse(connectorURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid connector URL "+connectorURL)
+	}
+	transportCredentials := insecure.NewCredentials()
+	switch u.Scheme {
+	case Scheme:
+	case TLSScheme:
+		var tlsConfig *tls.Config
+		if tlsConfig, err = fybrikTLS.GetClientTLSConfig(log); err != nil {
+			return nil, errors.Wrap(err, "failed to get tls config")
+		}
+		transportCredentials = credentials.NewTLS(tlsConfig)
+	default:
+		return nil, errors.Errorf("connector URL %s does not have a %s or %s scheme", connectorURL, Scheme, TLSScheme)
+	}
+	return grpc.Dial(u.Host,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(injectTraceContext(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+			streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(injectTraceContext(ctx), desc, cc, method, opts...)
+		}),
+	)
+}
+
+// NewServer returns a gRPC server for a connector, which uses the server TLS configuration of the fybrik
+// components if TLS is enabled
+func NewServer(serviceName string, log *zerolog.Logger) (*grpc.Server, error) {
+	options := []grpc.ServerOption{grpc.UnaryInterceptor(tracingInterceptor(serviceName))}
+	if environment.IsUsingTLS() {
+		tlsConfig, err := fybrikTLS.GetServerConfig(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get tls config")
+		}
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	return grpc.NewServer(options...), nil
+}
+
+// Serve accepts gRPC connections on the given address
+func Serve(server *grpc.Server, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return server.Serve(listener)
+}
+
+// metadataCarrier propagates the trace context in gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// injectTraceContext adds the trace context to the outgoing metadata
+func injectTraceContext(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// tracingInterceptor creates a span for each call, in the trace of the caller
+func tracingInterceptor(serviceName string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		ctx, span := tracing.Tracer(serviceName).Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer))
+		resp, err := handler(ctx, req)
+		tracing.EndSpan(span, err)
+		return resp, err
+	}
+}
@@ -4,6 +4,8 @@
 package clients
 
 import (
+	"context"
+
 	"emperror.dev/errors"
 
 	"fybrik.io/fybrik/pkg/model/storagemanager"
@@ -18,8 +20,8 @@ func NewMockupStorageManager() StorageManagerInterface {
 	return &mockupStorageManager{}
 }
 
-func (m *mockupStorageManager) AllocateStorage(request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse,
-	error) {
+func (m *mockupStorageManager) AllocateStorage(ctx context.Context,
+	request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error) {
 	if request == nil {
 		return nil, errors.New("bad request")
 	}
@@ -28,11 +30,11 @@ func (m *mockupStorageManager) AllocateStorage(request *storagemanager.AllocateS
 	return resp, nil
 }
 
-func (m *mockupStorageManager) DeleteStorage(request *storagemanager.DeleteStorageRequest) error {
+func (m *mockupStorageManager) DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) error {
 	return nil
 }
 
-func (m *mockupStorageManager) GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error) {
+func (m *mockupStorageManager) GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error) {
 	return &storagemanager.GetSupportedStorageTypesResponse{ConnectionTypes: []taxonomy.ConnectionType{"mysql", "db2", "s3"}}, nil
 }
 
@@ -4,8 +4,10 @@
 package clients
 
 import (
+	"context"
 	"io"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/environment"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 )
@@ -14,14 +16,20 @@ import (
 type StorageManagerInterface interface {
 	// AllocateStorage allocates storage based on the selected storage account by invoking the specific implementation agent
 	// returns a Connection object in case of success, and an error - otherwise
-	AllocateStorage(request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error)
+	AllocateStorage(ctx context.Context, request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error)
 	// DeleteStorage deletes the allocated storage
-	DeleteStorage(request *storagemanager.DeleteStorageRequest) error
+	DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) error
 	// GetSupportedStorageTypes returns a list of supported connection types
-	GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error)
+	GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error)
 	io.Closer
 }
 
+// NewStorageManager creates a StorageManagerInterface facade to the storage manager, which is called with gRPC if its
+// address has a grpc:// or grpcs:// scheme, and with REST otherwise
 func NewStorageManager() (StorageManagerInterface, error) {
-	return NewOpenAPIStorageManager(environment.GetStorageManagerAddress()), nil
+	address := environment.GetStorageManagerAddress()
+	if rpc.IsGRPCURL(address) {
+		return NewGRPCStorageManager(address)
+	}
+	return NewOpenAPIStorageManager(address), nil
 }
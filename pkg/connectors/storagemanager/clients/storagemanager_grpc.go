@@ -0,0 +1,87 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package clients
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"fybrik.io/fybrik/pkg/connectors/protos"
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/model/storagemanager"
+)
+
+var _ StorageManagerInterface = (*grpcStorageManager)(nil)
+
+type grpcStorageManager struct {
+	conn   *grpc.ClientConn
+	client protos.StorageManagerClient
+}
+
+// NewGRPCStorageManager creates a StorageManagerInterface facade that connects to a gRPC service at a grpc:// or
+// grpcs:// URL
+func NewGRPCStorageManager(address string) (StorageManagerInterface, error) {
+	log := logging.LogInit(logging.SETUP, "storage manager client")
+	conn, err := rpc.Dial(address, &log)
+	if err != nil {
+		return nil, err
+	}
+	return &grpcStorageManager{conn: conn, client: protos.NewStorageManagerClient(conn)}, nil
+}
+
+// getGRPCError returns the error that the REST client returns for the same status
+func getGRPCError(err error) error {
+	switch status.Code(err) {
+	case codes.Unimplemented:
+		return errors.New(StorageTypeNotSupported)
+	case codes.Unavailable:
+		return errors.Wrap(err, StorageManagerCommunicationError)
+	default:
+		return err
+	}
+}
+
+// storage allocation request
+func (m *grpcStorageManager) AllocateStorage(ctx context.Context,
+	request *storagemanager.AllocateStorageRequest) (response *storagemanager.AllocateStorageResponse, err error) {
+	defer func() {
+		metrics.StorageOperations.WithLabelValues(metrics.AllocateOperation, string(request.AccountType), metrics.Result(err)).Inc()
+	}()
+	response = &storagemanager.AllocateStorageResponse{}
+	if err = rpc.Invoke(ctx, m.client.AllocateStorage, request, &protos.AllocateStorageRequest{}, response); err != nil {
+		return nil, getGRPCError(err)
+	}
+	return response, nil
+}
+
+// storage deletion request
+func (m *grpcStorageManager) DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) (err error) {
+	defer func() {
+		metrics.StorageOperations.WithLabelValues(metrics.DeleteOperation, string(request.Connection.Name), metrics.Result(err)).Inc()
+	}()
+	if err = rpc.Invoke(ctx, m.client.DeleteStorage, request, &protos.DeleteStorageRequest{}, nil); err != nil {
+		return getGRPCError(err)
+	}
+	return nil
+}
+
+// request to get supported connections
+func (m *grpcStorageManager) GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error) {
+	response := &storagemanager.GetSupportedStorageTypesResponse{}
+	if err := rpc.Invoke(ctx, m.client.GetSupportedStorageTypes, struct{}{}, &emptypb.Empty{}, response); err != nil {
+		return nil, getGRPCError(err)
+	}
+	return response, nil
+}
+
+func (m *grpcStorageManager) Close() error {
+	return m.conn.Close()
+}
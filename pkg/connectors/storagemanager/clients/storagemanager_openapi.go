@@ -12,8 +12,10 @@ import (
 
 	openapiclient "fybrik.io/fybrik/pkg/connectors/storagemanager/openapiclient"
 	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/tls"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 // ErrorMessages that are reported to the user
@@ -43,7 +45,7 @@ func NewOpenAPIStorageManager(address string) StorageManagerInterface {
 			},
 		},
 		OperationServers: map[string]openapiclient.ServerConfigurations{},
-		HTTPClient:       tls.GetHTTPClient(&log).StandardClient(),
+		HTTPClient:       tracing.WrapClient(tls.GetHTTPClient(&log).StandardClient()),
 	}
 	apiClient := openapiclient.NewAPIClient(configuration)
 
@@ -54,10 +56,13 @@ func NewOpenAPIStorageManager(address string) StorageManagerInterface {
 }
 
 // storage allocation request
-func (m *openAPIStorageManager) AllocateStorage(request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse,
-	error) {
+func (m *openAPIStorageManager) AllocateStorage(ctx context.Context,
+	request *storagemanager.AllocateStorageRequest) (response *storagemanager.AllocateStorageResponse, err error) {
+	defer func() {
+		metrics.StorageOperations.WithLabelValues(metrics.AllocateOperation, string(request.AccountType), metrics.Result(err)).Inc()
+	}()
 	resp, httpResponse, err :=
-		m.Client.DefaultApi.AllocateStorage(context.Background()).AllocateStorageRequest(*request).Execute()
+		m.Client.DefaultApi.AllocateStorage(ctx).AllocateStorageRequest(*request).Execute()
 	if httpResponse == nil {
 		if err != nil {
 			return nil, err
@@ -75,8 +80,11 @@ func (m *openAPIStorageManager) AllocateStorage(request *storagemanager.Allocate
 }
 
 // storage deletion request
-func (m *openAPIStorageManager) DeleteStorage(request *storagemanager.DeleteStorageRequest) error {
-	httpResponse, err := m.Client.DefaultApi.DeleteStorage(context.Background()).DeleteStorageRequest(*request).Execute()
+func (m *openAPIStorageManager) DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) (err error) {
+	defer func() {
+		metrics.StorageOperations.WithLabelValues(metrics.DeleteOperation, string(request.Connection.Name), metrics.Result(err)).Inc()
+	}()
+	httpResponse, err := m.Client.DefaultApi.DeleteStorage(ctx).DeleteStorageRequest(*request).Execute()
 	if httpResponse == nil {
 		if err != nil {
 			return err
@@ -91,9 +99,9 @@ func (m *openAPIStorageManager) DeleteStorage(request *storagemanager.DeleteStor
 }
 
 // request to get supported connections
-func (m *openAPIStorageManager) GetSupportedStorageTypes() (*storagemanager.GetSupportedStorageTypesResponse, error) {
+func (m *openAPIStorageManager) GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error) {
 	resp, httpResponse, err :=
-		m.Client.DefaultApi.GetSupportedStorageTypes(context.Background()).Execute()
+		m.Client.DefaultApi.GetSupportedStorageTypes(ctx).Execute()
 	if httpResponse == nil {
 		if err != nil {
 			return nil, err
@@ -44,11 +44,30 @@ const (
 	LocalZone                         string = "Zone"
 	LocalRegion                       string = "Region"
 	LocalVaultAuthPath                string = "VaultAuthPath"
+	LocalIngressIPBlocks              string = "IngressIPBlocks"
+	LocalEgressIPBlocks               string = "EgressIPBlocks"
 	ResourcesPollingInterval          string = "RESOURCE_POLLING_INTERVAL"
 	DiscoveryBurst                    string = "DISCOVERY_BURST"
 	DiscoveryQPS                      string = "DISCOVERY_QPS"
 	NPEnabled                         string = "NP_ENABLED"
+	NPBackend                         string = "NP_BACKEND"
+	IstioTrustDomain                  string = "ISTIO_TRUST_DOMAIN"
 	OpenShiftDeployment               string = "OPENSHIFT_DEPLOYMENT"
+	ConfigPolicyLanguageKey           string = "CONFIG_POLICY_LANGUAGE"
+	TracingExporterKey                string = "TRACING_EXPORTER"
+	TracingFileKey                    string = "TRACING_FILE"
+	ChartCacheMaxSizeKey              string = "CHART_CACHE_MAX_SIZE"
+	ChartCacheTagTTLKey               string = "CHART_CACHE_TAG_TTL"
+	ChartKeyringKey                   string = "CHART_KEYRING"
+	BlueprintRolloutStrategyKey       string = "BLUEPRINT_ROLLOUT_STRATEGY"
+	BlueprintRolloutTimeoutKey        string = "BLUEPRINT_ROLLOUT_TIMEOUT"
+	VaultTokenKey                     string = "VAULT_TOKEN"
+	VaultDynamicSecretsPathsKey       string = "VAULT_DYNAMIC_SECRETS_PATHS"
+	VaultLeasedSecretsPathKey         string = "VAULT_LEASED_SECRETS_PATH"
+	CredentialBrokerEnabledKey        string = "CREDENTIAL_BROKER_ENABLED"
+	CatalogRegistryFileKey            string = "CATALOG_REGISTRY_FILE"
+	AdditionalPolicyManagersKey       string = "ADDITIONAL_POLICY_MANAGERS"
+	GRPCPortKey                       string = "GRPC_PORT"
 )
 
 const printValueStr = "%s set to \"%s\""
@@ -79,6 +98,16 @@ func GetLocalVaultAuthPath() string {
 	return os.Getenv(LocalVaultAuthPath)
 }
 
+// GetLocalIngressIPBlocks returns a comma separated list of CIDRs of the local cluster ingress gateways
+func GetLocalIngressIPBlocks() string {
+	return os.Getenv(LocalIngressIPBlocks)
+}
+
+// GetLocalEgressIPBlocks returns a comma separated list of CIDRs of the local cluster egress gateways
+func GetLocalEgressIPBlocks() string {
+	return os.Getenv(LocalEgressIPBlocks)
+}
+
 func GetCatalogProvider() string {
 	return os.Getenv(CatalogProviderNameKey)
 }
@@ -156,6 +185,48 @@ func IsNPEnabled() bool {
 	return strings.ToLower(os.Getenv(NPEnabled)) == "true"
 }
 
+// GetNPBackend returns the backend used for the network isolation of the modules, networkpolicy by default
+func GetNPBackend() string {
+	backend := strings.ToLower(os.Getenv(NPBackend))
+	if backend == "" {
+		return "networkpolicy"
+	}
+	return backend
+}
+
+// GetIstioTrustDomain returns the trust domain of the workload identities in the service mesh
+func GetIstioTrustDomain() string {
+	if domain := os.Getenv(IstioTrustDomain); domain != "" {
+		return domain
+	}
+	return "cluster.local"
+}
+
+// GetBlueprintRolloutStrategy returns the strategy used to roll out blueprint changes, inplace by default
+func GetBlueprintRolloutStrategy() string {
+	strategy := strings.ToLower(os.Getenv(BlueprintRolloutStrategyKey))
+	if strategy == "" {
+		return "inplace"
+	}
+	return strategy
+}
+
+// GetBlueprintRolloutTimeout returns the time the new module releases of a blueprint may take to become ready
+// before the rollout is rolled back.
+// The function returns a default value if an error occurs or if the env var is undefined.
+func GetBlueprintRolloutTimeout() (time.Duration, error) {
+	defaultTimeout := 10 * time.Minute
+	timeoutStr := os.Getenv(BlueprintRolloutTimeoutKey)
+	if timeoutStr == "" {
+		return defaultTimeout, nil
+	}
+	timeout, err := time.ParseDuration(timeoutStr)
+	if err != nil {
+		return defaultTimeout, err
+	}
+	return timeout, nil
+}
+
 func IsOpenShiftDeployment() bool {
 	return strings.ToLower(os.Getenv(OpenShiftDeployment)) == "true"
 }
@@ -170,6 +241,12 @@ func IsVaultEnabled() bool {
 	return v == "true"
 }
 
+// IsCredentialBrokerEnabled returns true if the manager delivers the data credentials to the modules in Kubernetes
+// secrets. The credential broker is used only when Vault is disabled.
+func IsCredentialBrokerEnabled() bool {
+	return strings.ToLower(os.Getenv(CredentialBrokerEnabledKey)) == "true" && !IsVaultEnabled()
+}
+
 // GetModulesRole returns the modules assigned authentication role for accessing dataset credentials
 func GetModulesRole() string {
 	return os.Getenv(VaultModulesRoleKey)
@@ -208,6 +285,22 @@ func GetDiscoveryBurst() (int, error) {
 	return burst, err
 }
 
+// GetGRPCPort returns the port on which a connector serves gRPC if it is set, otherwise it returns 0
+func GetGRPCPort() (int, error) {
+	portStr := os.Getenv(GRPCPortKey)
+	if portStr == "" {
+		return 0, nil
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return 0, err
+	}
+	if port < 0 {
+		return 0, fmt.Errorf("%s should not be negative, got %d", GRPCPortKey, port)
+	}
+	return port, nil
+}
+
 // GetDiscoveryQPS returns the K8s discovery QPS value if it is set, otherwise it returns -1
 func GetDiscoveryQPS() (float32, error) {
 	qpsStr := os.Getenv(DiscoveryQPS)
@@ -225,12 +318,80 @@ func GetDiscoveryQPS() (float32, error) {
 	return float32(qps), err
 }
 
+// GetChartCacheMaxSize returns the maximal size in MiB of the chart cache, 0 disables the cache.
+// The function returns a default value if an error occurs or if ChartCacheMaxSizeKey env var is undefined.
+func GetChartCacheMaxSize() (int64, error) {
+	//nolint:revive // ignore magic numbers
+	var defaultSize int64 = 100
+	sizeStr := os.Getenv(ChartCacheMaxSizeKey)
+	if sizeStr == "" {
+		return defaultSize, nil
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil {
+		return defaultSize, err
+	}
+	if size < 0 {
+		return defaultSize, fmt.Errorf("chart cache size should not be negative, got %d", size)
+	}
+	return size, nil
+}
+
+// GetChartCacheTagTTL returns the time during which a cached chart tag is used without resolving it again
+// in the registry. The function returns a default value if an error occurs or if ChartCacheTagTTLKey env var
+// is undefined.
+func GetChartCacheTagTTL() (time.Duration, error) {
+	defaultTTL := 10 * time.Minute
+	ttlStr := os.Getenv(ChartCacheTagTTLKey)
+	if ttlStr == "" {
+		return defaultTTL, nil
+	}
+	ttl, err := time.ParseDuration(ttlStr)
+	if err != nil {
+		return defaultTTL, err
+	}
+	return ttl, nil
+}
+
+// GetChartKeyring returns the path of the keyring used to verify the provenance of the module charts.
+// The provenance is not verified if it is empty.
+func GetChartKeyring() string {
+	return os.Getenv(ChartKeyringKey)
+}
+
 // GetVaultAddress returns the address and port of the vault system,
 // which is used for managing data set credentials
 func GetVaultAddress() string {
 	return os.Getenv(VaultAddressKey)
 }
 
+// GetVaultToken returns the token used by the manager to authenticate with vault
+func GetVaultToken() string {
+	return os.Getenv(VaultTokenKey)
+}
+
+// GetVaultDynamicSecretsPaths returns the paths of the vault dynamic secrets engines, e.g. database/creds.
+// The manager leases the credentials for the modules if the catalog points to a secret under one of these paths.
+func GetVaultDynamicSecretsPaths() []string {
+	paths := []string{}
+	for _, path := range strings.Split(os.Getenv(VaultDynamicSecretsPathsKey), ",") {
+		if path = strings.Trim(strings.TrimSpace(path), "/"); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// GetVaultLeasedSecretsPath returns the path of the key-value secrets engine in which the manager stores
+// the leased credentials for the modules, fybrik-leases by default
+func GetVaultLeasedSecretsPath() string {
+	path := strings.Trim(os.Getenv(VaultLeasedSecretsPathKey), "/")
+	if path == "" {
+		return "fybrik-leases"
+	}
+	return path
+}
+
 // GetDataPathMaxSize bounds the data path size (number of modules that access data for read/write/copy,
 // not including transformations)
 // The function returns a default value if an error occurs or if DatapathLimitKey env var
@@ -253,6 +414,29 @@ func UseCSP() bool {
 	return os.Getenv(UseCSPKey) == "true"
 }
 
+// GetConfigPolicyLanguage returns the language of the admin config policies: rego (default) or cel
+func GetConfigPolicyLanguage() string {
+	language := strings.ToLower(os.Getenv(ConfigPolicyLanguageKey))
+	if language == "" {
+		return "rego"
+	}
+	return language
+}
+
+// GetTracingExporter returns the exporter of the trace spans: none (default), otlp or file
+func GetTracingExporter() string {
+	exporter := strings.ToLower(os.Getenv(TracingExporterKey))
+	if exporter == "" {
+		return "none"
+	}
+	return exporter
+}
+
+// GetTracingFile returns the file the trace spans are written to when the file exporter is used
+func GetTracingFile() string {
+	return os.Getenv(TracingFileKey)
+}
+
 // GetCSPPath returns the path of the CSP solver to use when generating a plotter, or "" if no CSP solver is defined
 func GetCSPPath() string {
 	return os.Getenv(CSPPathKey)
@@ -268,6 +452,30 @@ func GetDataCatalogServiceAddress() string {
 	return os.Getenv(CatalogConnectorServiceAddressKey)
 }
 
+// GetCatalogRegistryFile returns the file that defines the data catalog connectors in addition to the default one,
+// or "" if there are no other connectors
+func GetCatalogRegistryFile() string {
+	return os.Getenv(CatalogRegistryFileKey)
+}
+
+// GetAdditionalPolicyManagers returns the policy managers whose decisions are combined with the decisions of the
+// main policy manager, as a map from the policy manager names to their connector URLs.
+// The policy managers are defined in the format name=url,name=url
+func GetAdditionalPolicyManagers() (map[string]string, error) {
+	policyManagers := map[string]string{}
+	for _, policyManager := range strings.Split(os.Getenv(AdditionalPolicyManagersKey), ",") {
+		if policyManager = strings.TrimSpace(policyManager); policyManager == "" {
+			continue
+		}
+		name, url, found := strings.Cut(policyManager, "=")
+		if !found || name == "" || url == "" {
+			return nil, fmt.Errorf("invalid policy manager %q in %s", policyManager, AdditionalPolicyManagersKey)
+		}
+		policyManagers[name] = url
+	}
+	return policyManagers, nil
+}
+
 // GetStorageManagerAddress returns the address of storage manager
 func GetStorageManagerAddress() string {
 	return os.Getenv(StorageManagerAddressKey)
@@ -297,7 +505,9 @@ func LogEnvVariables(log *zerolog.Logger) {
 	envVarArray := [...]string{CatalogConnectorServiceAddressKey, StorageManagerAddressKey, VaultAddressKey, VaultModulesRoleKey,
 		EnableWebhooksKey, MainPolicyManagerConnectorURLKey,
 		MainPolicyManagerNameKey, LoggingVerbosityKey, PrettyLoggingKey,
-		DataDir, ModuleNamespace, ControllerNamespace, ApplicationNamespace, MinTLSVersion, NPEnabled}
+		DataDir, ModuleNamespace, ControllerNamespace, ApplicationNamespace, MinTLSVersion, NPEnabled, NPBackend, TracingExporterKey,
+		ChartKeyringKey, BlueprintRolloutStrategyKey, VaultDynamicSecretsPathsKey, VaultLeasedSecretsPathKey,
+		CredentialBrokerEnabledKey, CatalogRegistryFileKey, AdditionalPolicyManagersKey}
 
 	log.Info().Msg("Manager configured with the following environment variables:")
 	for _, envVar := range envVarArray {
@@ -312,4 +522,10 @@ func LogEnvVariables(log *zerolog.Logger) {
 	logEnvVarUpdatedValue(log, DiscoveryQPS, fmt.Sprintf("%f", discoveryQPS), err)
 	dataPathMaxSize, err := GetDataPathMaxSize()
 	logEnvVarUpdatedValue(log, DatapathLimitKey, strconv.Itoa(dataPathMaxSize), err)
+	chartCacheMaxSize, err := GetChartCacheMaxSize()
+	logEnvVarUpdatedValue(log, ChartCacheMaxSizeKey, strconv.FormatInt(chartCacheMaxSize, 10), err)
+	chartCacheTagTTL, err := GetChartCacheTagTTL()
+	logEnvVarUpdatedValue(log, ChartCacheTagTTLKey, chartCacheTagTTL.String(), err)
+	rolloutTimeout, err := GetBlueprintRolloutTimeout()
+	logEnvVarUpdatedValue(log, BlueprintRolloutTimeoutKey, rolloutTimeout.String(), err)
 }
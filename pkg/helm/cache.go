@@ -0,0 +1,293 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"helm.sh/helm/v3/pkg/downloader"
+
+	"fybrik.io/fybrik/pkg/metrics"
+)
+
+const (
+	cacheIndexFile = "index.json"
+	cacheBlobsDir  = "blobs"
+	chartExtension = ".tgz"
+	provExtension  = ".prov"
+	digestPrefix   = "sha256:"
+)
+
+// PulledChart is a chart archive pulled from a registry
+type PulledChart struct {
+	Ref string
+	// ManifestDigest is the digest of the OCI manifest of the chart
+	ManifestDigest string
+	// Digest is the digest of the chart archive
+	Digest string
+	Data   []byte
+	// Prov is the content of the provenance file, if it was pulled
+	Prov []byte
+}
+
+// PullFunc pulls a chart, and its provenance file if withProv is set, from the registry
+type PullFunc func(ref string, withProv bool) (*PulledChart, error)
+
+// cachedRef records the digest that a chart reference resolved to when it was last pulled
+type cachedRef struct {
+	Digest    string    `json:"digest"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// cachedChart describes a chart archive stored in the cache
+type cachedChart struct {
+	Size     int64     `json:"size"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+type cacheIndex struct {
+	Refs   map[string]*cachedRef   `json:"refs"`
+	Charts map[string]*cachedChart `json:"charts"`
+}
+
+// ChartCache is a content-addressed store of the charts pulled from OCI registries.
+// Charts are stored by the digest of their archive. References that contain a digest are immutable and
+// are never pulled again, while tags are resolved again from the registry once tagTTL has elapsed.
+// The least recently used charts are evicted when the total size of the cache exceeds maxSize.
+type ChartCache struct {
+	dir     string
+	maxSize int64
+	tagTTL  time.Duration
+	// keyring is the path of the public keyring used to verify the chart provenance files.
+	// Provenance verification is skipped if it is empty.
+	keyring string
+	now     func() time.Time
+
+	mutex sync.Mutex
+	index *cacheIndex
+}
+
+// NewChartCache returns a chart cache stored in dir. The index of a previous cache in dir is reused.
+func NewChartCache(dir string, maxSize int64, tagTTL time.Duration, keyring string) (*ChartCache, error) {
+	if err := os.MkdirAll(filepath.Join(dir, cacheBlobsDir), 0o700); err != nil {
+		return nil, errors.WithMessage(err, "failed to create chart cache directory")
+	}
+	c := &ChartCache{
+		dir:     dir,
+		maxSize: maxSize,
+		tagTTL:  tagTTL,
+		keyring: keyring,
+		now:     time.Now,
+		index:   &cacheIndex{Refs: map[string]*cachedRef{}, Charts: map[string]*cachedChart{}},
+	}
+	data, err := os.ReadFile(filepath.Join(dir, cacheIndexFile))
+	switch {
+	case os.IsNotExist(err):
+		return c, nil
+	case err != nil:
+		return nil, errors.WithMessage(err, "failed to read chart cache index")
+	}
+	// a corrupted index is discarded, the charts will be pulled again
+	if json.Unmarshal(data, c.index) != nil || c.index.Refs == nil || c.index.Charts == nil {
+		c.index = &cacheIndex{Refs: map[string]*cachedRef{}, Charts: map[string]*cachedChart{}}
+	}
+	return c, nil
+}
+
+// Get returns the path of the chart archive of the given reference, pulling the chart if it is not cached
+// or if the cached digest of a tag has expired.
+func (c *ChartCache) Get(ref string, pull PullFunc) (string, error) {
+	if path, found := c.lookup(ref); found {
+		metrics.ChartCacheRequests.WithLabelValues(metrics.HitResult).Inc()
+		return path, nil
+	}
+	metrics.ChartCacheRequests.WithLabelValues(metrics.MissResult).Inc()
+
+	result, err := pull(ref, c.keyring != "")
+	if err != nil {
+		return "", err
+	}
+	if err = verifyDigest(ref, result); err != nil {
+		return "", err
+	}
+	return c.store(ref, result)
+}
+
+// lookup returns the path of a cached chart that can be used for the reference without contacting the registry
+func (c *ChartCache) lookup(ref string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, found := c.index.Refs[ref]
+	if !found {
+		return "", false
+	}
+	chrt, found := c.index.Charts[entry.Digest]
+	if !found {
+		return "", false
+	}
+	if !isDigestReference(ref) && c.now().Sub(entry.FetchedAt) > c.tagTTL {
+		return "", false
+	}
+	path := c.chartPath(entry.Digest)
+	if _, err := os.Stat(path); err != nil {
+		delete(c.index.Charts, entry.Digest)
+		return "", false
+	}
+	chrt.LastUsed = c.now()
+	return path, true
+}
+
+// store adds a pulled chart to the cache, verifies its provenance and evicts old charts if needed
+func (c *ChartCache) store(ref string, result *PulledChart) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	digest := result.Digest
+	path := c.chartPath(digest)
+	_, found := c.index.Charts[digest]
+	if _, err := os.Stat(path); !found || err != nil {
+		if err := writeFileAtomic(path, result.Data); err != nil {
+			return "", err
+		}
+		if c.keyring != "" {
+			if err := c.verifyProvenance(path, result); err != nil {
+				os.Remove(path)
+				os.Remove(path + provExtension)
+				return "", err
+			}
+		}
+		c.index.Charts[digest] = &cachedChart{Size: int64(len(result.Data))}
+	}
+	c.index.Charts[digest].LastUsed = c.now()
+	c.index.Refs[ref] = &cachedRef{Digest: digest, FetchedAt: c.now()}
+	c.evict(digest)
+	return path, c.saveIndex()
+}
+
+func (c *ChartCache) verifyProvenance(path string, result *PulledChart) error {
+	if len(result.Prov) == 0 {
+		return errors.Errorf("chart %s has no provenance file", result.Ref)
+	}
+	if err := writeFileAtomic(path+provExtension, result.Prov); err != nil {
+		return err
+	}
+	if _, err := downloader.VerifyChart(path, c.keyring); err != nil {
+		return errors.WithMessagef(err, "failed to verify the provenance of chart %s", result.Ref)
+	}
+	return nil
+}
+
+// evict removes the least recently used charts until the cache size is within its bound.
+// The chart that was just added is never evicted.
+func (c *ChartCache) evict(keep string) {
+	var size int64
+	digests := []string{}
+	for digest, chrt := range c.index.Charts {
+		size += chrt.Size
+		if digest != keep {
+			digests = append(digests, digest)
+		}
+	}
+	sort.Slice(digests, func(i, j int) bool {
+		return c.index.Charts[digests[i]].LastUsed.Before(c.index.Charts[digests[j]].LastUsed)
+	})
+	for _, digest := range digests {
+		if size <= c.maxSize {
+			break
+		}
+		size -= c.index.Charts[digest].Size
+		delete(c.index.Charts, digest)
+		os.Remove(c.chartPath(digest))
+		os.Remove(c.chartPath(digest) + provExtension)
+		for ref, entry := range c.index.Refs {
+			if entry.Digest == digest {
+				delete(c.index.Refs, ref)
+			}
+		}
+	}
+}
+
+func (c *ChartCache) saveIndex() error {
+	data, err := json.Marshal(c.index)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(c.dir, cacheIndexFile), data)
+}
+
+func (c *ChartCache) chartPath(digest string) string {
+	return filepath.Join(c.dir, cacheBlobsDir, strings.TrimPrefix(digest, digestPrefix)+chartExtension)
+}
+
+// verifyDigest checks that the pulled chart matches its digest, and that the manifest matches the digest
+// of the reference if the reference contains one
+func verifyDigest(ref string, result *PulledChart) error {
+	if !strings.HasPrefix(result.Digest, digestPrefix) {
+		return errors.Errorf("chart %s was pulled without a sha256 digest", ref)
+	}
+	sum := sha256.Sum256(result.Data)
+	if digestPrefix+hex.EncodeToString(sum[:]) != result.Digest {
+		return errors.Errorf("chart %s does not match its digest %s", ref, result.Digest)
+	}
+	if isDigestReference(ref) {
+		chartRef, err := parseReference(ref)
+		if err != nil {
+			return err
+		}
+		if result.ManifestDigest != chartRef.Reference {
+			return errors.Errorf("manifest of chart %s does not match the requested digest", ref)
+		}
+	}
+	return nil
+}
+
+// isDigestReference returns true if the reference points to a manifest digest rather than to a tag
+func isDigestReference(ref string) bool {
+	chartRef, err := parseReference(ref)
+	if err != nil {
+		return false
+	}
+	_, err = chartRef.Digest()
+	return err == nil
+}
+
+// writeFileAtomic writes the file through a temporary file, so that a partially written file is never used
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// linkOrCopy makes the cached file available at destination, copying it if it can not be hard linked
+func linkOrCopy(source, destination string) error {
+	os.Remove(destination)
+	if err := os.Link(source, destination); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(destination, data, 0o600)
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	tagRef = "registry.example.com/charts/mychart:0.7.0"
+	// digest of the manifest referenced by digestRef
+	manifestDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	digestRef      = "registry.example.com/charts/mychart@" + manifestDigest
+)
+
+// fakeRegistry serves chart archives and counts the pulls
+type fakeRegistry struct {
+	charts map[string][]byte
+	pulls  int
+}
+
+func (f *fakeRegistry) pull(ref string, withProv bool) (*PulledChart, error) {
+	f.pulls++
+	data, found := f.charts[ref]
+	if !found {
+		return nil, errors.New("not found")
+	}
+	sum := sha256.Sum256(data)
+	return &PulledChart{Ref: ref, ManifestDigest: manifestDigest, Digest: "sha256:" + hex.EncodeToString(sum[:]), Data: data}, nil
+}
+
+func newTestCache(t *testing.T, maxSize int64) (*ChartCache, *time.Time) {
+	cache, err := NewChartCache(t.TempDir(), maxSize, time.Minute, "")
+	assert.Nil(t, err)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	return cache, &now
+}
+
+func TestChartCacheTagTTL(t *testing.T) {
+	cache, now := newTestCache(t, 1024)
+	reg := &fakeRegistry{charts: map[string][]byte{tagRef: []byte("chart-v1")}}
+
+	path, err := cache.Get(tagRef, reg.pull)
+	assert.Nil(t, err)
+	data, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "chart-v1", string(data))
+
+	// the tag is served from the cache until its TTL expires
+	_, err = cache.Get(tagRef, reg.pull)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reg.pulls)
+
+	reg.charts[tagRef] = []byte("chart-v2")
+	*now = now.Add(2 * time.Minute)
+	path, err = cache.Get(tagRef, reg.pull)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reg.pulls)
+	data, err = os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "chart-v2", string(data))
+}
+
+func TestChartCacheDigestReference(t *testing.T) {
+	cache, now := newTestCache(t, 1024)
+	reg := &fakeRegistry{charts: map[string][]byte{digestRef: []byte("chart")}}
+
+	_, err := cache.Get(digestRef, reg.pull)
+	assert.Nil(t, err)
+	// digest references are immutable and never expire
+	*now = now.Add(time.Hour)
+	_, err = cache.Get(digestRef, reg.pull)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reg.pulls)
+
+	// the index is reused by a new cache in the same directory
+	reloaded, err := NewChartCache(cache.dir, 1024, time.Minute, "")
+	assert.Nil(t, err)
+	_, err = reloaded.Get(digestRef, reg.pull)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reg.pulls)
+}
+
+func TestChartCacheVerification(t *testing.T) {
+	cache, _ := newTestCache(t, 1024)
+	reg := &fakeRegistry{charts: map[string][]byte{tagRef: []byte("chart")}}
+
+	corrupted := func(ref string, withProv bool) (*PulledChart, error) {
+		result, err := reg.pull(ref, withProv)
+		if err == nil {
+			result.Data = []byte("tampered")
+		}
+		return result, err
+	}
+	_, err := cache.Get(tagRef, corrupted)
+	assert.NotNil(t, err)
+
+	// the manifest must match the digest of the reference
+	otherDigestRef := "registry.example.com/charts/mychart@sha256:" + hex.EncodeToString(make([]byte, sha256.Size))
+	reg.charts[otherDigestRef] = []byte("chart")
+	_, err = cache.Get(otherDigestRef, reg.pull)
+	assert.NotNil(t, err)
+
+	// charts without provenance are rejected when a keyring is configured
+	cache.keyring = "pubring.gpg"
+	_, err = cache.Get(tagRef, reg.pull)
+	assert.NotNil(t, err)
+	assert.NotContains(t, cache.index.Refs, tagRef)
+	sum := sha256.Sum256([]byte("chart"))
+	_, err = os.Stat(cache.chartPath("sha256:" + hex.EncodeToString(sum[:])))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestChartCacheEviction(t *testing.T) {
+	cache, now := newTestCache(t, 10)
+	refs := []string{
+		"registry.example.com/charts/a:1.0.0",
+		"registry.example.com/charts/b:1.0.0",
+		"registry.example.com/charts/c:1.0.0",
+	}
+	reg := &fakeRegistry{charts: map[string][]byte{refs[0]: []byte("aaaa"), refs[1]: []byte("bbbb"), refs[2]: []byte("cccc")}}
+
+	paths := []string{}
+	for _, ref := range refs[:2] {
+		path, err := cache.Get(ref, reg.pull)
+		assert.Nil(t, err)
+		paths = append(paths, path)
+		*now = now.Add(time.Second)
+	}
+	// a is used again, so b is the least recently used chart
+	_, err := cache.Get(refs[0], reg.pull)
+	assert.Nil(t, err)
+	*now = now.Add(time.Second)
+	_, err = cache.Get(refs[2], reg.pull)
+	assert.Nil(t, err)
+
+	_, err = os.Stat(paths[0])
+	assert.Nil(t, err)
+	_, err = os.Stat(paths[1])
+	assert.True(t, os.IsNotExist(err))
+	assert.NotContains(t, cache.index.Refs, refs[1])
+	assert.Equal(t, 3, reg.pulls)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
@@ -21,12 +22,19 @@ import (
 	oras "oras.land/oras-go/pkg/registry"
 
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/logging"
+	"fybrik.io/fybrik/pkg/metrics"
 )
 
 // Relevant only when helm charts are placed in
 // local directory.
 const chartsDir = "/charts/"
 
+const (
+	chartCacheDir = "charts-cache"
+	mebibyte      = 1 << 20
+)
+
 // Interface of a helm chart
 type Interface interface {
 	GetConfig(kubeNamespace string, log action.DebugLog) (*action.Configuration, error)
@@ -134,6 +142,8 @@ type Impl struct {
 	localChartsMountPath string
 	discoveryBurst       int
 	discoveryQPS         float32
+	// if set, the pulled charts are kept in the cache and are pulled again only if their digest changes
+	cache *ChartCache
 }
 
 func NewHelmerImpl(chartsPath string) *Impl {
@@ -142,9 +152,33 @@ func NewHelmerImpl(chartsPath string) *Impl {
 	impl.discoveryBurst, _ = environment.GetDiscoveryBurst()
 	// If an error exists it is logged in LogEnvVariables
 	impl.discoveryQPS, _ = environment.GetDiscoveryQPS()
+	if chartsPath == "" {
+		// If an error exists it is logged in LogEnvVariables
+		cacheSize, _ := environment.GetChartCacheMaxSize()
+		tagTTL, _ := environment.GetChartCacheTagTTL()
+		if cacheSize > 0 {
+			cache, err := NewChartCache(filepath.Join(dataDir(), chartCacheDir), cacheSize*mebibyte, tagTTL,
+				environment.GetChartKeyring())
+			if err != nil {
+				// the cache is disabled, and the charts are pulled on every deployment
+				log := logging.LogInit(logging.SETUP, "helm")
+				log.Warn().Err(err).Msg("The chart cache is disabled, charts are pulled without caching")
+			} else {
+				impl.cache = cache
+			}
+		}
+	}
 	return &impl
 }
 
+// dataDir returns the directory where the charts are stored
+func dataDir() string {
+	if dir := environment.GetDataDir(); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
 // Uninstall helm release
 func (r *Impl) Uninstall(cfg *action.Configuration, releaseName string) (*release.UninstallReleaseResponse, error) {
 	uninstall := action.NewUninstall(cfg)
@@ -168,10 +202,13 @@ func (r *Impl) Load(ref, chartPath string) (*chart.Chart, error) {
 	if err != nil {
 		return nil, err
 	}
-	_, chartName := filepath.Split(chartRef.Repository)
-	packedChartPath := fmt.Sprintf("%s/%s-%s.tgz", chartPath, chartName, chartRef.Reference)
+	return loader.Load(packedChartPath(chartPath, chartRef))
+}
 
-	return loader.Load(packedChartPath)
+// packedChartPath returns the path of the chart archive in the pull destination directory
+func packedChartPath(destination string, chartRef oras.Reference) string {
+	_, chartName := filepath.Split(chartRef.Repository)
+	return fmt.Sprintf("%s/%s-%s.tgz", destination, chartName, chartRef.Reference)
 }
 
 // Install helm release from packaged chart
@@ -181,7 +218,10 @@ func (r *Impl) Install(ctx context.Context, cfg *action.Configuration, chrt *cha
 	install.ReleaseName = releaseName
 	install.Namespace = kubeNamespace
 
-	return install.RunWithContext(ctx, chrt, vals)
+	start := time.Now()
+	rel, err := install.RunWithContext(ctx, chrt, vals)
+	metrics.ObserveHelmOperation(metrics.InstallOperation, start, err)
+	return rel, err
 }
 
 // Upgrade helm release
@@ -190,7 +230,10 @@ func (r *Impl) Upgrade(ctx context.Context, cfg *action.Configuration, chrt *cha
 	upgrade := action.NewUpgrade(cfg)
 	upgrade.Namespace = kubeNamespace
 
-	return upgrade.RunWithContext(ctx, releaseName, chrt, vals)
+	start := time.Now()
+	rel, err := upgrade.RunWithContext(ctx, releaseName, chrt, vals)
+	metrics.ObserveHelmOperation(metrics.UpgradeOperation, start, err)
+	return rel, err
 }
 
 // Status of helm release
@@ -263,6 +306,13 @@ func (r *Impl) Pull(cfg *action.Configuration, ref, destination string) error {
 	if err != nil {
 		return err
 	}
+	if r.cache != nil {
+		cachedChart, cacheErr := r.cache.Get(chartRef.String(), pullChart)
+		if cacheErr != nil {
+			return cacheErr
+		}
+		return linkOrCopy(cachedChart, packedChartPath(destination, chartRef))
+	}
 
 	var settings = cli.New()
 	registryClient, err := registry.NewClient(registry.ClientOptDebug(settings.Debug),
@@ -281,6 +331,32 @@ func (r *Impl) Pull(cfg *action.Configuration, ref, destination string) error {
 	return err
 }
 
+// pullChart pulls the chart archive, and optionally its provenance file, with the registry client
+func pullChart(ref string, withProv bool) (*PulledChart, error) {
+	var settings = cli.New()
+	registryClient, err := registry.NewClient(registry.ClientOptDebug(settings.Debug),
+		registry.ClientOptWriter(os.Stdout),
+		registry.ClientOptCredentialsFile(settings.RegistryConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	result, err := registryClient.Pull(ref, registry.PullOptWithProv(withProv))
+	if err != nil {
+		return nil, err
+	}
+	chrt := &PulledChart{
+		Ref:            result.Ref,
+		ManifestDigest: result.Manifest.Digest,
+		Digest:         result.Chart.Digest,
+		Data:           result.Chart.Data,
+	}
+	if result.Prov != nil {
+		chrt.Prov = result.Prov.Data
+	}
+	return chrt, nil
+}
+
 func (r *Impl) GetConfig(kubeNamespace string, log action.DebugLog) (*action.Configuration, error) {
 	actionConfig := new(action.Configuration)
 
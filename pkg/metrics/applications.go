@@ -0,0 +1,46 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// CountFunc returns the number of applications per state
+type CountFunc func() (map[string]int, error)
+
+// applicationsCollector reports the number of applications per state whenever the metrics are scraped
+type applicationsCollector struct {
+	count CountFunc
+}
+
+func (c *applicationsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- ApplicationsDesc
+}
+
+func (c *applicationsCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.count()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(ApplicationsDesc, err)
+		return
+	}
+	for _, state := range []string{ReadyState, DenyState, ErrorState, PendingState} {
+		ch <- prometheus.MustNewConstMetric(ApplicationsDesc, prometheus.GaugeValue, float64(counts[state]), state)
+	}
+}
+
+// RegisterApplicationsCollector registers a gauge of the number of applications per state.
+// The gauge values are computed by the given function when the metrics are scraped.
+// A collector that has been registered before is kept.
+func RegisterApplicationsCollector(count CountFunc) error {
+	err := metrics.Registry.Register(&applicationsCollector{count: count})
+	var registered prometheus.AlreadyRegisteredError
+	if errors.As(err, &registered) {
+		return nil
+	}
+	return err
+}
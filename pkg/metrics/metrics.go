@@ -0,0 +1,250 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// Namespace is the common prefix of all fybrik metrics
+	Namespace = "fybrik"
+
+	// ChartLabel is the label holding the module chart name
+	ChartLabel = "chart"
+	// ControllerLabel is the label holding the controller name
+	ControllerLabel = "controller"
+	// ResultLabel is the label holding the outcome of an operation
+	ResultLabel = "result"
+	// ReasonLabel is the label holding the failure reason
+	ReasonLabel = "reason"
+	// ConnectorLabel is the label holding the connector type, e.g. datacatalog
+	ConnectorLabel = "connector"
+	// OperationLabel is the label holding the operation name
+	OperationLabel = "operation"
+	// TypeLabel is the label holding the connection type of a storage
+	TypeLabel = "type"
+	// StateLabel is the label holding the state of an application
+	StateLabel = "state"
+)
+
+// Results of operations
+const (
+	SuccessResult = "success"
+	ErrorResult   = "error"
+	RequeueResult = "requeue"
+	HitResult     = "hit"
+	MissResult    = "miss"
+)
+
+// Reasons of data path construction failures
+const (
+	NoDeployedModulesReason    = "no_deployed_modules"
+	UnsupportedInterfaceReason = "unsupported_interface"
+	MissingCapabilityReason    = "missing_capability"
+	NoDataPathReason           = "no_data_path"
+)
+
+// Connector types
+const (
+	DataCatalogConnector   = "datacatalog"
+	PolicyManagerConnector = "policymanager"
+)
+
+// Storage and helm operations
+const (
+	AllocateOperation = "allocate"
+	DeleteOperation   = "delete"
+	InstallOperation  = "install"
+	UpgradeOperation  = "upgrade"
+)
+
+// States of FybrikApplication
+const (
+	ReadyState   = "ready"
+	DenyState    = "deny"
+	ErrorState   = "error"
+	PendingState = "pending"
+)
+
+// ModuleTimeToReady measures the time it takes a module instance to become ready,
+// either after it has been deployed or after it stopped being ready.
+var ModuleTimeToReady = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "module",
+		Name:      "time_to_ready_seconds",
+		Help:      "Time it takes a module instance to become ready",
+		Buckets:   []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	},
+	[]string{ChartLabel},
+)
+
+// ReconcileTotal counts the reconcile cycles per controller and outcome
+var ReconcileTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "reconcile_total",
+		Help:      "Number of reconcile cycles per controller and outcome",
+	},
+	[]string{ControllerLabel, ResultLabel},
+)
+
+// SolveDuration measures the time it takes to construct the data paths of an application
+var SolveDuration = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "solver",
+		Name:      "duration_seconds",
+		Help:      "Time it takes to construct the data paths of an application",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12), //nolint:gomnd
+	},
+)
+
+// SolveFailures counts the failures to construct a data path per reason
+var SolveFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "solver",
+		Name:      "failures_total",
+		Help:      "Number of failures to construct a data path per reason",
+	},
+	[]string{ReasonLabel},
+)
+
+// CSPSolverInvocations counts the invocations of the CSP solver
+var CSPSolverInvocations = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "csp_solver",
+		Name:      "invocations_total",
+		Help:      "Number of CSP solver invocations",
+	},
+)
+
+// CSPSolverTimeouts counts the CSP solver invocations that have not completed the search
+var CSPSolverTimeouts = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "csp_solver",
+		Name:      "timeouts_total",
+		Help:      "Number of CSP solver invocations that reached the time limit",
+	},
+)
+
+// ConnectorRequestDuration measures the latency of connector requests
+var ConnectorRequestDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "connector",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to the data catalog and policy manager connectors",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{ConnectorLabel, OperationLabel},
+)
+
+// ConnectorRequestErrors counts the failed connector requests
+var ConnectorRequestErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "connector",
+		Name:      "request_errors_total",
+		Help:      "Number of failed requests to the data catalog and policy manager connectors",
+	},
+	[]string{ConnectorLabel, OperationLabel},
+)
+
+// StorageOperations counts storage allocations and deletions per connection type and outcome
+var StorageOperations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "storage",
+		Name:      "operations_total",
+		Help:      "Number of storage allocations and deletions per connection type",
+	},
+	[]string{OperationLabel, TypeLabel, ResultLabel},
+)
+
+// HelmOperationDuration measures the duration of helm installs and upgrades
+var HelmOperationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "helm",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of helm release installs and upgrades",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 30, 60, 120, 300},
+	},
+	[]string{OperationLabel, ResultLabel},
+)
+
+// ChartCacheRequests counts the chart requests that are served from the chart cache and those that require a pull
+var ChartCacheRequests = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "helm",
+		Name:      "chart_cache_requests_total",
+		Help:      "Number of chart requests served from the chart cache (hit) or pulled from the registry (miss)",
+	},
+	[]string{ResultLabel},
+)
+
+// ApplicationsDesc describes the number of applications per state
+var ApplicationsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(Namespace, "", "applications"),
+	"Number of FybrikApplications per state",
+	[]string{StateLabel},
+	nil,
+)
+
+// metrics are registered in the controller-runtime registry which is served by the manager
+func init() {
+	metrics.Registry.MustRegister(
+		ModuleTimeToReady,
+		ReconcileTotal,
+		SolveDuration,
+		SolveFailures,
+		CSPSolverInvocations,
+		CSPSolverTimeouts,
+		ConnectorRequestDuration,
+		ConnectorRequestErrors,
+		StorageOperations,
+		HelmOperationDuration,
+		ChartCacheRequests,
+	)
+}
+
+// Result returns the result label value of an operation
+func Result(err error) string {
+	if err != nil {
+		return ErrorResult
+	}
+	return SuccessResult
+}
+
+// ObserveReconcile records the outcome of a reconcile cycle
+func ObserveReconcile(controller string, result reconcile.Result, err error) {
+	outcome := Result(err)
+	if err == nil && (result.Requeue || result.RequeueAfter > 0) {
+		outcome = RequeueResult
+	}
+	ReconcileTotal.WithLabelValues(controller, outcome).Inc()
+}
+
+// ObserveConnectorRequest records the latency and the outcome of a connector request
+func ObserveConnectorRequest(connector, operation string, start time.Time, err error) {
+	ConnectorRequestDuration.WithLabelValues(connector, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		ConnectorRequestErrors.WithLabelValues(connector, operation).Inc()
+	}
+}
+
+// ObserveHelmOperation records the duration and the outcome of a helm operation
+func ObserveHelmOperation(operation string, start time.Time, err error) {
+	HelmOperationDuration.WithLabelValues(operation, Result(err)).Observe(time.Since(start).Seconds())
+}
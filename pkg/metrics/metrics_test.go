@@ -0,0 +1,53 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestObserveReconcile(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	controller := "TestController"
+	ObserveReconcile(controller, reconcile.Result{}, nil)
+	ObserveReconcile(controller, reconcile.Result{RequeueAfter: time.Second}, nil)
+	ObserveReconcile(controller, reconcile.Result{Requeue: true}, errors.New("failure"))
+	g.Expect(testutil.ToFloat64(ReconcileTotal.WithLabelValues(controller, SuccessResult))).To(gomega.Equal(1.0))
+	g.Expect(testutil.ToFloat64(ReconcileTotal.WithLabelValues(controller, RequeueResult))).To(gomega.Equal(1.0))
+	g.Expect(testutil.ToFloat64(ReconcileTotal.WithLabelValues(controller, ErrorResult))).To(gomega.Equal(1.0))
+}
+
+func TestObserveConnectorRequest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	operation := "testOperation"
+	ObserveConnectorRequest(DataCatalogConnector, operation, time.Now(), nil)
+	ObserveConnectorRequest(DataCatalogConnector, operation, time.Now(), errors.New("failure"))
+	g.Expect(testutil.ToFloat64(ConnectorRequestErrors.WithLabelValues(DataCatalogConnector, operation))).To(gomega.Equal(1.0))
+}
+
+func TestApplicationsCollector(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	collector := &applicationsCollector{count: func() (map[string]int, error) {
+		return map[string]int{ReadyState: 2, DenyState: 1}, nil
+	}}
+	expected := `
+# HELP fybrik_applications Number of FybrikApplications per state
+# TYPE fybrik_applications gauge
+fybrik_applications{state="deny"} 1
+fybrik_applications{state="error"} 0
+fybrik_applications{state="pending"} 0
+fybrik_applications{state="ready"} 2
+`
+	g.Expect(testutil.CollectAndCompare(collector, strings.NewReader(expected))).To(gomega.Succeed())
+	g.Expect(RegisterApplicationsCollector(collector.count)).To(gomega.Succeed())
+	// registering the collector again does not fail
+	g.Expect(RegisterApplicationsCollector(collector.count)).To(gomega.Succeed())
+}
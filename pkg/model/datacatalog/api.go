@@ -11,6 +11,11 @@ type GetAssetRequest struct {
 
 	// +kubebuilder:validation:Enum=read;
 	OperationType OperationType `json:"operationType"`
+
+	// +kubebuilder:validation:Optional
+	// Revision of the asset metadata to be returned, as returned in the revision of a previous response.
+	// The latest revision is returned if it is not set
+	Revision string `json:"revision,omitempty"`
 }
 
 type GetAssetResponse struct {
@@ -23,6 +28,9 @@ type GetAssetResponse struct {
 	Credentials string `json:"credentials"`
 	// Additional message to be reported to the user
 	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Revision of the returned asset metadata. Empty if the connector does not keep the history of the asset metadata
+	Revision string `json:"revision,omitempty"`
 }
 
 type CreateAssetRequest struct {
@@ -44,6 +52,11 @@ type CreateAssetRequest struct {
 	// +kubebuilder:validation:Optional
 	// The vault plugin path where the destination data credentials will be stored as kubernetes secrets
 	Credentials string `json:"credentials"`
+
+	// +kubebuilder:validation:Optional
+	// Identifies the write of the asset. The connector returns the asset that has been created for a previous request
+	// with the same key instead of creating a new asset
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
 }
 
 type CreateAssetResponse struct {
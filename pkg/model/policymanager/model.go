@@ -26,4 +26,7 @@ type ResultItem struct {
 	// The policy on which the decision was based
 	Policy string          `json:"policy"`
 	Action taxonomy.Action `json:"action"`
+	// +kubebuilder:validation:Optional
+	// The policy managers that made the decision, set when the decisions of several policy managers are combined
+	Source string `json:"source,omitempty"`
 }
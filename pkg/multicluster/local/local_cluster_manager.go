@@ -26,9 +26,11 @@ func (cm *localClusterManager) GetClusters() ([]multicluster.Cluster, error) {
 	clusters := []multicluster.Cluster{{
 		Name: environment.GetLocalClusterName(),
 		Metadata: multicluster.ClusterMetadata{
-			Region:        environment.GetLocalRegion(),
-			Zone:          environment.GetLocalZone(),
-			VaultAuthPath: environment.GetLocalVaultAuthPath(),
+			Region:          environment.GetLocalRegion(),
+			Zone:            environment.GetLocalZone(),
+			VaultAuthPath:   environment.GetLocalVaultAuthPath(),
+			IngressIPBlocks: multicluster.ParseIPBlocks(environment.GetLocalIngressIPBlocks()),
+			EgressIPBlocks:  multicluster.ParseIPBlocks(environment.GetLocalEgressIPBlocks()),
 		},
 	}}
 	return clusters, nil
@@ -4,6 +4,8 @@
 package multicluster
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -28,6 +30,10 @@ type ClusterMetadata struct {
 	Region        string `json:"region"`
 	Zone          string `json:"zone,omitempty"`
 	VaultAuthPath string `json:"vaultAuthPath,omitempty"`
+	// IngressIPBlocks are the CIDRs of the gateways through which connections from other clusters enter the cluster
+	IngressIPBlocks []string `json:"ingressIPBlocks,omitempty"`
+	// EgressIPBlocks are the CIDRs of the gateways through which connections to other clusters leave the cluster
+	EgressIPBlocks []string `json:"egressIPBlocks,omitempty"`
 }
 
 type Cluster struct {
@@ -39,14 +45,27 @@ func CreateCluster(cm corev1.ConfigMap) Cluster {
 	cluster := Cluster{
 		Name: cm.Data["ClusterName"],
 		Metadata: ClusterMetadata{
-			Region:        cm.Data["Region"],
-			Zone:          cm.Data["Zone"],
-			VaultAuthPath: cm.Data["VaultAuthPath"],
+			Region:          cm.Data["Region"],
+			Zone:            cm.Data["Zone"],
+			VaultAuthPath:   cm.Data["VaultAuthPath"],
+			IngressIPBlocks: ParseIPBlocks(cm.Data["IngressIPBlocks"]),
+			EgressIPBlocks:  ParseIPBlocks(cm.Data["EgressIPBlocks"]),
 		},
 	}
 	return cluster
 }
 
+// ParseIPBlocks returns the CIDRs in a comma separated list
+func ParseIPBlocks(list string) []string {
+	var blocks []string
+	for _, block := range strings.Split(list, ",") {
+		if block = strings.TrimSpace(block); block != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
 // Decode json into runtime.Object, which is a pointer (such as &corev1.ConfigMapList)
 func Decode(json string, scheme *runtime.Scheme, object runtime.Object) error {
 	decoder := serializer.NewCodecFactory(scheme).UniversalDecoder()
@@ -16,6 +16,7 @@
 package optimizer
 
 import (
+	"context"
 	"math"
 	"os"
 	"os/exec"
@@ -24,13 +25,18 @@ import (
 	"emperror.dev/errors"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"fybrik.io/fybrik/pkg/datapath"
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/metrics"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
 const (
 	MaxDataPathDepth = 4
+	TracerName       = "fybrik.io/fybrik/pkg/optimizer"
 )
 
 type Optimizer struct {
@@ -47,7 +53,7 @@ func NewOptimizer(env *datapath.Environment, problemData *datapath.DataInfo, sol
 	return &opt
 }
 
-func (opt *Optimizer) getSolution(pathLength int) (string, error) {
+func (opt *Optimizer) getSolution(ctx context.Context, pathLength int) (solution string, err error) {
 	opt.log.Debug().Msgf("finding solution of length %d", pathLength)
 	modelFile, err := opt.dpc.BuildFzModel(pathLength)
 	if len(modelFile) > 0 {
@@ -63,21 +69,38 @@ func (opt *Optimizer) getSolution(pathLength int) (string, error) {
 		solverArgs = append(solverArgs, strings.Split(additionalArgs, " ")...)
 	}
 	opt.log.Debug().Msgf("Executing %s %v", opt.solverPath, solverArgs)
+	metrics.CSPSolverInvocations.Inc()
+	_, span := tracing.Tracer(TracerName).Start(ctx, "solver",
+		trace.WithAttributes(
+			attribute.String("solver.path", opt.solverPath),
+			attribute.Int("datapath.length", pathLength)))
+	defer func() { tracing.EndSpan(span, err) }()
 	// #nosec G204 -- Avoid "Subprocess launched with variable" error
 	solverSolution, err := exec.Command(opt.solverPath, solverArgs...).Output()
 	if err != nil {
 		return "", errors.Wrapf(err, "error executing %s %s", opt.solverPath, modelFile)
 	}
+	if solverTimedOut(string(solverSolution)) {
+		opt.log.Warn().Msgf("solver %s reached the time limit", opt.solverPath)
+		metrics.CSPSolverTimeouts.Inc()
+	}
 	return string(solverSolution), nil
 }
 
+// solverTimedOut returns true if the solver has reached its time limit without finding a solution
+// or proving that there is none, which FlatZinc solvers indicate by an UNKNOWN status
+func solverTimedOut(solverOutput string) bool {
+	return strings.Contains(solverOutput, "=====UNKNOWN=====")
+}
+
 // The main method to call for finding a legal and optimal data path
 // Attempts short data-paths first, and gradually increases data-path length.
-func (opt *Optimizer) Solve() (datapath.Solution, error) {
+// Every invocation of the solver is traced as a span of the given context.
+func (opt *Optimizer) Solve(ctx context.Context) (datapath.Solution, error) {
 	bestScore := math.NaN()
 	bestSolution := datapath.Solution{}
 	for pathLen := 1; pathLen <= MaxDataPathDepth; pathLen++ {
-		solverSolution, err := opt.getSolution(pathLen)
+		solverSolution, err := opt.getSolution(ctx, pathLen)
 		if err != nil {
 			return datapath.Solution{}, err
 		}
@@ -4,6 +4,7 @@
 package optimizer
 
 import (
+	"context"
 	"os"
 	"testing"
 
@@ -15,7 +16,7 @@ var testLog = logging.LogInit("Optimizer", "Test")
 func TestOptimizer(t *testing.T) {
 	env := getTestEnv()
 	opt := NewOptimizer(env, getDataInfo(env), os.Getenv("CSP_PATH"), &testLog)
-	solution, err := opt.Solve()
+	solution, err := opt.Solve(context.Background())
 	if err != nil {
 		t.Fatalf("Failed solving constraint problem: %v", err)
 	}
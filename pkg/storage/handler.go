@@ -4,12 +4,14 @@
 package main
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	kclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/logging"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/storage/registrator"
@@ -21,6 +23,8 @@ import (
 
 const UnsupportedTypeError string = "unsupported storage type: "
 
+var _ rpc.StorageManagerServer = (*Handler)(nil)
+
 type Handler struct {
 	Client kclient.Client
 	Log    zerolog.Logger
@@ -34,8 +38,53 @@ func NewHandler(client kclient.Client) *Handler {
 	return handler
 }
 
-// allocates storage based on the selected storage account by invoking the specific implementation agent
+// AllocateStorage allocates storage based on the selected storage account by invoking the specific implementation agent
 // returns a Connection object in case of success, and an error - otherwise
+func (r *Handler) AllocateStorage(ctx context.Context,
+	request *storagemanager.AllocateStorageRequest) (*storagemanager.AllocateStorageResponse, error) {
+	r.Log.Info().Msgf("allocateStorage request for %s", request.AccountType)
+	impl, err := registrator.GetAgent(request.AccountType)
+	if err != nil {
+		r.Log.Info().Msg(err.Error())
+		return nil, rpc.NewError(http.StatusNotImplemented, UnsupportedTypeError+string(request.AccountType))
+	}
+	conn, err := impl.AllocateStorage(request, r.Client)
+	if err != nil {
+		r.Log.Info().Msg(err.Error())
+		return nil, rpc.NewError(http.StatusForbidden, err.Error())
+	}
+	logging.LogStructure("allocated storage", &conn, &r.Log, zerolog.InfoLevel, false, true)
+	return &storagemanager.AllocateStorageResponse{Connection: &conn}, nil
+}
+
+// DeleteStorage deletes the existing storage by invoking the specific implementation agent based on the connection type
+func (r *Handler) DeleteStorage(ctx context.Context, request *storagemanager.DeleteStorageRequest) error {
+	impl, err := registrator.GetAgent(request.Connection.Name)
+	if err != nil {
+		r.Log.Info().Msg(err.Error())
+		return rpc.NewError(http.StatusNotImplemented, UnsupportedTypeError+string(request.Connection.Name))
+	}
+	r.Log.Info().Msgf("deleteStorage request for %v", request.Connection)
+	if err = impl.DeleteStorage(request, r.Client); err != nil {
+		r.Log.Info().Msg(err.Error())
+		return rpc.NewError(http.StatusForbidden, err.Error())
+	}
+	return nil
+}
+
+// GetSupportedStorageTypes returns a list of supported connection types
+func (r *Handler) GetSupportedStorageTypes(ctx context.Context) (*storagemanager.GetSupportedStorageTypesResponse, error) {
+	resp := &storagemanager.GetSupportedStorageTypesResponse{ConnectionTypes: registrator.GetRegisteredTypes()}
+	r.Log.Info().Msgf("supported connections: %v", resp)
+	return resp, nil
+}
+
+// abortWithError responds with the status code and the message of an error of an operation
+func abortWithError(c *gin.Context, err error) {
+	code, message := rpc.HTTPStatus(err)
+	c.JSON(code, gin.H{"error": message})
+}
+
 func (r *Handler) allocateStorage(c *gin.Context) {
 	// Parse request
 	var request storagemanager.AllocateStorageRequest
@@ -44,24 +93,14 @@ func (r *Handler) allocateStorage(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Error during ShouldBindJSON in allocateStorage "})
 		return
 	}
-	r.Log.Info().Msgf("allocateStorage request for %s", request.AccountType)
-	impl, err := registrator.GetAgent(request.AccountType)
-	if err != nil {
-		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusNotImplemented, gin.H{"error": UnsupportedTypeError + string(request.AccountType)})
-		return
-	}
-	conn, err := impl.AllocateStorage(&request, r.Client)
+	response, err := r.AllocateStorage(c.Request.Context(), &request)
 	if err != nil {
-		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		abortWithError(c, err)
 		return
 	}
-	logging.LogStructure("allocated storage", &conn, &r.Log, zerolog.InfoLevel, false, true)
-	c.JSON(http.StatusOK, &storagemanager.AllocateStorageResponse{Connection: &conn})
+	c.JSON(http.StatusOK, response)
 }
 
-// deletes the existing storage by invoking the specific implementation agent based on the connection type
 func (r *Handler) deleteStorage(c *gin.Context) {
 	// Parse request
 	var request storagemanager.DeleteStorageRequest
@@ -70,26 +109,18 @@ func (r *Handler) deleteStorage(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Error during ShouldBindJSON in deleteStorage"})
 		return
 	}
-
-	impl, err := registrator.GetAgent(request.Connection.Name)
-	if err != nil {
-		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusNotImplemented, gin.H{"error": UnsupportedTypeError + string(request.Connection.Name)})
-		return
-	}
-	r.Log.Info().Msgf("deleteStorage request for %v", request.Connection)
-	err = impl.DeleteStorage(&request, r.Client)
-	if err != nil {
-		r.Log.Info().Msg(err.Error())
-		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	if err := r.DeleteStorage(c.Request.Context(), &request); err != nil {
+		abortWithError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "OK"})
 }
 
-// return a list of supported connection types
 func (r *Handler) getSupportedStorageTypes(c *gin.Context) {
-	resp := &storagemanager.GetSupportedStorageTypesResponse{ConnectionTypes: registrator.GetRegisteredTypes()}
-	r.Log.Info().Msgf("supported connections: %v", resp)
-	c.JSON(http.StatusOK, resp)
+	response, err := r.GetSupportedStorageTypes(c.Request.Context())
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response)
 }
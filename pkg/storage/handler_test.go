@@ -4,17 +4,22 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
 	"gotest.tools/assert"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
+	storage "fybrik.io/fybrik/pkg/connectors/storagemanager/clients"
 	"fybrik.io/fybrik/pkg/model/storagemanager"
 	"fybrik.io/fybrik/pkg/model/taxonomy"
 )
@@ -43,3 +48,28 @@ func TestSupportedConnections(t *testing.T) {
 		g.Expect(response.ConnectionTypes).To(gomega.ContainElement(taxonomy.ConnectionType("mysql")))
 	})
 }
+
+// test that the storage manager serves the same operations with gRPC
+func TestGRPCStorageManager(t *testing.T) {
+	t.Parallel()
+	g := gomega.NewGomegaWithT(t)
+	client := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	server := grpc.NewServer()
+	rpc.RegisterStorageManagerServer(server, NewHandler(client))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	storageManager, err := storage.NewGRPCStorageManager("grpc://" + listener.Addr().String())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer storageManager.Close()
+
+	response, err := storageManager.GetSupportedStorageTypes(context.Background())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(response.ConnectionTypes).To(gomega.ConsistOf(taxonomy.ConnectionType("s3"), taxonomy.ConnectionType("mysql")))
+
+	// an unsupported storage type is reported as with the REST API
+	_, err = storageManager.AllocateStorage(context.Background(), &storagemanager.AllocateStorageRequest{AccountType: "db2"})
+	g.Expect(err).To(gomega.MatchError(storage.StorageTypeNotSupported))
+}
@@ -3,23 +3,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	"emperror.dev/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 
+	"fybrik.io/fybrik/pkg/connectors/rpc"
 	"fybrik.io/fybrik/pkg/environment"
+	"fybrik.io/fybrik/pkg/tracing"
 )
 
-const ServerPortKey string = "SERVER_PORT"
+const (
+	ServerPortKey string = "SERVER_PORT"
+	serviceName   string = "storage-manager"
+)
 
 // NewRouter returns a new router.
 func NewRouter(handler *Handler) *gin.Engine {
 	router := gin.Default()
+	router.Use(otelgin.Middleware(serviceName))
 	router.POST("/allocateStorage", handler.allocateStorage)
 	router.DELETE("/deleteStorage", handler.deleteStorage)
+	router.POST("/getSupportedStorageTypes", handler.getSupportedStorageTypes)
+	// kept for clients that used GET before it was aligned with the spec
 	router.GET("/getSupportedStorageTypes", handler.getSupportedStorageTypes)
 	return router
 }
@@ -46,6 +56,11 @@ func RunCmd() *cobra.Command {
 				return errors.Wrap(err, "failed to create a Kubernetes client")
 			}
 			handler := NewHandler(client)
+			shutdownTracing, err := tracing.Init(context.Background(), serviceName)
+			if err != nil {
+				return errors.Wrap(err, "failed to initialize tracing")
+			}
+			defer func() { _ = shutdownTracing(context.Background()) }()
 			router := NewRouter(handler)
 			router.Use(gin.Logger())
 			port, err := environment.MustGetEnv(ServerPortKey)
@@ -53,8 +68,26 @@ func RunCmd() *cobra.Command {
 				handler.Log.Err(err).Msg(ServerPortKey + " env var is not defined")
 				return err
 			}
+			grpcPort, err := environment.GetGRPCPort()
+			if err != nil {
+				return errors.Wrap(err, "invalid "+environment.GRPCPortKey)
+			}
 			bindAddress := ":" + port
-			return router.Run(bindAddress)
+			if grpcPort == 0 {
+				return router.Run(bindAddress)
+			}
+
+			// the storage manager serves gRPC in addition to REST if a gRPC port is set
+			grpcServer, err := rpc.NewServer(serviceName, &handler.Log)
+			if err != nil {
+				return err
+			}
+			defer grpcServer.Stop()
+			rpc.RegisterStorageManagerServer(grpcServer, handler)
+			errs := make(chan error, 2)
+			go func() { errs <- rpc.Serve(grpcServer, fmt.Sprintf(":%d", grpcPort)) }()
+			go func() { errs <- router.Run(bindAddress) }()
+			return <-errs
 		},
 	}
 	return cmd
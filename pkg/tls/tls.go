@@ -219,3 +219,34 @@ func GetClientTLSConfig(clientLog *zerolog.Logger) (*tls.Config, error) {
 
 	return tlsConfig, nil
 }
+
+// GetClientTLSConfigFromFiles returns the client config for a tls connection to a connector that uses its own
+// certificates. The CA certificate and the client certificate with its private key are optional.
+func GetClientTLSConfigFromFiles(clientLog *zerolog.Logger, caCertFile, clientCertFile, clientKeyFile string) (*tls.Config, error) {
+	//nolint:gosec // ignore G402: TLS MinVersion too low
+	tlsConfig := &tls.Config{
+		MinVersion: environment.GetMinTLSVersion(clientLog),
+	}
+	if caCertFile != "" {
+		caCert, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("error in AppendCertsFromPEM trying to load CA certificate " + caCertFile)
+		}
+	}
+	certProvided, err := isCertificateProvided(clientCertFile != "", clientKeyFile != "")
+	if err != nil {
+		return nil, err
+	}
+	if certProvided {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(clientCertFile, clientKeyFile); err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
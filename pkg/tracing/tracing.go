@@ -0,0 +1,105 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"emperror.dev/errors"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// Supported exporters of trace spans
+const (
+	NoExporter   = "none"
+	OTLPExporter = "otlp"
+	FileExporter = "file"
+)
+
+// ShutdownFunc flushes the remaining spans and releases the exporter
+type ShutdownFunc func(ctx context.Context) error
+
+// Init configures the global tracer provider of a fybrik component according to the environment.
+// TRACING_EXPORTER selects the exporter:
+// - otlp exports the spans over http to the collector defined by the standard OTEL_EXPORTER_OTLP_* variables
+// - file writes the spans in JSON format to TRACING_FILE
+// - none (default) does not export the spans
+// The W3C trace context propagator is used in any case, so that the trace context is passed between the components.
+func Init(ctx context.Context, serviceName string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	var exporter sdktrace.SpanExporter
+	var err error
+	closeFunc := func() error { return nil }
+	switch environment.GetTracingExporter() {
+	case NoExporter:
+		return func(context.Context) error { return nil }, nil
+	case OTLPExporter:
+		exporter, err = otlptracehttp.New(ctx)
+	case FileExporter:
+		var file *os.File
+		file, err = os.OpenFile(filepath.Clean(environment.GetTracingFile()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open the tracing file")
+		}
+		closeFunc = file.Close
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, errors.Errorf("unsupported tracing exporter %s", environment.GetTracingExporter())
+	}
+	if err != nil {
+		_ = closeFunc()
+		return nil, errors.Wrap(err, "failed to create a trace exporter")
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closeErr := closeFunc(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// Tracer returns a named tracer of the global tracer provider
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// EndSpan records the error, if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapClient instruments the http client, so that a span is created for each request
+// and the trace context is propagated to the server in W3C trace context headers
+func WrapClient(client *http.Client) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	client.Transport = otelhttp.NewTransport(transport)
+	return client
+}
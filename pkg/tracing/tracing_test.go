@@ -0,0 +1,104 @@
+// Copyright 2023 IBM Corp.
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/onsi/gomega"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+
+	"fybrik.io/fybrik/pkg/environment"
+)
+
+// exportedSpan holds the fields of a span written by the file exporter that are checked by the tests
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Status struct {
+		Code string
+	}
+}
+
+func readSpans(g *gomega.WithT, path string) map[string]exportedSpan {
+	file, err := os.Open(path)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer file.Close()
+	spans := map[string]exportedSpan{}
+	decoder := json.NewDecoder(file)
+	for {
+		span := exportedSpan{}
+		err := decoder.Decode(&span)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		spans[span.Name] = span
+	}
+	return spans
+}
+
+func TestPropagation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	path := filepath.Join(t.TempDir(), "spans.json")
+	t.Setenv(environment.TracingExporterKey, FileExporter)
+	t.Setenv(environment.TracingFileKey, path)
+	shutdown, err := Init(context.Background(), "test")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(otelgin.Middleware("test-server"))
+	router.POST("/getAssetInfo", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, span := Tracer("test").Start(context.Background(), "reconcile")
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/getAssetInfo", http.NoBody)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	response, err := WrapClient(&http.Client{}).Do(request)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	response.Body.Close()
+	EndSpan(span, errors.New("failure"))
+	g.Expect(shutdown(context.Background())).To(gomega.Succeed())
+
+	spans := readSpans(g, path)
+	g.Expect(spans).To(gomega.HaveKey("reconcile"))
+	g.Expect(spans).To(gomega.HaveKey("HTTP POST"))
+	g.Expect(spans).To(gomega.HaveKey("/getAssetInfo"))
+	parent := spans["reconcile"]
+	client := spans["HTTP POST"]
+	serverSpan := spans["/getAssetInfo"]
+	g.Expect(parent.Status.Code).To(gomega.Equal("Error"))
+	// the client span is a child of the reconcile span
+	g.Expect(client.Parent.SpanID).To(gomega.Equal(parent.SpanContext.SpanID))
+	// the server span continues the trace received in the request headers
+	g.Expect(serverSpan.SpanContext.TraceID).To(gomega.Equal(parent.SpanContext.TraceID))
+	g.Expect(serverSpan.Parent.SpanID).To(gomega.Equal(client.SpanContext.SpanID))
+}
+
+func TestUnsupportedExporter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	t.Setenv(environment.TracingExporterKey, "unknown")
+	_, err := Init(context.Background(), "test")
+	g.Expect(err).To(gomega.HaveOccurred())
+}
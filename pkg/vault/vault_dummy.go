@@ -6,16 +6,23 @@ package vault
 import (
 	"encoding/json"
 	"errors"
+	"strconv"
+	"time"
 )
 
+// DummyLeaseDuration is the duration of the leases created by the Dummy implementation
+const DummyLeaseDuration = time.Hour
+
 // Dummy implementation for testing
 type Dummy struct {
 	values map[string]string
+	leases map[string]string
+	issued int
 }
 
 // NewDummyConnection returns a new Dummy object
 func NewDummyConnection() *Dummy {
-	return &Dummy{values: make(map[string]string)}
+	return &Dummy{values: make(map[string]string), leases: make(map[string]string)}
 }
 
 func (c *Dummy) LinkPolicyToIdentity(identity, policyName, boundedNamespace, serviceAccount, auth, ttl string) error {
@@ -67,3 +74,43 @@ func (c *Dummy) AddSecretFromStruct(path string, creds interface{}) error {
 	c.values[path] = string(bytes)
 	return nil
 }
+
+// CreateLease issues a lease on the secret stored in the given path
+func (c *Dummy) CreateLease(path string) (*Lease, error) {
+	s, hasKey := c.values[path]
+	if !hasKey {
+		return nil, errors.New("could not find key")
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		return nil, err
+	}
+	c.issued++
+	id := path + "/" + strconv.Itoa(c.issued)
+	c.leases[id] = path
+	return &Lease{ID: id, Duration: DummyLeaseDuration, Renewable: true, Data: data}, nil
+}
+
+func (c *Dummy) RenewLease(leaseID string, increment time.Duration) (*Lease, error) {
+	if _, hasKey := c.leases[leaseID]; !hasKey {
+		return nil, errors.New("could not find lease")
+	}
+	if increment == 0 {
+		increment = DummyLeaseDuration
+	}
+	return &Lease{ID: leaseID, Duration: increment, Renewable: true}, nil
+}
+
+func (c *Dummy) RevokeLease(leaseID string) error {
+	delete(c.leases, leaseID)
+	return nil
+}
+
+// Leases returns the identifiers of the leases that have not been revoked
+func (c *Dummy) Leases() []string {
+	leases := []string{}
+	for id := range c.leases {
+		leases = append(leases, id)
+	}
+	return leases
+}
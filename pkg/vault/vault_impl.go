@@ -207,3 +207,49 @@ func (c *Connection) Mount(path string) error {
 
 	return nil
 }
+
+// CreateLease reads a dynamic secret, e.g. database/creds/<role>, and returns the lease of the issued credentials
+func (c *Connection) CreateLease(path string) (*Lease, error) {
+	logicalClient := c.Client.Logical()
+	if logicalClient == nil {
+		return nil, errors.New("no logical client received when creating a lease in vault")
+	}
+	secret, err := logicalClient.Read(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating a lease for %s", path)
+	}
+	if secret == nil || secret.LeaseID == "" {
+		return nil, fmt.Errorf("no lease received from vault for %s", path)
+	}
+	return &Lease{
+		ID:        secret.LeaseID,
+		Duration:  time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable: secret.Renewable,
+		Data:      secret.Data,
+	}, nil
+}
+
+// RenewLease extends the lease by the given increment.
+// The returned lease may be shorter than requested if it reaches its maximal TTL.
+func (c *Connection) RenewLease(leaseID string, increment time.Duration) (*Lease, error) {
+	secret, err := c.Client.Sys().Renew(leaseID, int(increment.Seconds()))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error renewing lease %s", leaseID)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("no lease received from vault when renewing %s", leaseID)
+	}
+	return &Lease{
+		ID:        secret.LeaseID,
+		Duration:  time.Duration(secret.LeaseDuration) * time.Second,
+		Renewable: secret.Renewable,
+	}, nil
+}
+
+// RevokeLease revokes the lease, invalidating the credentials issued with it
+func (c *Connection) RevokeLease(leaseID string) error {
+	if err := c.Client.Sys().Revoke(leaseID); err != nil {
+		return errors.Wrapf(err, "error revoking lease %s", leaseID)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package vault
 
 import (
 	"os"
+	"time"
 )
 
 // Interface provides vault functionality
@@ -18,6 +19,18 @@ type Interface interface {
 	GetSecret(vaultPath string) (string, error)
 	AddSecret(path string, credentials map[string]interface{}) error
 	AddSecretFromStruct(path string, creds interface{}) error
+	CreateLease(path string) (*Lease, error)
+	RenewLease(leaseID string, increment time.Duration) (*Lease, error)
+	RevokeLease(leaseID string) error
+}
+
+// Lease holds the credentials issued by a dynamic secrets engine, e.g. database or aws,
+// which are valid for the duration of the lease
+type Lease struct {
+	ID        string
+	Duration  time.Duration
+	Renewable bool
+	Data      map[string]interface{}
 }
 
 // InitConnection creates a new connection to vault.
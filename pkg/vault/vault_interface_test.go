@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -50,4 +51,20 @@ func TestCredentialManagerInterface(t *testing.T) {
 	Log(t, "Delete secret", err)
 	_, err = conn.GetSecret("fybrik/test/123/456")
 	assert.NotNil(t, err)
+
+	// Lease dynamic credentials
+	err = conn.AddSecret("database/creds/readonly", credentials)
+	assert.Nil(t, err)
+	lease, err := conn.CreateLease("database/creds/readonly")
+	assert.Nil(t, err)
+	Log(t, "Create lease", err)
+	assert.Equal(t, credentials, lease.Data)
+	_, err = conn.RenewLease(lease.ID, time.Hour)
+	assert.Nil(t, err)
+	Log(t, "Renew lease", err)
+	err = conn.RevokeLease(lease.ID)
+	assert.Nil(t, err)
+	Log(t, "Revoke lease", err)
+	_, err = conn.RenewLease(lease.ID, time.Hour)
+	assert.NotNil(t, err)
 }
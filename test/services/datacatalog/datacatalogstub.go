@@ -48,7 +48,7 @@ func main() {
 		}
 
 		dataCatalog := mockup.NewTestCatalog()
-		dataCatalogResp, err := dataCatalog.GetAssetInfo(&dataCatalogReq, creds)
+		dataCatalogResp, err := dataCatalog.GetAssetInfo(c.Request.Context(), &dataCatalogReq, creds)
 		if err != nil {
 			if err.Error() == dc.AssetIDNotFound {
 				c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
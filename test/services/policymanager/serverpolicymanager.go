@@ -49,7 +49,7 @@ func main() {
 
 		policyManagerReq := constructPolicyManagerRequest(string(input))
 		policyManager := &mockup.MockPolicyManager{}
-		policyManagerResp, err := policyManager.GetPoliciesDecisions(policyManagerReq, creds)
+		policyManagerResp, err := policyManager.GetPoliciesDecisions(c.Request.Context(), policyManagerReq, creds)
 		if err != nil {
 			c.String(http.StatusInternalServerError, "Error in GetPoliciesDecisions!")
 			return